@@ -1,60 +1,420 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	appsvc "minigo/internal/application/service"
+	"minigo/internal/infrastructure/auth"
 	"minigo/internal/infrastructure/config"
+	"minigo/internal/infrastructure/health"
 	"minigo/internal/infrastructure/id"
 	"minigo/internal/infrastructure/logging"
+	"minigo/internal/infrastructure/mail"
+	"minigo/internal/infrastructure/metrics"
+	"minigo/internal/infrastructure/migrate"
+	"minigo/internal/infrastructure/ratelimit"
+	infrarepo "minigo/internal/infrastructure/repository"
+	"minigo/internal/infrastructure/sms"
+	"minigo/internal/infrastructure/tracing"
 	httpx "minigo/internal/interfaces/http"
+	"minigo/migrations"
 
-	"github.com/spf13/viper"
+	"github.com/sirupsen/logrus"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/driver/pgdriver"
 	"github.com/uptrace/bun/extra/bundebug"
 )
 
-// initConfig 初始化配置和日志
-func initConfig() {
-	config.Init()
-	logging.Init(config.GetLogLevel())
+// initConfig 加载配置并初始化日志，配置无效时直接退出
+func initConfig() *config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+	samplingLevel, err := logrus.ParseLevel(cfg.Log.SamplingLevel)
+	if err != nil {
+		log.Fatalf("invalid LOG_SAMPLING_LEVEL: %v", err)
+	}
+	rotate := logging.RotateOptions{
+		MaxSizeMB:  cfg.Log.MaxSizeMB,
+		Interval:   cfg.Log.RotateInterval,
+		MaxAge:     cfg.Log.MaxAge,
+		MaxBackups: cfg.Log.MaxBackups,
+		Compress:   cfg.Log.Compress,
+	}
+	if err = logging.Init(logging.Options{
+		Level:          cfg.Log.Level,
+		RedactFields:   cfg.Log.RedactFields,
+		RedactPatterns: cfg.Log.RedactPatterns,
+		Sampling: logging.SamplingOptions{
+			Initial:    cfg.Log.SamplingInitial,
+			Thereafter: cfg.Log.SamplingThereafter,
+			Tick:       cfg.Log.SamplingTick,
+			MinLevel:   samplingLevel,
+		},
+		Output: logging.SinkOptions{
+			Stdout: cfg.Log.Stdout,
+			File:   cfg.Log.File,
+			Rotate: rotate,
+		},
+		Access: logging.SinkOptions{
+			Stdout: cfg.Log.AccessStdout,
+			File:   cfg.Log.AccessFile,
+			Rotate: rotate,
+		},
+	}); err != nil {
+		log.Fatalf("failed to init logging: %v", err)
+	}
+	return cfg
 }
 
 // connectDB 连接数据库并返回bun.DB实例
-func connectDB() (*bun.DB, error) {
-	dsn := viper.GetString("DB_DSN")
-	if dsn == "" {
-		dsn = config.GetDBDsn()
-	}
-
-	hsqldb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn)))
+func connectDB(cfg *config.Config) (*bun.DB, error) {
+	hsqldb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(cfg.DB.ConnString())))
 	db := bun.NewDB(hsqldb, pgdialect.New())
-	if config.IsDevEnv() {
-		db.AddQueryHook(bundebug.NewQueryHook(bundebug.WithVerbose(true)))
+	db.AddQueryHook(metrics.NewQueryHook())
+	db.AddQueryHook(tracing.NewQueryHook())
+	if err := metrics.RegisterDBStats(hsqldb, "postgres"); err != nil {
+		return nil, err
+	}
+	if cfg.IsDev() {
+		// SQL 中内联了参数值，输出前脱敏密码哈希、手机号等
+		db.AddQueryHook(bundebug.NewQueryHook(
+			bundebug.WithVerbose(true),
+			bundebug.WithWriter(logging.RedactWriter(os.Stderr)),
+		))
 	}
 
 	return db, nil
 }
 
+// autoMigrate 启动时执行未应用的迁移
+func autoMigrate(db *bun.DB) error {
+	migrator, err := migrate.New(db.DB, migrations.FS)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(context.Background())
+	for _, m := range applied {
+		logging.L().WithFields(map[string]interface{}{
+			"version": m.Version,
+			"name":    m.Name,
+		}).Info("migration_applied")
+	}
+	return err
+}
+
+// newRateLimitStore 按 RATE_LIMIT_STORE 选择限流存储，多实例部署时使用 postgres 或 redis 共享限流状态
+func newRateLimitStore(cfg config.RateLimitConfig, db *bun.DB) (ratelimit.RateLimiter, error) {
+	switch cfg.Store {
+	case "postgres":
+		return ratelimit.NewPGStore(db), nil
+	case "redis":
+		return ratelimit.NewRedisStore(cfg.RedisURL)
+	default:
+		return ratelimit.NewMemoryStore(), nil
+	}
+}
+
+// newSMSSender 按 SMS_PROVIDER 创建短信发送器
+func newSMSSender(cfg config.SMSConfig) (sms.Sender, error) {
+	switch cfg.Provider {
+	case "file":
+		return sms.NewFileSender(cfg.FilePath)
+	case "webhook":
+		return sms.NewWebhookSender(cfg.WebhookURL, cfg.WebhookToken), nil
+	default:
+		return sms.NewConsoleSender(), nil
+	}
+}
+
+// newMailSender 按 MAIL_PROVIDER 创建邮件发送器
+func newMailSender(cfg config.MailConfig) (mail.Sender, error) {
+	switch cfg.Provider {
+	case "file":
+		return mail.NewFileSender(cfg.FilePath)
+	case "smtp":
+		return mail.NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From), nil
+	default:
+		return mail.NewConsoleSender(), nil
+	}
+}
+
+// buildHealthChecks 注册 /readyz 和 /status 使用的健康检查
+func buildHealthChecks(cfg *config.Config, db *bun.DB) (*health.Registry, error) {
+	migrator, err := migrate.New(db.DB, migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+	checks := health.NewRegistry()
+	checks.Register(health.Check{Name: "postgres", Critical: true, Fn: health.DatabaseCheck(db)})
+	// /status 不鉴权，迁移状态缓存一分钟，避免探针频繁查询数据库
+	checks.Register(health.Check{Name: "migrations", Fn: health.Cached(time.Minute, health.MigrationCheck(migrator))})
+	checks.Register(health.Check{Name: "oss", Fn: health.OSSConfigCheck(cfg.OSS)})
+	return checks, nil
+}
+
 func main() {
-	initConfig()
+	// 子命令：server secrets ...（在加载配置之前执行，配置中的密钥可能尚未写入）
+	if len(os.Args) > 1 && os.Args[1] == "secrets" {
+		os.Exit(runSecrets(os.Args[2:]))
+	}
+
+	cfg := initConfig()
+
+	// 子命令：server migrate up|down|status|create|baseline
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		code := runMigrate(cfg, os.Args[2:])
+		_ = logging.Close()
+		os.Exit(code)
+	}
+
 	id.Init()
+	tokens, err := auth.LoadTokenIssuer(cfg.JWT)
+	if err != nil {
+		log.Fatalf("failed to load jwt signing keys: %v", err)
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatalf("failed to init tracing: %v", err)
+	}
 
-	db, err := connectDB()
+	db, err := connectDB(cfg)
 	if err != nil {
 		log.Fatalf("failed to connect database: %v", err)
 	}
 
-	r := httpx.BuildRouter(db)
+	if cfg.DB.AutoMigrate {
+		if err = autoMigrate(db); err != nil {
+			log.Fatalf("failed to migrate database: %v", err)
+		}
+	}
 
-	port := viper.GetString("PORT")
-	if port == "" {
-		port = config.GetPort()
+	checks, err := buildHealthChecks(cfg, db)
+	if err != nil {
+		log.Fatalf("failed to build health checks: %v", err)
 	}
 
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("server exited: %v", err)
+	// 配置热更新：日志级别在此订阅，CORS、限流和功能开关由路由订阅
+	watcher := config.NewWatcher(cfg)
+	watcher.Subscribe(func(old, cfg *config.Config) {
+		if old.Log.Level == cfg.Log.Level {
+			return
+		}
+		// 通过管理接口设置的级别优先，重启后才恢复为 LOG_LEVEL
+		if logging.LevelOverridden() {
+			logging.L().WithFields(map[string]interface{}{
+				"level":      logging.Level(),
+				"configured": cfg.Log.Level,
+			}).Warn("log_level_reload_skipped")
+			return
+		}
+		_ = logging.SetLevel(cfg.Log.Level)
+	})
+
+	rateLimitStore, err := newRateLimitStore(cfg.RateLimit, db)
+	if err != nil {
+		log.Fatalf("failed to create rate limit store: %v", err)
+	}
+
+	smsSender, err := newSMSSender(cfg.SMS)
+	if err != nil {
+		log.Fatalf("failed to create sms sender: %v", err)
+	}
+
+	mailSender, err := newMailSender(cfg.Mail)
+	if err != nil {
+		log.Fatalf("failed to create mail sender: %v", err)
+	}
+
+	// 短信和邮件在后台发送，关闭时排空
+	deliveries := appsvc.NewDeliveryQueue()
+
+	r, err := httpx.BuildRouter(watcher, db, tokens, checks, rateLimitStore, smsSender, mailSender, deliveries)
+	if err != nil {
+		log.Fatalf("failed to build router: %v", err)
+	}
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.HTTP.Port,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+
+	// 指标使用独立的监听地址，不经过公网入口
+	var metricsSrv *http.Server
+	if cfg.HTTP.MetricsEnabled() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsSrv = &http.Server{
+			Addr:              cfg.HTTP.MetricsAddr,
+			Handler:           mux,
+			ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		}
+	}
+
+	// 后台任务共享同一个上下文，关闭时统一取消并等待退出
+	bgCtx, cancelBackground := context.WithCancel(context.Background())
+	var bg sync.WaitGroup
+	runBackground(bgCtx, &bg, func(ctx context.Context) {
+		ratelimit.RunCleanup(ctx, rateLimitStore, 10*time.Minute)
+	})
+	// 定期清理过期的一次性令牌
+	resetTokens := infrarepo.NewBunPasswordResetTokenRepository(db)
+	runBackground(bgCtx, &bg, func(ctx context.Context) {
+		runPurge(ctx, "password_reset_tokens", time.Hour, func(ctx context.Context) error {
+			return resetTokens.PurgeExpired(ctx, time.Now())
+		})
+	})
+	// 保留 24 小时内发送的验证码，用于 VERIFY_CODE_DAILY_LIMIT 计数
+	verificationCodes := infrarepo.NewBunVerificationCodeRepository(db)
+	runBackground(bgCtx, &bg, func(ctx context.Context) {
+		runPurge(ctx, "verification_codes", time.Hour, func(ctx context.Context) error {
+			return verificationCodes.PurgeSentBefore(ctx, time.Now().Add(-24*time.Hour))
+		})
+	})
+	// 失败窗口和最长延迟都已过去的计数不再影响登录
+	loginThrottles := infrarepo.NewBunLoginThrottleRepository(db)
+	runBackground(bgCtx, &bg, func(ctx context.Context) {
+		runPurge(ctx, "login_throttles", time.Hour, func(ctx context.Context) error {
+			return loginThrottles.PurgeStale(ctx, time.Now().Add(-max(cfg.Login.FailureWindow, cfg.Login.DelayMax)))
+		})
+	})
+	// 已吊销或全部过期的令牌族保留 24 小时，期间重放仍按吊销处理
+	refreshTokens := infrarepo.NewBunRefreshTokenRepository(db)
+	runBackground(bgCtx, &bg, func(ctx context.Context) {
+		runPurge(ctx, "refresh_tokens", time.Hour, func(ctx context.Context) error {
+			return refreshTokens.PurgeExpired(ctx, time.Now().Add(-24*time.Hour))
+		})
+	})
+	twoFactors := infrarepo.NewBunTwoFactorRepository(db)
+	runBackground(bgCtx, &bg, func(ctx context.Context) {
+		runPurge(ctx, "two_factor_challenges", time.Hour, func(ctx context.Context) error {
+			return twoFactors.PurgeExpiredChallenges(ctx, time.Now())
+		})
+	})
+	if cfg.JWT.RevocationStore != "memory" {
+		revocations := auth.NewPGRevocationStore(db)
+		runBackground(bgCtx, &bg, func(ctx context.Context) {
+			runPurge(ctx, "revoked_tokens", time.Hour, revocations.PurgeExpired)
+		})
+	}
+	runBackground(bgCtx, &bg, func(ctx context.Context) {
+		if err := watcher.Run(ctx); err != nil {
+			logging.L().WithError(err).Error("config_watch_failed")
+		}
+	})
+
+	// 监听退出信号
+	sigCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logging.L().WithField("addr", srv.Addr).Info("server_started")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+		close(serveErr)
+	}()
+	if metricsSrv != nil {
+		go func() {
+			logging.L().WithField("addr", metricsSrv.Addr).Info("metrics_server_started")
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logging.L().WithError(err).Error("metrics_server_exited")
+			}
+		}()
 	}
+
+	exitCode := 0
+	select {
+	case err = <-serveErr:
+		if err != nil {
+			logging.L().WithError(err).Error("server_exited")
+			exitCode = 1
+		}
+	case <-sigCtx.Done():
+		logging.L().Info("shutdown_signal_received")
+	}
+	stop()
+
+	// readiness 先失败，负载均衡摘除流量后再排空请求
+	checks.SetShuttingDown()
+	if delay := cfg.HTTP.ShutdownDrainDelay; delay > 0 {
+		time.Sleep(delay)
+	}
+
+	// 在限定时间内排空进行中的请求
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logging.L().WithError(err).Error("server_shutdown_failed")
+	}
+	if metricsSrv != nil {
+		_ = metricsSrv.Shutdown(shutdownCtx)
+	}
+
+	cancelBackground()
+	bg.Wait()
+
+	// 发完已排队的验证码短信和重置邮件，邮件发送需要查询数据库，之后才能关闭连接池
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancelDrain()
+	if err := deliveries.Close(drainCtx); err != nil {
+		logging.L().WithError(err).Error("delivery_drain_failed")
+	}
+
+	if err := db.Close(); err != nil {
+		logging.L().WithError(err).Error("db_close_failed")
+	}
+	// 刷新尚未导出的 span
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logging.L().WithError(err).Error("tracing_shutdown_failed")
+	}
+	logging.L().Info("server_stopped")
+	_ = logging.Close()
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
+
+// runPurge 每隔 interval 执行一次 purge 清理过期数据，ctx 取消时返回
+func runPurge(ctx context.Context, name string, interval time.Duration, purge func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := purge(ctx); err != nil {
+				logging.L().WithError(err).WithField("target", name).Warn("purge_failed")
+			}
+		}
+	}
+}
+
+// runBackground 启动一个随 ctx 取消而退出的后台任务
+func runBackground(ctx context.Context, wg *sync.WaitGroup, fn func(ctx context.Context)) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fn(ctx)
+	}()
 }
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"minigo/internal/infrastructure/config"
+	"minigo/internal/infrastructure/migrate"
+	"minigo/migrations"
+)
+
+const migrateUsage = `Usage: server migrate <command>
+
+Commands:
+  up                 apply all pending migrations
+  down [N]           roll back the last N migrations (default 1)
+  status             show applied and pending migrations
+  create NAME        create NNN_NAME.up.sql / NNN_NAME.down.sql in MIGRATIONS_DIR
+  baseline VERSION   mark migrations up to VERSION as applied without running them`
+
+// runMigrate 执行 migrate 子命令，返回进程退出码
+func runMigrate(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	// create 只操作源码目录，不需要连接数据库
+	if args[0] == "create" {
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		upFile, downFile, err := migrate.Create(cfg.DB.MigrationsDir, args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "create migration failed: %v\n", err)
+			return 1
+		}
+		fmt.Printf("created %s\ncreated %s\n", upFile, downFile)
+		return 0
+	}
+
+	db, err := connectDB(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db.DB, migrations.FS)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load migrations failed: %v\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		printMigrations("applied", applied)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate up failed: %v\n", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				fmt.Fprintf(os.Stderr, "invalid step count %q\n", args[1])
+				return 2
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		printMigrations("rolled back", rolledBack)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate down failed: %v\n", err)
+			return 1
+		}
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate status failed: %v\n", err)
+			return 1
+		}
+		for _, m := range status {
+			state := "pending"
+			if m.AppliedAt != nil {
+				state = "applied " + m.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%03d_%-30s %s\n", m.Version, m.Name, state)
+		}
+	case "baseline":
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid version %q\n", args[1])
+			return 2
+		}
+		marked, err := migrator.Baseline(ctx, version)
+		printMigrations("marked", marked)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate baseline failed: %v\n", err)
+			return 1
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
+
+func printMigrations(action string, list []*migrate.Migration) {
+	for _, m := range list {
+		fmt.Printf("%s %03d_%s\n", action, m.Version, m.Name)
+	}
+}
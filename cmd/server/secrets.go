@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"minigo/internal/infrastructure/config"
+)
+
+const secretsUsage = `Usage: server secrets <command>
+
+Commands:
+  keygen      print a new random master key for SECRETS_MASTER_KEY
+  list        list the secret names stored in SECRETS_FILE
+  get NAME    print the decrypted value of NAME
+  set NAME    read a value from stdin and store it encrypted as NAME
+  rotate      re-encrypt SECRETS_FILE with SECRETS_NEW_MASTER_KEY (generated when empty)`
+
+// runSecrets 执行 secrets 子命令，返回进程退出码
+func runSecrets(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, secretsUsage)
+		return 2
+	}
+
+	// keygen 不需要打开密钥文件
+	if args[0] == "keygen" {
+		key, err := config.GenerateMasterKey()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "generate master key failed: %v\n", err)
+			return 1
+		}
+		fmt.Println(key)
+		return 0
+	}
+
+	store, err := config.OpenSecrets()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open secrets failed: %v\n", err)
+		return 1
+	}
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		for _, name := range store.Names() {
+			fmt.Println(name)
+		}
+	case args[0] == "get" && len(args) == 2:
+		value, ok, err := store.Get(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "get secret failed: %v\n", err)
+			return 1
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "secret %s not found\n", args[1])
+			return 1
+		}
+		fmt.Println(value)
+	case args[0] == "set" && len(args) == 2:
+		if !isSecretKey(args[1]) {
+			fmt.Fprintf(os.Stderr, "unknown secret %s, expected one of: %s\n", args[1], strings.Join(config.SecretKeys(), ", "))
+			return 2
+		}
+		// 从标准输入读取，避免密钥出现在命令行历史和进程列表中
+		value, err := readSecretValue(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "read value failed: %v\n", err)
+			return 1
+		}
+		if err = store.Set(args[1], value); err == nil {
+			err = store.Save()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "set secret failed: %v\n", err)
+			return 1
+		}
+		fmt.Printf("stored %s\n", args[1])
+	case args[0] == "rotate" && len(args) == 1:
+		return rotateSecrets(store)
+	default:
+		fmt.Fprintln(os.Stderr, secretsUsage)
+		return 2
+	}
+	return 0
+}
+
+// rotateSecrets 使用新主密钥重新加密；未指定时生成并输出新主密钥
+func rotateSecrets(store *config.SecretStore) int {
+	encoded := strings.TrimSpace(os.Getenv("SECRETS_NEW_MASTER_KEY"))
+	generated := encoded == ""
+	if generated {
+		var err error
+		if encoded, err = config.GenerateMasterKey(); err != nil {
+			fmt.Fprintf(os.Stderr, "generate master key failed: %v\n", err)
+			return 1
+		}
+	}
+	newKey, err := config.ParseMasterKey(encoded)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "SECRETS_NEW_MASTER_KEY: %v\n", err)
+		return 1
+	}
+	if err = store.Rotate(newKey); err == nil {
+		err = store.Save()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rotate secrets failed: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintln(os.Stderr, "secrets re-encrypted; update SECRETS_MASTER_KEY before restarting")
+	if generated {
+		fmt.Println(encoded)
+	}
+	return 0
+}
+
+func isSecretKey(name string) bool {
+	for _, key := range config.SecretKeys() {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readSecretValue 读取全部输入并去掉末尾换行
+func readSecretValue(r io.Reader) (string, error) {
+	raw, err := io.ReadAll(bufio.NewReader(r))
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimRight(string(raw), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("empty value")
+	}
+	return value, nil
+}
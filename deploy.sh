@@ -8,7 +8,9 @@ ACTION=$1
 APP_START_TIMEOUT=20             # 等待应用启动的时间
 APP_PORT=8808                    # 应用端口
 APP_HOME=/usr/local/boc/backend # 从package.tgz中解压出来的包放到这个目录下
-APP_OUT=/var/log/boc/app.log
+APP_LOG_DIR=/var/log/boc
+# 应用日志和访问日志由程序自行切割，APP_OUT 只接收启动失败、panic 等控制台输出
+APP_OUT=${APP_LOG_DIR}/console.log
 
 # 进入应用目录
 cd $APP_HOME
@@ -44,7 +46,8 @@ health_check() {
 }
 start_application() {
   echo "starting $APP_NAME"
-  nohup ./$APP_NAME >>${APP_OUT} 2>&1 &
+  LOG_STDOUT=false LOG_FILE=${APP_LOG_DIR}/app.log LOG_ACCESS_FILE=${APP_LOG_DIR}/access.log \
+    nohup ./$APP_NAME >>${APP_OUT} 2>&1 &
   echo "started $APP_NAME"
 }
 
@@ -57,19 +60,26 @@ stop_application() {
   fi
 
   echo "stop $APP_NAME"
+  # 先发送 SIGTERM，应用会在 SHUTDOWN_TIMEOUT 内排空请求后退出
+  kill -TERM $checkpid
   times=60
   for e in $(seq 60); do
     sleep 1
     COSTTIME=$(($times - $e))
     checkpid=$(ps -ef | grep ${APP_NAME} | grep -v grep | grep -v 'deploy.sh' | awk '{print$2}')
     if [[ $checkpid ]]; then
-      kill -9 $checkpid
       echo -e "\r        -- stopping $APP_NAME lasts $(expr $COSTTIME) seconds."
     else
       echo -e "\r$APP_NAME has exited"
       break
     fi
   done
+  # 超时仍未退出则强制结束
+  checkpid=$(ps -ef | grep ${APP_NAME} | grep -v grep | grep -v 'deploy.sh' | awk '{print$2}')
+  if [[ $checkpid ]]; then
+    echo "$APP_NAME did not exit in $times seconds, killing"
+    kill -9 $checkpid
+  fi
   echo ""
 }
 start() {
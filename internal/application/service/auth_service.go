@@ -2,40 +2,534 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"minigo/internal/domain/entity"
+	apperrors "minigo/internal/domain/errors"
 	"minigo/internal/domain/repository"
 	"minigo/internal/infrastructure/auth"
 	"minigo/internal/infrastructure/config"
+	"minigo/internal/infrastructure/id"
+	"minigo/internal/infrastructure/logging"
+	"minigo/internal/infrastructure/metrics"
+	"minigo/internal/infrastructure/tx"
+	"minigo/pkg/utils"
+
+	"github.com/google/uuid"
 )
 
 // AuthService provides authentication operations.
 type AuthService struct {
-	userRepo repository.UserRepository
+	userRepo         repository.UserRepository
+	roleRepo         repository.RoleRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	tokens           *auth.TokenIssuer
+	revocations      auth.RevocationStore
+	loginThrottles   repository.LoginThrottleRepository
+	throttle         *loginThrottle
+	twoFactors       repository.TwoFactorRepository
+	twoFactor        *twoFactor
+	verification     *VerificationService
+	passwords        *PasswordPolicyService
+	txManager        *tx.Manager
+	accessTTL        time.Duration
+	refreshTTL       time.Duration
+	challengeTTL     time.Duration
+	maxAttempts      int // 每个登录挑战允许的验证次数
+}
+
+func NewAuthService(
+	users repository.UserRepository,
+	roles repository.RoleRepository,
+	refreshTokens repository.RefreshTokenRepository,
+	tokens *auth.TokenIssuer,
+	revocations auth.RevocationStore,
+	loginThrottles repository.LoginThrottleRepository,
+	twoFactors repository.TwoFactorRepository,
+	verification *VerificationService,
+	passwords *PasswordPolicyService,
+	txManager *tx.Manager,
+	secrets *auth.SecretBox,
+	jwtConfig config.JWTConfig,
+	loginConfig config.LoginConfig,
+	twoFactorConfig config.TwoFactorConfig,
+) *AuthService {
+	return &AuthService{
+		userRepo:         users,
+		roleRepo:         roles,
+		refreshTokenRepo: refreshTokens,
+		tokens:           tokens,
+		revocations:      revocations,
+		loginThrottles:   loginThrottles,
+		throttle:         newLoginThrottle(loginThrottles, loginConfig),
+		twoFactors:       twoFactors,
+		twoFactor:        newTwoFactor(twoFactors, secrets, twoFactorConfig),
+		verification:     verification,
+		passwords:        passwords,
+		txManager:        txManager,
+		accessTTL:        jwtConfig.ExpireDuration,
+		refreshTTL:       jwtConfig.RefreshExpireDuration,
+		challengeTTL:     twoFactorConfig.ChallengeTTL,
+		maxAttempts:      twoFactorConfig.MaxAttempts,
+	}
+}
+
+// TokenPair 访问令牌与刷新令牌
+type TokenPair struct {
+	AccessToken     string
+	RefreshToken    string
+	ExpiresIn       time.Duration // 访问令牌有效期
+	PasswordExpired bool          // 密码已过期，需先修改密码
+}
+
+// LoginChallenge 密码验证通过后仍需完成的两步验证
+type LoginChallenge struct {
+	Token              string
+	ExpiresIn          time.Duration
+	EnrollmentRequired bool // 角色要求两步验证但用户尚未绑定验证器
+}
+
+// LoginResult 登录结果，Tokens 和 Challenge 有且仅有一个不为空
+type LoginResult struct {
+	Tokens    *TokenPair
+	Challenge *LoginChallenge
 }
 
-func NewAuthService(users repository.UserRepository) *AuthService {
-	return &AuthService{userRepo: users}
+// TwoFactorLoginResult 两步验证完成后的令牌，首次绑定时附带恢复码
+type TwoFactorLoginResult struct {
+	Tokens        *TokenPair
+	RecoveryCodes []string
 }
 
-// Login validates credentials and returns JWT token and user info.
-func (s *AuthService) Login(ctx context.Context, phone, password string) (string, error) {
+// Login validates credentials and returns a new token pair, or a challenge when two-factor login is required.
+// 手机号不存在与密码错误返回相同的错误；同一手机号或 IP 连续失败后延迟或锁定，期间返回 LoginThrottledError。
+func (s *AuthService) Login(ctx context.Context, phone, password, ip string) (*LoginResult, error) {
 	var (
-		err  error
-		user *entity.User
+		err      error
+		user     *entity.User
+		now      = time.Now()
+		subjects = []string{phoneSubject(phone), ipSubject(ip)}
 	)
-	if user, err = s.userRepo.GetByPhone(ctx, phone); err != nil {
-		return "", ErrUserNotFound
+	if err = s.throttle.check(ctx, subjects, now); err != nil {
+		metrics.RecordLogin("throttled")
+		return nil, err
+	}
+
+	if user, err = s.userRepo.GetByPhone(ctx, phone); err != nil && !errors.Is(err, apperrors.ErrResourceNotFound) {
+		metrics.RecordLogin("error")
+		return nil, err
 	}
 	// Verify password
-	if err = user.CheckPassword(password); err != nil {
-		return "", ErrInvalidCredentials
+	reason := ""
+	switch {
+	case user == nil:
+		utils.BcryptCheck(password, dummyPasswordHash())
+		reason = "user_not_found"
+	case user.CheckPassword(password) != nil:
+		reason = "invalid_credentials"
+	}
+	if reason != "" {
+		metrics.RecordLogin(reason)
+		if err = s.txManager.InTx(ctx, func(txCtx context.Context) error {
+			return s.throttle.recordFailure(txCtx, subjects, ip, now)
+		}); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+	if user.Status == entity.StatusDisabled {
+		metrics.RecordLogin("user_disabled")
+		return nil, ErrUserDisabled
+	}
+	return s.completeLogin(ctx, user, now)
+}
+
+// LoginWithCode 使用短信验证码登录，验证码错误同样计入手机号和 IP 的失败次数
+func (s *AuthService) LoginWithCode(ctx context.Context, phone, code, ip string) (*LoginResult, error) {
+	var (
+		err      error
+		user     *entity.User
+		now      = time.Now()
+		subjects = []string{phoneSubject(phone), ipSubject(ip)}
+	)
+	if err = s.throttle.check(ctx, subjects, now); err != nil {
+		metrics.RecordLogin("throttled")
+		return nil, err
+	}
+
+	if err = s.verification.Verify(ctx, phone, entity.VerifyPurposeLogin, code); err != nil {
+		if !errors.Is(err, ErrInvalidVerificationCode) {
+			metrics.RecordLogin("error")
+			return nil, err
+		}
+		metrics.RecordLogin("invalid_verification_code")
+		if err = s.txManager.InTx(ctx, func(txCtx context.Context) error {
+			return s.throttle.recordFailure(txCtx, subjects, ip, now)
+		}); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidVerificationCode
+	}
+	// 验证码只发给已注册的手机号，校验通过后用户不存在说明期间被删除
+	if user, err = s.userRepo.GetByPhone(ctx, phone); err != nil {
+		if errors.Is(err, apperrors.ErrResourceNotFound) {
+			return nil, ErrInvalidVerificationCode
+		}
+		metrics.RecordLogin("error")
+		return nil, err
+	}
+	if user.Status == entity.StatusDisabled {
+		metrics.RecordLogin("user_disabled")
+		return nil, ErrUserDisabled
+	}
+	return s.completeLogin(ctx, user, now)
+}
+
+// completeLogin 第一步验证通过后签发令牌，每次登录开启一个新的令牌族；
+// 需要两步验证时改为签发登录挑战，失败计数在完成第二步后清除
+func (s *AuthService) completeLogin(ctx context.Context, user *entity.User, now time.Time) (*LoginResult, error) {
+	var (
+		err       error
+		pair      *TokenPair
+		challenge *LoginChallenge
+	)
+	if err = s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		if challenge, err = s.newChallenge(txCtx, user, now); err != nil || challenge != nil {
+			return err
+		}
+		pair, err = s.issueTokenPair(txCtx, user, uuid.NewString())
+		return err
+	}); err != nil {
+		metrics.RecordLogin("error")
+		return nil, err
+	}
+	if challenge != nil {
+		return &LoginResult{Challenge: challenge}, nil
+	}
+	s.resetPhoneThrottle(ctx, user.Phone)
+	metrics.RecordLogin("")
+	return &LoginResult{Tokens: pair}, nil
+}
+
+// EnrollTwoFactor 角色要求两步验证而用户尚未绑定时，凭登录挑战生成待验证的 TOTP 密钥
+func (s *AuthService) EnrollTwoFactor(ctx context.Context, challengeToken string) (*TOTPSetup, error) {
+	var setup *TOTPSetup
+	if err := s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		_, user, err := s.getChallenge(txCtx, challengeToken, time.Now())
+		if err != nil {
+			return err
+		}
+		setup, err = s.twoFactor.setup(txCtx, user)
+		return err
+	}); err != nil {
+		return nil, err
 	}
-	// Generate JWT with correct user role. TODO
-	userRole := entity.RoleUser
-	token, err := auth.GenerateToken(user.ID, userRole, config.GetJWTExpireDuration())
+	return setup, nil
+}
+
+// VerifyTwoFactor 校验登录挑战的验证码（或恢复码）并签发令牌，首次绑定时同时启用两步验证并返回恢复码。
+// 验证失败计入挑战的尝试次数和登录失败计数。
+func (s *AuthService) VerifyTwoFactor(ctx context.Context, challengeToken, code, ip string) (*TwoFactorLoginResult, error) {
+	var (
+		err    error
+		result *TwoFactorLoginResult
+		phone  string
+		failed bool
+		now    = time.Now()
+	)
+
+	if err = s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		challenge, user, err := s.getChallenge(txCtx, challengeToken, now)
+		if err != nil {
+			return err
+		}
+		phone = user.Phone
+		subjects := []string{phoneSubject(phone), ipSubject(ip)}
+		if err = s.throttle.check(txCtx, subjects, now); err != nil {
+			return err
+		}
+		totp, err := s.twoFactors.GetTOTPForUpdate(txCtx, user.ID)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrResourceNotFound) {
+				return ErrTwoFactorSetupRequired
+			}
+			return err
+		}
+
+		ok, err := s.twoFactor.verify(txCtx, totp, code, now)
+		if err != nil {
+			return err
+		}
+		// 失败计数需要提交，因此不在此处返回错误
+		if !ok {
+			failed = true
+			if err = s.twoFactors.IncrementChallengeAttempts(txCtx, challenge.ID); err != nil {
+				return err
+			}
+			return s.throttle.recordFailure(txCtx, subjects, ip, now)
+		}
+
+		result = &TwoFactorLoginResult{}
+		if !totp.IsEnabled() {
+			if result.RecoveryCodes, err = s.twoFactor.enable(txCtx, totp, now); err != nil {
+				return err
+			}
+		}
+		if err = s.twoFactors.MarkChallengeUsed(txCtx, challenge.ID, now); err != nil {
+			return err
+		}
+		result.Tokens, err = s.issueTokenPair(txCtx, user, uuid.NewString())
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if failed {
+		metrics.RecordLogin("invalid_two_factor_code")
+		return nil, ErrInvalidTwoFactorCode
+	}
+	s.resetPhoneThrottle(ctx, phone)
+	metrics.RecordLogin("")
+	return result, nil
+}
+
+// newChallenge 已启用 TOTP 或任一角色要求两步验证时签发登录挑战，否则返回 nil
+func (s *AuthService) newChallenge(ctx context.Context, user *entity.User, now time.Time) (*LoginChallenge, error) {
+	totp, err := s.twoFactors.GetTOTP(ctx, user.ID)
+	if err != nil && !errors.Is(err, apperrors.ErrResourceNotFound) {
+		return nil, err
+	}
+	enabled := err == nil && totp.IsEnabled()
+	roles, err := s.roleRepo.ListByUserID(ctx, user.ID)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if !enabled && !requiresTwoFactor(roles) {
+		return nil, nil
+	}
+
+	token, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.twoFactors.CreateChallenge(ctx, &entity.TwoFactorChallenge{
+		ID:        id.NextID(),
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: now.Add(s.challengeTTL),
+	}); err != nil {
+		return nil, err
+	}
+	return &LoginChallenge{
+		Token:              token,
+		ExpiresIn:          s.challengeTTL,
+		EnrollmentRequired: !enabled,
+	}, nil
+}
+
+// getChallenge 按明文令牌查找可用的登录挑战（加锁）及其用户
+func (s *AuthService) getChallenge(ctx context.Context, challengeToken string, now time.Time) (*entity.TwoFactorChallenge, *entity.User, error) {
+	challenge, err := s.twoFactors.GetChallengeByHashForUpdate(ctx, auth.HashOpaqueToken(challengeToken))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrResourceNotFound) {
+			return nil, nil, ErrInvalidTwoFactorChallenge
+		}
+		return nil, nil, err
+	}
+	if !challenge.IsUsable(now, s.maxAttempts) {
+		return nil, nil, ErrInvalidTwoFactorChallenge
+	}
+	user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, nil, ErrInvalidTwoFactorChallenge
+	}
+	if user.Status == entity.StatusDisabled {
+		return nil, nil, ErrUserDisabled
+	}
+	return challenge, user, nil
+}
+
+// resetPhoneThrottle 登录成功只清除手机号的计数，IP 的计数不能通过登录自己的账号重置
+func (s *AuthService) resetPhoneThrottle(ctx context.Context, phone string) {
+	if err := s.loginThrottles.Delete(ctx, phoneSubject(phone)); err != nil {
+		logging.FromContext(ctx).WithError(err).Warn("login_throttle_reset_failed")
+	}
+}
+
+// UnlockLoginParams 解锁登录参数，Phone 和 IP 至少指定一个
+type UnlockLoginParams struct {
+	Phone      string
+	IP         string
+	OperatorID int64
+}
+
+// UnlockLogin 清除手机号和/或 IP 的连续失败计数与锁定，并记录解锁事件
+func (s *AuthService) UnlockLogin(ctx context.Context, params UnlockLoginParams) error {
+	var subjects []string
+	if params.Phone != "" {
+		subjects = append(subjects, phoneSubject(params.Phone))
+	}
+	if params.IP != "" {
+		subjects = append(subjects, ipSubject(params.IP))
+	}
+	if len(subjects) == 0 {
+		return ErrUnlockTargetRequired
+	}
+	return s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		return s.throttle.unlock(txCtx, subjects, params.OperatorID)
+	})
+}
+
+// ListLockoutEventsParams 登录锁定记录查询参数，Phone 和 IP 同时指定时按 Phone 查询
+type ListLockoutEventsParams struct {
+	Phone string
+	IP    string
+	Page  int
+	Size  int
+}
+
+// ListLockoutEvents 分页查询登录锁定与解锁记录
+func (s *AuthService) ListLockoutEvents(ctx context.Context, params ListLockoutEventsParams) ([]*entity.LoginLockoutEvent, int, error) {
+	filter := repository.LockoutEventFilter{
+		Offset: (params.Page - 1) * params.Size,
+		Limit:  params.Size,
+	}
+	switch {
+	case params.Phone != "":
+		filter.Subject = phoneSubject(params.Phone)
+	case params.IP != "":
+		filter.Subject = ipSubject(params.IP)
+	}
+	return s.loginThrottles.ListEvents(ctx, filter)
+}
+
+// Refresh rotates the refresh token and returns a new token pair.
+// 已轮换过的令牌再次出现时视为被盗用，吊销整个令牌族。
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	var (
+		err      error
+		pair     *TokenPair
+		reused   bool
+		disabled bool
+	)
+
+	if err = s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		token, err := s.getRefreshToken(txCtx, refreshToken)
+		if err != nil {
+			return err
+		}
+		if token.IsRevoked() || token.IsExpired(time.Now()) {
+			return ErrInvalidRefreshToken
+		}
+		// 重放检测：吊销操作需要提交，因此不在此处返回错误
+		if token.IsUsed() {
+			reused = true
+			return s.refreshTokenRepo.RevokeFamily(txCtx, token.FamilyID)
+		}
+
+		user, err := s.userRepo.GetByID(txCtx, token.UserID)
+		if err != nil {
+			return ErrInvalidRefreshToken
+		}
+		if user.Status == entity.StatusDisabled {
+			disabled = true
+			return s.refreshTokenRepo.RevokeFamily(txCtx, token.FamilyID)
+		}
+
+		if err = s.refreshTokenRepo.MarkUsed(txCtx, token.ID, time.Now()); err != nil {
+			return err
+		}
+		pair, err = s.issueTokenPair(txCtx, user, token.FamilyID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+	if disabled {
+		return nil, ErrUserDisabled
+	}
+	return pair, nil
+}
+
+// Logout revokes the token family the refresh token belongs to.
+// accessToken 为请求携带的访问令牌（可为空），有效且属于同一用户时一并吊销，退出后立即失效而不必等到过期。
+func (s *AuthService) Logout(ctx context.Context, refreshToken, accessToken string) error {
+	var access *auth.Claims
+	if accessToken != "" {
+		access, _ = s.tokens.ParseToken(accessToken)
+	}
+	return s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		token, err := s.getRefreshToken(txCtx, refreshToken)
+		if err != nil {
+			return err
+		}
+		if err = s.refreshTokenRepo.RevokeFamily(txCtx, token.FamilyID); err != nil {
+			return err
+		}
+		if access == nil || access.UserID != token.UserID {
+			return nil
+		}
+		return s.revocations.RevokeToken(txCtx, access)
+	})
+}
+
+// getRefreshToken 按明文令牌查找记录（加锁）
+func (s *AuthService) getRefreshToken(ctx context.Context, refreshToken string) (*entity.RefreshToken, error) {
+	token, err := s.refreshTokenRepo.GetByHashForUpdate(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrResourceNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
 	}
 	return token, nil
 }
+
+// issueTokenPair 签发访问令牌，并在指定令牌族中保存新的刷新令牌
+func (s *AuthService) issueTokenPair(ctx context.Context, user *entity.User, familyID string) (*TokenPair, error) {
+	// 令牌携带主角色和权限编码
+	roles, err := s.roleRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.roleRepo.ListPermissionCodesByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	// 密码过期时令牌带 pwdExpired 声明，PasswordExpiry 中间件据此只放行修改密码等接口
+	passwordExpired := s.passwords.Expired(user, time.Now())
+	accessToken, err := s.tokens.GenerateTokenWithClaims(auth.Claims{
+		UserID:          user.ID,
+		UserRole:        entity.PrimaryRole(roles),
+		Permissions:     permissions,
+		PasswordExpired: passwordExpired,
+	}, s.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.refreshTokenRepo.Create(ctx, &entity.RefreshToken{
+		ID:        id.NextID(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:     accessToken,
+		RefreshToken:    refreshToken,
+		ExpiresIn:       s.accessTTL,
+		PasswordExpired: passwordExpired,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"minigo/internal/domain/entity"
+	"minigo/internal/infrastructure/auth"
+	"minigo/internal/infrastructure/config"
+	"minigo/pkg/utils"
+)
+
+// testTokens 测试使用的 HS256 访问令牌签发器
+var testTokens = auth.NewTokenIssuer([]byte("test-secret"), nil)
+
+func TestLoginDoesNotRevealRegistration(t *testing.T) {
+	ctx := context.Background()
+	throttles := &memoryLoginThrottles{throttles: make(map[string]*entity.LoginThrottle)}
+	user := &entity.User{ID: 1, Phone: "13800000001", Password: utils.BcryptHash("Right-pass-1")}
+	svc := NewAuthService(newMemoryUsers(user), nil, &memoryRefreshTokens{}, testTokens, auth.NewMemoryRevocationStore(), throttles, nil, nil, nil,
+		newTestTxManager(), nil, config.JWTConfig{}, config.LoginConfig{
+			PhoneFreeAttempts: 10,
+			PhoneMaxFailures:  10,
+			IPFreeAttempts:    10,
+			IPMaxFailures:     10,
+			FailureWindow:     time.Hour,
+		}, config.TwoFactorConfig{})
+	dummyPasswordHash()
+
+	login := func(phone string) (time.Duration, error) {
+		start := time.Now()
+		_, err := svc.Login(ctx, phone, "Wrong-pass-1", "10.0.0.1")
+		return time.Since(start), err
+	}
+	wrongPassword, err := login("13800000001")
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Expected ErrInvalidCredentials for a wrong password, got %v", err)
+	}
+	unknownPhone, err := login("13900000000")
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Expected ErrInvalidCredentials for an unknown phone, got %v", err)
+	}
+	// 未注册的手机号同样执行一次 bcrypt，耗时与密码错误相当；不执行时只有微秒级
+	if unknownPhone < wrongPassword/4 {
+		t.Fatalf("Expected a dummy bcrypt for an unknown phone, took %s against %s", unknownPhone, wrongPassword)
+	}
+	for _, subject := range []string{phoneSubject("13800000001"), phoneSubject("13900000000")} {
+		if throttles.throttles[subject] == nil || throttles.throttles[subject].Failures != 1 {
+			t.Fatalf("Expected one failure counted for %s", subject)
+		}
+	}
+}
+
+func TestLogout(t *testing.T) {
+	ctx := context.Background()
+	refreshTokens := &memoryRefreshTokens{}
+	revocations := auth.NewMemoryRevocationStore()
+	svc := NewAuthService(newMemoryUsers(), nil, refreshTokens, testTokens, revocations, nil, nil, nil, nil,
+		newTestTxManager(), nil, config.JWTConfig{}, config.LoginConfig{}, config.TwoFactorConfig{})
+
+	issue := func(userID int64) (string, *entity.RefreshToken) {
+		token, tokenHash, err := auth.NewOpaqueToken()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		record := &entity.RefreshToken{
+			ID:        int64(len(refreshTokens.tokens) + 1),
+			UserID:    userID,
+			FamilyID:  token,
+			TokenHash: tokenHash,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+		refreshTokens.tokens = append(refreshTokens.tokens, record)
+		return token, record
+	}
+	accessToken := func(userID int64) (string, *auth.Claims) {
+		token, err := testTokens.GenerateToken(userID, entity.RoleUser, nil, time.Minute)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		claims, err := testTokens.ParseToken(token)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return token, claims
+	}
+
+	t.Run("revokes the refresh family and the access token", func(t *testing.T) {
+		token, record := issue(1)
+		access, claims := accessToken(1)
+		if err := svc.Logout(ctx, token, access); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if record.RevokedAt == nil {
+			t.Fatal("Expected refresh token revoked")
+		}
+		if revoked, _ := revocations.IsRevoked(ctx, claims); !revoked {
+			t.Fatal("Expected access token revoked")
+		}
+	})
+
+	t.Run("ignores an access token of another user", func(t *testing.T) {
+		token, _ := issue(1)
+		access, claims := accessToken(2)
+		if err := svc.Logout(ctx, token, access); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if revoked, _ := revocations.IsRevoked(ctx, claims); revoked {
+			t.Fatal("Expected access token of another user kept")
+		}
+	})
+
+	t.Run("works without an access token", func(t *testing.T) {
+		token, record := issue(1)
+		if err := svc.Logout(ctx, token, ""); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if record.RevokedAt == nil {
+			t.Fatal("Expected refresh token revoked")
+		}
+	})
+}
@@ -45,23 +45,6 @@ func (v *CommonValidator) ValidatePhone(phone string) error {
 	return nil
 }
 
-// ValidatePassword 验证密码强度
-func (v *CommonValidator) ValidatePassword(password string) error {
-	if password == "" {
-		return errors.New("密码不能为空")
-	}
-
-	if len(password) < 6 {
-		return errors.New("密码长度不能少于6位")
-	}
-
-	if len(password) > 20 {
-		return errors.New("密码长度不能超过20位")
-	}
-
-	return nil
-}
-
 // ValidateUsername 验证用户名
 func (v *CommonValidator) ValidateUsername(username string) error {
 	if username == "" {
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"minigo/internal/infrastructure/logging"
+)
+
+const (
+	// deliveryTimeout 后台发送一条短信或邮件的超时时间
+	deliveryTimeout = 30 * time.Second
+	// deliveryWorkers 同时进行的发送数上限
+	deliveryWorkers = 8
+	// deliveryQueueSize 等待发送的队列长度，队列满时丢弃新的发送并记录日志
+	deliveryQueueSize = 1024
+)
+
+// errDeliveryQueueFull 队列已满或已关闭，本次发送被丢弃
+var errDeliveryQueueFull = errors.New("delivery queue is full or closed")
+
+// DeliveryQueue 在后台发送短信和邮件，接口不等待发送结果。
+// 是否真正发送取决于手机号、邮箱是否已注册，同步发送会让响应时间和发送失败的错误暴露注册状态。
+// 由固定数量的 worker 发送，进程退出前调用 Close 发完已入队的消息（验证码、重置令牌已在事务中提交）。
+type DeliveryQueue struct {
+	mu      sync.RWMutex
+	closed  bool
+	jobs    chan delivery
+	workers sync.WaitGroup
+	pending sync.WaitGroup
+}
+
+type delivery struct {
+	ctx  context.Context
+	send func(ctx context.Context)
+}
+
+// NewDeliveryQueue 创建后台发送队列并启动 worker
+func NewDeliveryQueue() *DeliveryQueue {
+	q := &DeliveryQueue{jobs: make(chan delivery, deliveryQueueSize)}
+	for i := 0; i < deliveryWorkers; i++ {
+		q.workers.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+func (q *DeliveryQueue) work() {
+	defer q.workers.Done()
+	for job := range q.jobs {
+		ctx, cancel := context.WithTimeout(job.ctx, deliveryTimeout)
+		job.send(ctx)
+		cancel()
+		q.pending.Done()
+	}
+}
+
+// run 将 send 加入后台队列。ctx 的取消不影响发送（保留日志字段等上下文值），超时为 deliveryTimeout，
+// 发送失败由 send 自行记录日志；队列已满或已关闭时丢弃并记录 delivery_dropped。
+func (q *DeliveryQueue) run(ctx context.Context, send func(ctx context.Context)) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if !q.closed {
+		q.pending.Add(1)
+		select {
+		case q.jobs <- delivery{ctx: context.WithoutCancel(ctx), send: send}:
+			return
+		default:
+			q.pending.Done()
+		}
+	}
+	logging.FromContext(ctx).WithError(errDeliveryQueueFull).Error("delivery_dropped")
+}
+
+// wait 等待已入队的发送完成
+func (q *DeliveryQueue) wait() {
+	q.pending.Wait()
+}
+
+// Close 停止接收新的发送，等待已入队的发送完成；ctx 到期时返回 ctx.Err()，未发完的消息随进程退出丢失
+func (q *DeliveryQueue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
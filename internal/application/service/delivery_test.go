@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDeliveryQueue(t *testing.T) {
+	t.Run("Close waits for queued deliveries", func(t *testing.T) {
+		q := NewDeliveryQueue()
+		var sent atomic.Int32
+		for i := 0; i < deliveryWorkers*2; i++ {
+			q.run(context.Background(), func(ctx context.Context) {
+				time.Sleep(10 * time.Millisecond)
+				sent.Add(1)
+			})
+		}
+		if err := q.Close(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := sent.Load(); got != deliveryWorkers*2 {
+			t.Fatalf("Expected %d deliveries, got %d", deliveryWorkers*2, got)
+		}
+	})
+
+	t.Run("drops deliveries after Close", func(t *testing.T) {
+		q := NewDeliveryQueue()
+		if err := q.Close(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		called := false
+		q.run(context.Background(), func(ctx context.Context) { called = true })
+		q.wait()
+		if called {
+			t.Fatal("Expected delivery dropped after Close")
+		}
+	})
+
+	t.Run("Close gives up when ctx expires", func(t *testing.T) {
+		q := NewDeliveryQueue()
+		release := make(chan struct{})
+		defer close(release)
+		q.run(context.Background(), func(ctx context.Context) { <-release })
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := q.Close(ctx); err != context.DeadlineExceeded {
+			t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+}
@@ -16,6 +16,59 @@ var (
 	ErrUserPaymentCheck     = apperrors.NewBusinessError("USER_007", "用户收款方式已存在")
 	ErrUserPaymentNotFound  = apperrors.NewNotFoundError("USER_008", "收款方式不存在")
 	ErrInvalidReferrerPhone = apperrors.NewBusinessError("USER_009", "邀请人不存在")
+	ErrEmailExists          = apperrors.NewBusinessError("USER_010", "邮箱已被使用")
+)
+
+// 角色权限相关错误
+var (
+	ErrRoleNotFound     = apperrors.NewNotFoundError("ROLE_001", "角色不存在")
+	ErrUserRoleNotFound = apperrors.NewNotFoundError("ROLE_002", "用户未分配该角色")
+)
+
+// 令牌相关错误
+var (
+	ErrInvalidRefreshToken = apperrors.NewAuthError("TOKEN_001", "刷新令牌无效或已过期")
+	ErrRefreshTokenReused  = apperrors.NewAuthError("TOKEN_002", "刷新令牌已失效，请重新登录")
+)
+
+// 登录保护相关错误
+var (
+	ErrTooManyLoginAttempts = apperrors.NewTooManyRequestsError("LOGIN_001", "登录失败次数过多，请稍后再试")
+	ErrUnlockTargetRequired = apperrors.NewValidationError("LOGIN_002", "请指定要解锁的手机号或IP")
+)
+
+// 两步验证相关错误
+var (
+	ErrInvalidTwoFactorCode      = apperrors.NewBusinessError("TFA_001", "验证码错误")
+	ErrInvalidTwoFactorChallenge = apperrors.NewAuthError("TFA_002", "两步验证已过期，请重新登录")
+	ErrTwoFactorAlreadyEnabled   = apperrors.NewBusinessError("TFA_003", "两步验证已启用")
+	ErrTwoFactorNotEnabled       = apperrors.NewBusinessError("TFA_004", "两步验证未启用")
+	ErrTwoFactorSetupRequired    = apperrors.NewBusinessError("TFA_005", "请先生成两步验证密钥")
+	ErrTwoFactorRequiredByRole   = apperrors.NewBusinessError("TFA_006", "所属角色要求启用两步验证，不能关闭")
+)
+
+// 短信验证码相关错误
+var (
+	ErrInvalidVerificationCode     = apperrors.NewBusinessError("VERIFY_001", "验证码错误或已过期")
+	ErrVerificationCodeTooFrequent = apperrors.NewTooManyRequestsError("VERIFY_002", "验证码发送过于频繁，请稍后再试")
+	ErrVerificationCodeDailyLimit  = apperrors.NewTooManyRequestsError("VERIFY_003", "今日验证码发送次数已达上限")
+	ErrInvalidVerifyPurpose        = apperrors.NewValidationError("VERIFY_005", "验证码用途无效")
+	ErrInvalidPhone                = apperrors.NewValidationError("VERIFY_006", "手机号格式不正确")
+	ErrVerificationCodeRequired    = apperrors.NewValidationError("VERIFY_007", "请输入短信验证码")
+	ErrPhoneVerificationRequired   = apperrors.NewBusinessError("VERIFY_008", "修改手机号需要短信验证")
+)
+
+// 找回密码相关错误
+var (
+	ErrResetTargetRequired     = apperrors.NewValidationError("RESET_001", "请输入手机号或邮箱")
+	ErrInvalidEmail            = apperrors.NewValidationError("RESET_002", "邮箱格式不正确")
+	ErrInvalidResetToken       = apperrors.NewBusinessError("RESET_003", "重置链接无效或已过期")
+	ErrResetCredentialRequired = apperrors.NewValidationError("RESET_004", "请提供短信验证码或重置令牌")
+)
+
+// 密码策略相关错误，违反的具体规则见 AppError.Fields（PWD_002 ~ PWD_008）
+var (
+	ErrPasswordPolicy = apperrors.NewValidationError("PWD_001", "密码不符合安全要求")
 )
 
 // 工具函数
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"minigo/internal/domain/entity"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/config"
+	"minigo/internal/infrastructure/id"
+	"minigo/internal/infrastructure/logging"
+	"minigo/pkg/utils"
+)
+
+// LoginThrottledError 登录因连续失败被延迟或锁定，RetryAfter 为可再次尝试前的等待时间。
+// errors.Is(err, ErrTooManyLoginAttempts) 成立。
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("%s (retry after %s)", ErrTooManyLoginAttempts.Error(), e.RetryAfter)
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+// loginLimit 同一计数对象在延迟和锁定前允许的连续失败次数
+type loginLimit struct {
+	freeAttempts int
+	maxFailures  int
+}
+
+// loginThrottle 按手机号和 IP 统计连续失败次数：超过 freeAttempts 后逐次翻倍延迟，
+// 达到 maxFailures 时锁定。手机号不存在时同样计数，调用方无法据此判断手机号是否已注册。
+type loginThrottle struct {
+	repo          repository.LoginThrottleRepository
+	phone         loginLimit
+	ip            loginLimit
+	delayBase     time.Duration
+	delayMax      time.Duration
+	lockout       time.Duration
+	failureWindow time.Duration
+}
+
+func newLoginThrottle(repo repository.LoginThrottleRepository, cfg config.LoginConfig) *loginThrottle {
+	return &loginThrottle{
+		repo:          repo,
+		phone:         loginLimit{freeAttempts: cfg.PhoneFreeAttempts, maxFailures: cfg.PhoneMaxFailures},
+		ip:            loginLimit{freeAttempts: cfg.IPFreeAttempts, maxFailures: cfg.IPMaxFailures},
+		delayBase:     cfg.DelayBase,
+		delayMax:      cfg.DelayMax,
+		lockout:       cfg.LockoutDuration,
+		failureWindow: cfg.FailureWindow,
+	}
+}
+
+func phoneSubject(phone string) string { return "phone:" + phone }
+
+func ipSubject(ip string) string { return "ip:" + ip }
+
+// limitFor 按计数对象的类型返回对应的限制
+func (t *loginThrottle) limitFor(subject string) loginLimit {
+	if strings.HasPrefix(subject, "ip:") {
+		return t.ip
+	}
+	return t.phone
+}
+
+// delay 第 failures 次连续失败后需要等待的时间
+func (t *loginThrottle) delay(failures int, limit loginLimit) time.Duration {
+	over := failures - limit.freeAttempts
+	if over <= 0 {
+		return 0
+	}
+	d := t.delayBase
+	for i := 1; i < over && d < t.delayMax; i++ {
+		d *= 2
+	}
+	return min(d, t.delayMax)
+}
+
+// blockedUntil 计数对象在此时间之前不能尝试登录，零值表示不受限制
+func (t *loginThrottle) blockedUntil(throttle *entity.LoginThrottle, now time.Time) time.Time {
+	var until time.Time
+	if throttle.IsLocked(now) {
+		until = *throttle.LockedUntil
+	}
+	if delayed := throttle.LastFailedAt.Add(t.delay(throttle.Failures, t.limitFor(throttle.Subject))); delayed.After(until) {
+		until = delayed
+	}
+	return until
+}
+
+// check 任一计数对象处于延迟或锁定期时返回 LoginThrottledError
+func (t *loginThrottle) check(ctx context.Context, subjects []string, now time.Time) error {
+	throttles, err := t.repo.ListBySubjects(ctx, subjects)
+	if err != nil {
+		return err
+	}
+	var until time.Time
+	for _, throttle := range throttles {
+		if u := t.blockedUntil(throttle, now); u.After(until) {
+			until = u
+		}
+	}
+	if until.After(now) {
+		return &LoginThrottledError{RetryAfter: until.Sub(now)}
+	}
+	return nil
+}
+
+// recordFailure 记录一次失败，达到上限的计数对象被锁定并写入锁定记录
+func (t *loginThrottle) recordFailure(ctx context.Context, subjects []string, ip string, now time.Time) error {
+	for _, subject := range subjects {
+		throttle, err := t.repo.RecordFailure(ctx, subject, now, now.Add(-t.failureWindow))
+		if err != nil {
+			return err
+		}
+		if throttle.Failures < t.limitFor(subject).maxFailures {
+			continue
+		}
+		until := now.Add(t.lockout)
+		if err = t.repo.Lock(ctx, subject, until); err != nil {
+			return err
+		}
+		if err = t.repo.CreateEvent(ctx, &entity.LoginLockoutEvent{
+			ID:          id.NextID(),
+			Subject:     subject,
+			Action:      entity.LockoutActionLocked,
+			Failures:    throttle.Failures,
+			LockedUntil: &until,
+			IP:          ip,
+		}); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).WithFields(map[string]interface{}{
+			"subject":      subject,
+			"failures":     throttle.Failures,
+			"locked_until": until,
+		}).Warn("login_locked")
+	}
+	return nil
+}
+
+// unlock 清除计数与锁定，并记录执行解锁的管理员
+func (t *loginThrottle) unlock(ctx context.Context, subjects []string, operatorID int64) error {
+	if err := t.repo.Delete(ctx, subjects...); err != nil {
+		return err
+	}
+	for _, subject := range subjects {
+		if err := t.repo.CreateEvent(ctx, &entity.LoginLockoutEvent{
+			ID:         id.NextID(),
+			Subject:    subject,
+			Action:     entity.LockoutActionUnlocked,
+			OperatorID: &operatorID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dummyPasswordHash 手机号不存在时仍执行一次 bcrypt 比较，使响应时间与密码错误一致
+var dummyPasswordHash = sync.OnceValue(func() string {
+	return utils.BcryptHash("minigo-dummy-password")
+})
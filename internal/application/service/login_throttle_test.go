@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"minigo/internal/domain/entity"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/config"
+)
+
+// memoryLoginThrottles 内存实现，RecordFailure 与 SQL 版本的计数规则一致
+type memoryLoginThrottles struct {
+	throttles map[string]*entity.LoginThrottle
+	events    []*entity.LoginLockoutEvent
+}
+
+func (m *memoryLoginThrottles) ListBySubjects(ctx context.Context, subjects []string) ([]*entity.LoginThrottle, error) {
+	var out []*entity.LoginThrottle
+	for _, s := range subjects {
+		if t, ok := m.throttles[s]; ok {
+			copied := *t
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryLoginThrottles) RecordFailure(ctx context.Context, subject string, at, resetBefore time.Time) (*entity.LoginThrottle, error) {
+	t, ok := m.throttles[subject]
+	switch {
+	case !ok:
+		t = &entity.LoginThrottle{Subject: subject, Failures: 1}
+		m.throttles[subject] = t
+	case t.LastFailedAt.Before(resetBefore):
+		t.Failures = 1
+	default:
+		t.Failures++
+	}
+	t.LastFailedAt = at
+	copied := *t
+	return &copied, nil
+}
+
+func (m *memoryLoginThrottles) Lock(ctx context.Context, subject string, until time.Time) error {
+	m.throttles[subject].Failures = 0
+	m.throttles[subject].LockedUntil = &until
+	return nil
+}
+
+func (m *memoryLoginThrottles) Delete(ctx context.Context, subjects ...string) error {
+	for _, s := range subjects {
+		delete(m.throttles, s)
+	}
+	return nil
+}
+
+func (m *memoryLoginThrottles) PurgeStale(ctx context.Context, before time.Time) error {
+	for s, t := range m.throttles {
+		if t.LastFailedAt.Before(before) && (t.LockedUntil == nil || t.LockedUntil.Before(before)) {
+			delete(m.throttles, s)
+		}
+	}
+	return nil
+}
+
+func (m *memoryLoginThrottles) CreateEvent(ctx context.Context, event *entity.LoginLockoutEvent) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *memoryLoginThrottles) ListEvents(ctx context.Context, filter repository.LockoutEventFilter) ([]*entity.LoginLockoutEvent, int, error) {
+	return m.events, len(m.events), nil
+}
+
+func TestLoginThrottle(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryLoginThrottles{throttles: make(map[string]*entity.LoginThrottle)}
+	throttle := newLoginThrottle(repo, config.LoginConfig{
+		PhoneFreeAttempts: 2,
+		PhoneMaxFailures:  5,
+		IPFreeAttempts:    10,
+		IPMaxFailures:     20,
+		DelayBase:         time.Second,
+		DelayMax:          3 * time.Second,
+		LockoutDuration:   15 * time.Minute,
+		FailureWindow:     time.Hour,
+	})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	subjects := []string{phoneSubject("13800000000"), ipSubject("10.0.0.1")}
+
+	retryAfter := func() time.Duration {
+		err := throttle.check(ctx, subjects, now)
+		if err == nil {
+			return 0
+		}
+		var throttled *LoginThrottledError
+		if !errors.As(err, &throttled) || !errors.Is(err, ErrTooManyLoginAttempts) {
+			t.Fatalf("Expected LoginThrottledError, got %v", err)
+		}
+		return throttled.RetryAfter
+	}
+	fail := func() {
+		if err := throttle.recordFailure(ctx, subjects, "10.0.0.1", now); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	t.Run("delays progressively after the free attempts", func(t *testing.T) {
+		for i, want := range []time.Duration{0, 0, time.Second, 2 * time.Second} {
+			fail()
+			if got := retryAfter(); got != want {
+				t.Fatalf("Expected %s delay after %d failures, got %s", want, i+1, got)
+			}
+			now = now.Add(want)
+		}
+		if got := throttle.delay(30, throttle.ip); got != 3*time.Second {
+			t.Fatalf("Expected delay capped at 3s, got %s", got)
+		}
+	})
+
+	t.Run("locks the phone at the maximum and records the event", func(t *testing.T) {
+		fail()
+		if got := retryAfter(); got != 15*time.Minute {
+			t.Fatalf("Expected 15m lockout, got %s", got)
+		}
+		if len(repo.events) != 1 || repo.events[0].Subject != subjects[0] || repo.events[0].Action != entity.LockoutActionLocked {
+			t.Fatalf("Expected one phone lock event, got %+v", repo.events)
+		}
+		// IP 未达到上限，计数不受手机号锁定影响
+		if repo.throttles[subjects[1]].Failures != 5 {
+			t.Fatalf("Expected 5 IP failures, got %d", repo.throttles[subjects[1]].Failures)
+		}
+	})
+
+	t.Run("admin unlock clears the lock", func(t *testing.T) {
+		if err := throttle.unlock(ctx, subjects[:1], 1); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := retryAfter(); got != 0 {
+			t.Fatalf("Expected no delay after unlock, got %s", got)
+		}
+		if last := repo.events[len(repo.events)-1]; last.Action != entity.LockoutActionUnlocked || *last.OperatorID != 1 {
+			t.Fatalf("Expected unlock event with operator, got %+v", last)
+		}
+	})
+
+	t.Run("restarts counting after the failure window", func(t *testing.T) {
+		fail()
+		fail()
+		now = now.Add(2 * time.Hour)
+		fail()
+		if got := repo.throttles[subjects[0]].Failures; got != 1 {
+			t.Fatalf("Expected count restarted, got %d", got)
+		}
+	})
+}
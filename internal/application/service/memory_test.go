@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+
+	"minigo/internal/domain/entity"
+	apperrors "minigo/internal/domain/errors"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/mail"
+	"minigo/internal/infrastructure/tx"
+)
+
+// noopConnector 只支持开启和提交事务的数据库连接，配合内存仓储测试服务层的事务流程。
+// 内存仓储不随事务回滚，测试应只依赖写入是否发生。
+type noopConnector struct{}
+
+func (noopConnector) Connect(context.Context) (driver.Conn, error) { return noopConn{}, nil }
+func (noopConnector) Driver() driver.Driver                        { return noopDriver{} }
+
+type noopDriver struct{}
+
+func (noopDriver) Open(string) (driver.Conn, error) { return noopConn{}, nil }
+
+type noopConn struct{}
+
+func (noopConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("noop connection does not run queries")
+}
+func (noopConn) Close() error              { return nil }
+func (noopConn) Begin() (driver.Tx, error) { return noopTx{}, nil }
+
+type noopTx struct{}
+
+func (noopTx) Commit() error   { return nil }
+func (noopTx) Rollback() error { return nil }
+
+func newTestTxManager() *tx.Manager {
+	return tx.NewManager(bun.NewDB(sql.OpenDB(noopConnector{}), pgdialect.New()))
+}
+
+// memoryUsers 内存用户仓储
+type memoryUsers struct {
+	mu    sync.Mutex
+	users map[int64]*entity.User
+}
+
+func newMemoryUsers(users ...*entity.User) *memoryUsers {
+	m := &memoryUsers{users: make(map[int64]*entity.User)}
+	for _, u := range users {
+		m.users[u.ID] = u
+	}
+	return m
+}
+
+func (m *memoryUsers) Create(ctx context.Context, user *entity.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *user
+	m.users[user.ID] = &copied
+	return nil
+}
+
+func (m *memoryUsers) Update(ctx context.Context, user *entity.User) error {
+	return m.Create(ctx, user)
+}
+
+func (m *memoryUsers) GetByID(ctx context.Context, id int64) (*entity.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if u, ok := m.users[id]; ok {
+		copied := *u
+		return &copied, nil
+	}
+	return nil, apperrors.ErrResourceNotFound
+}
+
+func (m *memoryUsers) GetForUpdate(ctx context.Context, id int64) (*entity.User, error) {
+	return m.GetByID(ctx, id)
+}
+
+func (m *memoryUsers) find(match func(*entity.User) bool) (*entity.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range m.users {
+		if match(u) {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrResourceNotFound
+}
+
+func (m *memoryUsers) GetByPhone(ctx context.Context, phone string) (*entity.User, error) {
+	return m.find(func(u *entity.User) bool { return u.Phone == phone })
+}
+
+func (m *memoryUsers) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return m.find(func(u *entity.User) bool { return u.Email != "" && u.Email == entity.NormalizeEmail(email) })
+}
+
+func (m *memoryUsers) List(ctx context.Context, filter repository.UserListFilter) ([]*entity.User, int, error) {
+	return nil, 0, nil
+}
+
+func (m *memoryUsers) Delete(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.users, id)
+	return nil
+}
+
+// memoryRefreshTokens 内存刷新令牌仓储，并记录按用户吊销
+type memoryRefreshTokens struct {
+	tokens       []*entity.RefreshToken
+	revokedUsers []int64
+}
+
+func (m *memoryRefreshTokens) Create(ctx context.Context, token *entity.RefreshToken) error {
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *memoryRefreshTokens) GetByHashForUpdate(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	for _, t := range m.tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrResourceNotFound
+}
+
+func (m *memoryRefreshTokens) MarkUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	for _, t := range m.tokens {
+		if t.ID == id {
+			t.UsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+func (m *memoryRefreshTokens) RevokeFamily(ctx context.Context, familyID string) error {
+	now := time.Now()
+	for _, t := range m.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *memoryRefreshTokens) RevokeByUserID(ctx context.Context, userID int64) error {
+	m.revokedUsers = append(m.revokedUsers, userID)
+	return nil
+}
+
+func (m *memoryRefreshTokens) PurgeExpired(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+// memoryVerificationCodes 内存验证码仓储
+type memoryVerificationCodes struct {
+	codes []*entity.VerificationCode
+}
+
+func (m *memoryVerificationCodes) Create(ctx context.Context, code *entity.VerificationCode) error {
+	m.codes = append(m.codes, code)
+	return nil
+}
+
+func (m *memoryVerificationCodes) GetLatest(ctx context.Context, phone, purpose string) (*entity.VerificationCode, error) {
+	for i := len(m.codes) - 1; i >= 0; i-- {
+		if c := m.codes[i]; c.Phone == phone && c.Purpose == purpose {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrResourceNotFound
+}
+
+func (m *memoryVerificationCodes) GetLatestForUpdate(ctx context.Context, phone, purpose string) (*entity.VerificationCode, error) {
+	return m.GetLatest(ctx, phone, purpose)
+}
+
+func (m *memoryVerificationCodes) CountSince(ctx context.Context, phone string, since time.Time) (int, error) {
+	n := 0
+	for _, c := range m.codes {
+		if c.Phone == phone && !c.CreatedAt.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memoryVerificationCodes) byID(id int64) *entity.VerificationCode {
+	for _, c := range m.codes {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func (m *memoryVerificationCodes) IncrementAttempts(ctx context.Context, id int64) error {
+	m.byID(id).Attempts++
+	return nil
+}
+
+func (m *memoryVerificationCodes) MarkUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	m.byID(id).UsedAt = &usedAt
+	return nil
+}
+
+func (m *memoryVerificationCodes) PurgeSentBefore(ctx context.Context, before time.Time) error {
+	codes := m.codes[:0]
+	for _, c := range m.codes {
+		if !c.CreatedAt.Before(before) {
+			codes = append(codes, c)
+		}
+	}
+	m.codes = codes
+	return nil
+}
+
+// memoryResetTokens 内存重置令牌仓储
+type memoryResetTokens struct {
+	mu     sync.Mutex
+	tokens []*entity.PasswordResetToken
+}
+
+func (m *memoryResetTokens) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *memoryResetTokens) GetLatestByUser(ctx context.Context, userID int64) (*entity.PasswordResetToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.tokens) - 1; i >= 0; i-- {
+		if t := m.tokens[i]; t.UserID == userID {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrResourceNotFound
+}
+
+func (m *memoryResetTokens) GetByHashForUpdate(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range m.tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrResourceNotFound
+}
+
+func (m *memoryResetTokens) InvalidateByUser(ctx context.Context, userID int64, usedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range m.tokens {
+		if t.UserID == userID && t.UsedAt == nil {
+			t.UsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+func (m *memoryResetTokens) PurgeExpired(ctx context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tokens := m.tokens[:0]
+	for _, t := range m.tokens {
+		if !t.ExpiresAt.Before(before) {
+			tokens = append(tokens, t)
+		}
+	}
+	m.tokens = tokens
+	return nil
+}
+
+// memoryMail 记录发出的邮件
+type memoryMail struct {
+	mu   sync.Mutex
+	sent []mail.Message
+}
+
+func (m *memoryMail) Send(ctx context.Context, msg mail.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *memoryMail) messages() []mail.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]mail.Message(nil), m.sent...)
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"minigo/internal/domain/entity"
+	apperrors "minigo/internal/domain/errors"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/auth"
+	"minigo/internal/infrastructure/config"
+	"minigo/internal/infrastructure/id"
+	"minigo/pkg/utils"
+)
+
+// 密码策略违反的规则，作为 ErrPasswordPolicy 字段级错误的 code
+const (
+	PasswordTooShort      = "PWD_002"
+	PasswordTooLong       = "PWD_003"
+	PasswordMissingClass  = "PWD_004"
+	PasswordTooFewClasses = "PWD_005"
+	PasswordTooCommon     = "PWD_006"
+	PasswordContainsPhone = "PWD_007"
+	PasswordReused        = "PWD_008"
+)
+
+// 请求中密码字段的名称，用于字段级错误
+const (
+	fieldPassword    = "password"
+	fieldNewPassword = "new_password"
+)
+
+// bcryptMaxBytes bcrypt 只支持 72 字节以内的密码
+const bcryptMaxBytes = 72
+
+// PasswordPolicyService 统一的密码策略：长度、字符种类、常见密码、历史密码和最长使用期限。
+// 注册、管理员创建/重置、修改密码和找回密码都通过它校验。
+type PasswordPolicyService struct {
+	historyRepo repository.PasswordHistoryRepository
+	blocklist   *auth.PasswordBlocklist // nil 表示不检查常见密码
+	cfg         config.PasswordPolicyConfig
+}
+
+// NewPasswordPolicyService 创建密码策略服务实例
+func NewPasswordPolicyService(
+	historyRepo repository.PasswordHistoryRepository,
+	blocklist *auth.PasswordBlocklist,
+	cfg config.PasswordPolicyConfig,
+) *PasswordPolicyService {
+	return &PasswordPolicyService{
+		historyRepo: historyRepo,
+		blocklist:   blocklist,
+		cfg:         cfg,
+	}
+}
+
+// PasswordRules 当前生效的密码规则，供客户端展示
+type PasswordRules struct {
+	MinLength      int
+	MaxLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSymbol  bool
+	MinCharClasses int
+	History        int
+	MaxAge         time.Duration
+}
+
+// Rules 返回当前生效的密码规则
+func (s *PasswordPolicyService) Rules() PasswordRules {
+	return PasswordRules{
+		MinLength:      s.cfg.MinLength,
+		MaxLength:      s.cfg.MaxLength,
+		RequireUpper:   s.cfg.RequireUpper,
+		RequireLower:   s.cfg.RequireLower,
+		RequireDigit:   s.cfg.RequireDigit,
+		RequireSymbol:  s.cfg.RequireSymbol,
+		MinCharClasses: s.cfg.MinCharClasses,
+		History:        s.cfg.History,
+		MaxAge:         s.cfg.MaxAge,
+	}
+}
+
+// Check 校验 user 将要设置的新密码，违反的所有规则以 field 的字段级错误返回（ErrPasswordPolicy）。
+// user.ID 为 0 表示新用户，不检查历史密码；否则 user.Password 应为当前密码的哈希。
+func (s *PasswordPolicyService) Check(ctx context.Context, user *entity.User, field, password string) error {
+	var violations []apperrors.FieldError
+	add := func(code, message string) {
+		violations = append(violations, apperrors.FieldError{Field: field, Code: code, Message: message})
+	}
+
+	length := utf8.RuneCountInString(password)
+	switch {
+	case length < s.cfg.MinLength:
+		add(PasswordTooShort, fmt.Sprintf("密码长度不能少于%d位", s.cfg.MinLength))
+	case length > s.cfg.MaxLength || len(password) > bcryptMaxBytes:
+		add(PasswordTooLong, fmt.Sprintf("密码长度不能超过%d位", s.cfg.MaxLength))
+	}
+
+	upper, lower, digit, symbol := charClasses(password)
+	if s.cfg.RequireUpper && !upper {
+		add(PasswordMissingClass, "密码必须包含大写字母")
+	}
+	if s.cfg.RequireLower && !lower {
+		add(PasswordMissingClass, "密码必须包含小写字母")
+	}
+	if s.cfg.RequireDigit && !digit {
+		add(PasswordMissingClass, "密码必须包含数字")
+	}
+	if s.cfg.RequireSymbol && !symbol {
+		add(PasswordMissingClass, "密码必须包含符号")
+	}
+	classes := 0
+	for _, ok := range []bool{upper, lower, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	if classes < s.cfg.MinCharClasses {
+		add(PasswordTooFewClasses, fmt.Sprintf("密码至少包含大写字母、小写字母、数字、符号中的%d种", s.cfg.MinCharClasses))
+	}
+
+	if s.blocklist.Contains(password) {
+		add(PasswordTooCommon, "密码过于常见，请更换")
+	}
+	if user.Phone != "" && strings.Contains(password, user.Phone) {
+		add(PasswordContainsPhone, "密码不能包含手机号")
+	}
+
+	if user.ID != 0 && s.cfg.History > 0 {
+		reused, err := s.reused(ctx, user, password)
+		if err != nil {
+			return err
+		}
+		if reused {
+			add(PasswordReused, fmt.Sprintf("不能使用最近%d次使用过的密码", s.cfg.History))
+		}
+	}
+
+	if len(violations) > 0 {
+		return ErrPasswordPolicy.WithFields(violations...)
+	}
+	return nil
+}
+
+// Expired 密码是否已超过 PASSWORD_MAX_AGE，过期后登录签发的令牌只能用于修改密码
+func (s *PasswordPolicyService) Expired(user *entity.User, now time.Time) bool {
+	return s.cfg.MaxAge > 0 && now.Sub(user.PasswordChangedAt) > s.cfg.MaxAge
+}
+
+// reused 新密码是否与当前密码或最近的历史密码相同
+func (s *PasswordPolicyService) reused(ctx context.Context, user *entity.User, password string) (bool, error) {
+	// 启用历史记录之前设置的密码不在历史表中，当前密码单独比较
+	if utils.BcryptCheck(password, user.Password) {
+		return true, nil
+	}
+	history, err := s.historyRepo.ListRecent(ctx, user.ID, s.cfg.History)
+	if err != nil {
+		return false, err
+	}
+	for _, h := range history {
+		if utils.BcryptCheck(password, h.PasswordHash) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// record 保存用户刚设置的密码哈希（user.SetPassword 之后的 user.Password），
+// 并清理超出 PASSWORD_HISTORY 的旧记录。需在持久化用户之后、同一事务中调用。
+func (s *PasswordPolicyService) record(ctx context.Context, user *entity.User) error {
+	if s.cfg.History <= 0 {
+		return nil
+	}
+	if err := s.historyRepo.Create(ctx, &entity.PasswordHistory{
+		ID:           id.NextID(),
+		UserID:       user.ID,
+		PasswordHash: user.Password,
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		return err
+	}
+	return s.historyRepo.Prune(ctx, user.ID, s.cfg.History)
+}
+
+// charClasses 统计密码包含的字符种类，字母和数字以外的字符均视为符号
+func charClasses(password string) (upper, lower, digit, symbol bool) {
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	return
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"minigo/internal/domain/entity"
+	apperrors "minigo/internal/domain/errors"
+	"minigo/internal/infrastructure/auth"
+	"minigo/internal/infrastructure/config"
+	"minigo/pkg/utils"
+)
+
+// memoryPasswordHistory 内存实现，按插入顺序保存
+type memoryPasswordHistory struct {
+	items []*entity.PasswordHistory
+}
+
+func (m *memoryPasswordHistory) Create(ctx context.Context, history *entity.PasswordHistory) error {
+	m.items = append(m.items, history)
+	return nil
+}
+
+func (m *memoryPasswordHistory) ListRecent(ctx context.Context, userID int64, limit int) ([]*entity.PasswordHistory, error) {
+	var out []*entity.PasswordHistory
+	for i := len(m.items) - 1; i >= 0 && len(out) < limit; i-- {
+		if m.items[i].UserID == userID {
+			out = append(out, m.items[i])
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryPasswordHistory) Prune(ctx context.Context, userID int64, keep int) error {
+	recent, _ := m.ListRecent(ctx, userID, keep)
+	kept := make(map[int64]bool, len(recent))
+	for _, h := range recent {
+		kept[h.ID] = true
+	}
+	items := m.items[:0]
+	for _, h := range m.items {
+		if h.UserID != userID || kept[h.ID] {
+			items = append(items, h)
+		}
+	}
+	m.items = items
+	return nil
+}
+
+// violationCodes 返回字段级错误的 code，顺序无关
+func violationCodes(t *testing.T, err error, field string) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrPasswordPolicy) {
+		t.Fatalf("Expected ErrPasswordPolicy, got %v", err)
+	}
+	appErr, _ := apperrors.AsAppError(err)
+	var codes []string
+	for _, f := range appErr.Fields {
+		if f.Field != field {
+			t.Fatalf("Expected field %q, got %q", field, f.Field)
+		}
+		codes = append(codes, f.Code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+	blocklist, err := auth.NewPasswordBlocklist("")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	history := &memoryPasswordHistory{}
+	policy := NewPasswordPolicyService(history, blocklist, config.PasswordPolicyConfig{
+		MinLength:      8,
+		MaxLength:      20,
+		RequireDigit:   true,
+		MinCharClasses: 3,
+		History:        2,
+		MaxAge:         90 * 24 * time.Hour,
+	})
+	newUser := &entity.User{Phone: "13800000000"}
+
+	t.Run("accepts a password meeting every rule", func(t *testing.T) {
+		if err := policy.Check(ctx, newUser, fieldPassword, "Blue-sky42"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("reports every violation as a field error", func(t *testing.T) {
+		codes := violationCodes(t, policy.Check(ctx, newUser, fieldNewPassword, "abc"), fieldNewPassword)
+		want := []string{PasswordTooShort, PasswordMissingClass, PasswordTooFewClasses}
+		sort.Strings(want)
+		if len(codes) != len(want) {
+			t.Fatalf("Expected %v, got %v", want, codes)
+		}
+		for i := range want {
+			if codes[i] != want[i] {
+				t.Fatalf("Expected %v, got %v", want, codes)
+			}
+		}
+	})
+
+	t.Run("counts characters rather than bytes", func(t *testing.T) {
+		if err := policy.Check(ctx, newUser, fieldPassword, "密码安全Ab1!"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		codes := violationCodes(t, policy.Check(ctx, newUser, fieldPassword, "Aa1!"+"一二三四五六七八九十一二三四五六七八九十"), fieldPassword)
+		if len(codes) != 1 || codes[0] != PasswordTooLong {
+			t.Fatalf("Expected too long, got %v", codes)
+		}
+	})
+
+	t.Run("rejects common passwords and the phone number", func(t *testing.T) {
+		codes := violationCodes(t, policy.Check(ctx, newUser, fieldPassword, "Password1!"), fieldPassword)
+		if len(codes) != 0 {
+			t.Fatalf("Expected Password1! allowed, got %v", codes)
+		}
+		codes = violationCodes(t, policy.Check(ctx, newUser, fieldPassword, "Qwerty123"), fieldPassword)
+		if len(codes) != 1 || codes[0] != PasswordTooCommon {
+			t.Fatalf("Expected common password, got %v", codes)
+		}
+		codes = violationCodes(t, policy.Check(ctx, newUser, fieldPassword, "A13800000000!"), fieldPassword)
+		if len(codes) != 1 || codes[0] != PasswordContainsPhone {
+			t.Fatalf("Expected phone violation, got %v", codes)
+		}
+	})
+
+	t.Run("rejects the current and recent passwords", func(t *testing.T) {
+		user := &entity.User{ID: 1, Phone: "13800000000"}
+		for _, pw := range []string{"Oldest-01", "Recent-01", "Recent-02"} {
+			user.Password = utils.BcryptHash(pw)
+			if err := policy.record(ctx, user); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+		if len(history.items) != 2 {
+			t.Fatalf("Expected history pruned to 2, got %d", len(history.items))
+		}
+		for _, pw := range []string{"Recent-01", "Recent-02"} {
+			codes := violationCodes(t, policy.Check(ctx, user, fieldNewPassword, pw), fieldNewPassword)
+			if len(codes) != 1 || codes[0] != PasswordReused {
+				t.Fatalf("Expected %s reused, got %v", pw, codes)
+			}
+		}
+		if err := policy.Check(ctx, user, fieldNewPassword, "Oldest-01"); err != nil {
+			t.Fatalf("Expected password older than the history allowed, got %v", err)
+		}
+		// 新用户不检查历史密码
+		if err := policy.Check(ctx, newUser, fieldPassword, "Recent-02"); err != nil {
+			t.Fatalf("Expected no history check for new users, got %v", err)
+		}
+	})
+
+	t.Run("expires after the maximum age", func(t *testing.T) {
+		now := time.Now()
+		user := &entity.User{PasswordChangedAt: now.Add(-89 * 24 * time.Hour)}
+		if policy.Expired(user, now) {
+			t.Fatal("Expected password not expired")
+		}
+		user.PasswordChangedAt = now.Add(-91 * 24 * time.Hour)
+		if !policy.Expired(user, now) {
+			t.Fatal("Expected password expired")
+		}
+		if NewPasswordPolicyService(history, nil, config.PasswordPolicyConfig{}).Expired(user, now) {
+			t.Fatal("Expected no expiry without PASSWORD_MAX_AGE")
+		}
+	})
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"minigo/internal/domain/entity"
+	apperrors "minigo/internal/domain/errors"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/auth"
+	"minigo/internal/infrastructure/config"
+	"minigo/internal/infrastructure/id"
+	"minigo/internal/infrastructure/logging"
+	"minigo/internal/infrastructure/mail"
+	"minigo/internal/infrastructure/tx"
+	"minigo/pkg/validator"
+)
+
+// PasswordResetService 忘记密码时找回：按手机号发送短信验证码，按邮箱发送带一次性令牌的重置链接。
+// 完成重置后吊销该用户的所有令牌。
+type PasswordResetService struct {
+	tokenRepo    repository.PasswordResetTokenRepository
+	userRepo     repository.UserRepository
+	users        *UserService
+	verification *VerificationService
+	sender       mail.Sender
+	deliveries   *DeliveryQueue
+	txManager    *tx.Manager
+	cfg          config.PasswordResetConfig
+}
+
+// NewPasswordResetService 创建找回密码服务实例
+func NewPasswordResetService(
+	tokenRepo repository.PasswordResetTokenRepository,
+	userRepo repository.UserRepository,
+	users *UserService,
+	verification *VerificationService,
+	sender mail.Sender,
+	deliveries *DeliveryQueue,
+	txManager *tx.Manager,
+	cfg config.PasswordResetConfig,
+) *PasswordResetService {
+	return &PasswordResetService{
+		tokenRepo:    tokenRepo,
+		userRepo:     userRepo,
+		users:        users,
+		verification: verification,
+		sender:       sender,
+		deliveries:   deliveries,
+		txManager:    txManager,
+		cfg:          cfg,
+	}
+}
+
+// RequestPasswordResetParams 申请找回密码参数，Phone 和 Email 二选一
+type RequestPasswordResetParams struct {
+	Phone string
+	Email string
+	IP    string
+}
+
+// RequestReset 申请找回密码。手机号发送 reset_password 用途的短信验证码；
+// 邮箱在后台查找用户并发送重置链接，接口立即返回相同的结果，邮箱未注册、用户已停用或发送间隔未到时
+// 只是不发送邮件，调用方无法据响应内容或时间判断邮箱是否已注册。
+func (s *PasswordResetService) RequestReset(ctx context.Context, params RequestPasswordResetParams) (*SendCodeResult, error) {
+	switch {
+	case params.Phone != "":
+		return s.verification.SendCode(ctx, SendCodeParams{
+			Phone:   params.Phone,
+			Purpose: entity.VerifyPurposeResetPassword,
+			IP:      params.IP,
+		})
+	case params.Email != "":
+		if !validator.IsEmail(params.Email) {
+			return nil, ErrInvalidEmail
+		}
+		email := entity.NormalizeEmail(params.Email)
+		s.deliveries.run(ctx, func(ctx context.Context) {
+			if err := s.sendResetLink(ctx, email, params.IP); err != nil {
+				logging.FromContext(ctx).WithError(err).WithField("email", email).Error("password_reset_failed")
+			}
+		})
+		return &SendCodeResult{ExpiresIn: s.cfg.TokenTTL, ResendAfter: s.cfg.ResendInterval}, nil
+	}
+	return nil, ErrResetTargetRequired
+}
+
+// ResetPasswordParams 重置密码参数，凭短信验证码（Phone + Code）或邮件中的 Token
+type ResetPasswordParams struct {
+	Phone    string
+	Code     string
+	Token    string
+	Password string
+}
+
+// ResetPassword 校验短信验证码或重置令牌后设置新密码，并吊销该用户的所有令牌
+func (s *PasswordResetService) ResetPassword(ctx context.Context, params ResetPasswordParams) error {
+	switch {
+	case params.Token != "":
+		return s.resetWithToken(ctx, params.Token, params.Password)
+	case params.Phone != "" && params.Code != "":
+		return s.users.ResetPasswordByCode(ctx, params.Phone, params.Code, params.Password)
+	}
+	return ErrResetCredentialRequired
+}
+
+// sendResetLink 生成重置令牌并发送邮件，同一用户在 PASSWORD_RESET_RESEND_INTERVAL 内只发送一次。
+// 在后台执行，错误只记录日志。
+func (s *PasswordResetService) sendResetLink(ctx context.Context, email, ip string) error {
+	now := time.Now()
+	log := logging.FromContext(ctx).WithField("email", email)
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrResourceNotFound) {
+			log.Info("password_reset_not_delivered")
+			return nil
+		}
+		return err
+	}
+	if user.Status == entity.StatusDisabled {
+		log.Info("password_reset_not_delivered")
+		return nil
+	}
+	token, tokenHash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	var throttled bool
+	if err = s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		// 锁定用户，串行化同一用户的并发申请
+		if _, err := s.userRepo.GetForUpdate(txCtx, user.ID); err != nil {
+			return err
+		}
+		latest, err := s.tokenRepo.GetLatestByUser(txCtx, user.ID)
+		switch {
+		case err == nil:
+			if latest.CreatedAt.Add(s.cfg.ResendInterval).After(now) {
+				throttled = true
+				return nil
+			}
+		case !errors.Is(err, apperrors.ErrResourceNotFound):
+			return err
+		}
+		return s.tokenRepo.Create(txCtx, &entity.PasswordResetToken{
+			ID:        id.NextID(),
+			UserID:    user.ID,
+			TokenHash: tokenHash,
+			IP:        ip,
+			ExpiresAt: now.Add(s.cfg.TokenTTL),
+			CreatedAt: now,
+		})
+	}); err != nil {
+		return err
+	}
+	if throttled {
+		log.Info("password_reset_throttled")
+		return nil
+	}
+
+	if err = s.sender.Send(ctx, mail.Message{
+		To:      email,
+		Subject: "重置密码",
+		Body: fmt.Sprintf("您正在重置密码，请在 %d 分钟内打开以下链接设置新密码：\n\n%s\n\n如非本人操作，请忽略此邮件。",
+			int(s.cfg.TokenTTL.Minutes()), resetLink(s.cfg.URL, token)),
+	}); err != nil {
+		log.WithError(err).Error("password_reset_send_failed")
+	}
+	return nil
+}
+
+// resetWithToken 消费重置令牌并设置新密码，同时作废该用户其他未使用的令牌
+func (s *PasswordResetService) resetWithToken(ctx context.Context, token, password string) error {
+	now := time.Now()
+	return s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		reset, err := s.tokenRepo.GetByHashForUpdate(txCtx, auth.HashOpaqueToken(token))
+		if err != nil {
+			if errors.Is(err, apperrors.ErrResourceNotFound) {
+				return ErrInvalidResetToken
+			}
+			return err
+		}
+		if !reset.IsUsable(now) {
+			return ErrInvalidResetToken
+		}
+		user, err := s.userRepo.GetForUpdate(txCtx, reset.UserID)
+		if err != nil {
+			// 令牌签发后用户被删除
+			return ErrInvalidResetToken
+		}
+		if err = s.users.setPassword(txCtx, user, fieldNewPassword, password); err != nil {
+			return err
+		}
+		if err = s.tokenRepo.InvalidateByUser(txCtx, user.ID, now); err != nil {
+			return err
+		}
+		return s.users.revokeUserTokens(txCtx, user.ID)
+	})
+}
+
+// resetLink 在 PASSWORD_RESET_URL 上附加 token 查询参数，保留已有参数
+func resetLink(base, token string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
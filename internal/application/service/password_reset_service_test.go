@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"minigo/internal/domain/entity"
+	"minigo/internal/infrastructure/auth"
+	"minigo/internal/infrastructure/config"
+	"minigo/pkg/utils"
+)
+
+func TestPasswordReset(t *testing.T) {
+	ctx := context.Background()
+	svc := &PasswordResetService{}
+
+	t.Run("requires a phone or email", func(t *testing.T) {
+		if _, err := svc.RequestReset(ctx, RequestPasswordResetParams{IP: "10.0.0.1"}); !errors.Is(err, ErrResetTargetRequired) {
+			t.Fatalf("Expected ErrResetTargetRequired, got %v", err)
+		}
+		if _, err := svc.RequestReset(ctx, RequestPasswordResetParams{Email: "not-an-email"}); !errors.Is(err, ErrInvalidEmail) {
+			t.Fatalf("Expected ErrInvalidEmail, got %v", err)
+		}
+	})
+
+	t.Run("requires a code or token", func(t *testing.T) {
+		err := svc.ResetPassword(ctx, ResetPasswordParams{Phone: "13800000000", Password: "secret123"})
+		if !errors.Is(err, ErrResetCredentialRequired) {
+			t.Fatalf("Expected ErrResetCredentialRequired, got %v", err)
+		}
+	})
+
+	t.Run("appends the token to the reset URL", func(t *testing.T) {
+		link, err := url.Parse(resetLink("https://example.com/reset?lang=zh", "a+b/c"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if link.Query().Get("token") != "a+b/c" || link.Query().Get("lang") != "zh" || link.Path != "/reset" {
+			t.Fatalf("Expected token and existing query kept, got %s", link)
+		}
+	})
+
+	t.Run("tokens expire after use or timeout", func(t *testing.T) {
+		now := time.Now()
+		token := entity.PasswordResetToken{ExpiresAt: now.Add(time.Minute)}
+		if !token.IsUsable(now) {
+			t.Fatal("Expected fresh token usable")
+		}
+		if token.IsUsable(now.Add(time.Minute)) {
+			t.Fatal("Expected expired token unusable")
+		}
+		token.UsedAt = &now
+		if token.IsUsable(now) {
+			t.Fatal("Expected used token unusable")
+		}
+	})
+}
+
+func TestPasswordResetByEmail(t *testing.T) {
+	ctx := context.Background()
+	txManager := newTestTxManager()
+	users := newMemoryUsers(
+		&entity.User{ID: 1, Phone: "13800000001", Email: "active@example.com", Password: utils.BcryptHash("Old-pass-1")},
+		&entity.User{ID: 2, Phone: "13800000002", Email: "disabled@example.com", Status: entity.StatusDisabled},
+	)
+	refreshTokens := &memoryRefreshTokens{}
+	revocations := auth.NewMemoryRevocationStore()
+	policy := NewPasswordPolicyService(&memoryPasswordHistory{}, nil, config.PasswordPolicyConfig{MinLength: 8, MaxLength: 64})
+	userSvc := NewUserService(users, refreshTokens, revocations, nil, policy, txManager)
+	tokens := &memoryResetTokens{}
+	sender := &memoryMail{}
+	cfg := config.PasswordResetConfig{
+		URL:            "https://example.com/reset",
+		TokenTTL:       30 * time.Minute,
+		ResendInterval: time.Minute,
+	}
+	svc := NewPasswordResetService(tokens, users, userSvc, nil, sender, NewDeliveryQueue(), txManager, cfg)
+
+	request := func(email string) *SendCodeResult {
+		t.Helper()
+		result, err := svc.RequestReset(ctx, RequestPasswordResetParams{Email: email, IP: "10.0.0.1"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		svc.deliveries.wait()
+		return result
+	}
+	// lastToken 取出最近一封邮件链接中的令牌
+	lastToken := func() string {
+		t.Helper()
+		sent := sender.messages()
+		if len(sent) == 0 {
+			t.Fatal("Expected a reset email")
+		}
+		for _, field := range strings.Fields(sent[len(sent)-1].Body) {
+			if strings.HasPrefix(field, cfg.URL) {
+				link, err := url.Parse(field)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return link.Query().Get("token")
+			}
+		}
+		t.Fatal("Expected a reset link in the email")
+		return ""
+	}
+	// expireResendInterval 把已签发的令牌提前到发送间隔之前，允许再次申请
+	expireResendInterval := func() {
+		for _, token := range tokens.tokens {
+			token.CreatedAt = token.CreatedAt.Add(-cfg.ResendInterval)
+		}
+	}
+
+	t.Run("treats unknown and disabled emails like registered ones", func(t *testing.T) {
+		known := request("Active@Example.com")
+		for _, email := range []string{"unknown@example.com", "disabled@example.com"} {
+			if result := request(email); *result != *known {
+				t.Fatalf("Expected %+v for %s, got %+v", *known, email, *result)
+			}
+		}
+		sent := sender.messages()
+		if len(sent) != 1 || sent[0].To != "active@example.com" {
+			t.Fatalf("Expected one email to the registered address, got %+v", sent)
+		}
+		if len(tokens.tokens) != 1 || tokens.tokens[0].UserID != 1 {
+			t.Fatalf("Expected one token for the registered user, got %d", len(tokens.tokens))
+		}
+	})
+
+	t.Run("throttles repeated requests", func(t *testing.T) {
+		request("active@example.com")
+		if len(sender.messages()) != 1 || len(tokens.tokens) != 1 {
+			t.Fatalf("Expected no new email within the resend interval, got %d", len(sender.messages()))
+		}
+		expireResendInterval()
+		request("active@example.com")
+		if len(sender.messages()) != 2 || len(tokens.tokens) != 2 {
+			t.Fatalf("Expected a new email after the resend interval, got %d", len(sender.messages()))
+		}
+	})
+
+	t.Run("uses a token once and invalidates the others", func(t *testing.T) {
+		stale := tokens.tokens[0]
+		token := lastToken()
+		before := &auth.Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))}}
+
+		if err := svc.ResetPassword(ctx, ResetPasswordParams{Token: token, Password: "New-pass-1"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		user, _ := users.GetByID(ctx, 1)
+		if !utils.BcryptCheck("New-pass-1", user.Password) {
+			t.Fatal("Expected password changed")
+		}
+		if err := svc.ResetPassword(ctx, ResetPasswordParams{Token: token, Password: "Another-pass-1"}); !errors.Is(err, ErrInvalidResetToken) {
+			t.Fatalf("Expected ErrInvalidResetToken on reuse, got %v", err)
+		}
+		if stale.UsedAt == nil {
+			t.Fatal("Expected earlier token invalidated")
+		}
+		if len(refreshTokens.revokedUsers) != 1 || refreshTokens.revokedUsers[0] != 1 {
+			t.Fatalf("Expected refresh tokens of user 1 revoked, got %v", refreshTokens.revokedUsers)
+		}
+		if revoked, _ := revocations.IsRevoked(ctx, before); !revoked {
+			t.Fatal("Expected access tokens issued before the reset revoked")
+		}
+	})
+
+	t.Run("rejects unknown tokens", func(t *testing.T) {
+		if err := svc.ResetPassword(ctx, ResetPasswordParams{Token: "unknown", Password: "New-pass-2"}); !errors.Is(err, ErrInvalidResetToken) {
+			t.Fatalf("Expected ErrInvalidResetToken, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"minigo/internal/domain/entity"
+	apperrors "minigo/internal/domain/errors"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/auth"
+	"minigo/internal/infrastructure/tx"
+)
+
+// permissionCacheTTL 用户权限缓存有效期
+const permissionCacheTTL = time.Minute
+
+type permissionCacheEntry struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+// RoleService 角色与权限管理
+type RoleService struct {
+	roleRepo    repository.RoleRepository
+	userRepo    repository.UserRepository
+	revocations auth.RevocationStore
+	txManager   *tx.Manager
+
+	cache map[int64]permissionCacheEntry
+	mutex sync.RWMutex
+}
+
+// NewRoleService 创建角色服务实例
+func NewRoleService(
+	roleRepo repository.RoleRepository,
+	userRepo repository.UserRepository,
+	revocations auth.RevocationStore,
+	txManager *tx.Manager,
+) *RoleService {
+	return &RoleService{
+		roleRepo:    roleRepo,
+		userRepo:    userRepo,
+		revocations: revocations,
+		txManager:   txManager,
+		cache:       make(map[int64]permissionCacheEntry),
+	}
+}
+
+// ListRoles 获取全部角色及其权限
+func (s *RoleService) ListRoles(ctx context.Context) ([]*entity.Role, error) {
+	return s.roleRepo.List(ctx)
+}
+
+// GetUserRoles 获取用户已分配的角色
+func (s *RoleService) GetUserRoles(ctx context.Context, userID int64) ([]*entity.Role, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+	return s.roleRepo.ListByUserID(ctx, userID)
+}
+
+// GetUserPermissions 获取用户权限编码（带缓存）
+func (s *RoleService) GetUserPermissions(ctx context.Context, userID int64) ([]string, error) {
+	s.mutex.RLock()
+	entry, ok := s.cache[userID]
+	s.mutex.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.permissions, nil
+	}
+
+	permissions, err := s.roleRepo.ListPermissionCodesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	s.cache[userID] = permissionCacheEntry{
+		permissions: permissions,
+		expiresAt:   time.Now().Add(permissionCacheTTL),
+	}
+	s.mutex.Unlock()
+	return permissions, nil
+}
+
+// SetTwoFactorRequired 设置该角色的用户登录时是否必须完成两步验证，
+// 未绑定验证器的用户下次登录时需先完成绑定
+func (s *RoleService) SetTwoFactorRequired(ctx context.Context, roleCode string, required bool) error {
+	role, err := s.roleRepo.GetByCode(ctx, roleCode)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrResourceNotFound) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+	return s.roleRepo.SetRequireTwoFactor(ctx, role.ID, required)
+}
+
+// AssignRole 为用户分配角色
+func (s *RoleService) AssignRole(ctx context.Context, userID int64, roleCode string) error {
+	return s.changeUserRole(ctx, userID, roleCode, s.roleRepo.AssignToUser)
+}
+
+// RemoveRole 移除用户角色
+func (s *RoleService) RemoveRole(ctx context.Context, userID int64, roleCode string) error {
+	return s.changeUserRole(ctx, userID, roleCode, func(txCtx context.Context, userID, roleID int64) error {
+		if err := s.roleRepo.RemoveFromUser(txCtx, userID, roleID); err != nil {
+			if errors.Is(err, apperrors.ErrResourceNotFound) {
+				return ErrUserRoleNotFound
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// changeUserRole 变更用户角色，并使旧的访问令牌失效
+// 仅吊销访问令牌，客户端可用刷新令牌换取携带新权限的令牌。
+func (s *RoleService) changeUserRole(
+	ctx context.Context,
+	userID int64,
+	roleCode string,
+	change func(ctx context.Context, userID, roleID int64) error,
+) error {
+	if err := s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		if _, err := s.userRepo.GetByID(txCtx, userID); err != nil {
+			return ErrUserNotFound
+		}
+		role, err := s.roleRepo.GetByCode(txCtx, roleCode)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrResourceNotFound) {
+				return ErrRoleNotFound
+			}
+			return err
+		}
+		if err = change(txCtx, userID, role.ID); err != nil {
+			return err
+		}
+		return s.revocations.RevokeUser(txCtx, userID, time.Now())
+	}); err != nil {
+		return err
+	}
+
+	s.invalidate(userID)
+	return nil
+}
+
+// invalidate 清除用户权限缓存
+func (s *RoleService) invalidate(userID int64) {
+	s.mutex.Lock()
+	delete(s.cache, userID)
+	s.mutex.Unlock()
+}
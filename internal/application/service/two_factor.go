@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"minigo/internal/domain/entity"
+	apperrors "minigo/internal/domain/errors"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/auth"
+	"minigo/internal/infrastructure/config"
+)
+
+// TOTPSetup 待验证的 TOTP 密钥，客户端将 URI 渲染为二维码供验证器应用扫描
+type TOTPSetup struct {
+	Secret string
+	URI    string
+}
+
+// twoFactor 管理 TOTP 密钥与恢复码，登录第二步和账号设置共用
+type twoFactor struct {
+	repo          repository.TwoFactorRepository
+	secrets       *auth.SecretBox
+	issuer        string
+	recoveryCodes int
+}
+
+func newTwoFactor(repo repository.TwoFactorRepository, secrets *auth.SecretBox, cfg config.TwoFactorConfig) *twoFactor {
+	return &twoFactor{
+		repo:          repo,
+		secrets:       secrets,
+		issuer:        cfg.Issuer,
+		recoveryCodes: cfg.RecoveryCodes,
+	}
+}
+
+// secretContext 密文绑定所属用户，复制到其他用户的记录上无法解密
+func secretContext(userID int64) string {
+	return "user:" + strconv.FormatInt(userID, 10)
+}
+
+// requiresTwoFactor 任一角色要求两步验证
+func requiresTwoFactor(roles []*entity.Role) bool {
+	for _, role := range roles {
+		if role.RequireTwoFactor {
+			return true
+		}
+	}
+	return false
+}
+
+// setup 生成新的待验证密钥并替换尚未启用的旧密钥，已启用时返回 ErrTwoFactorAlreadyEnabled
+func (t *twoFactor) setup(ctx context.Context, user *entity.User) (*TOTPSetup, error) {
+	existing, err := t.repo.GetTOTPForUpdate(ctx, user.ID)
+	switch {
+	case err == nil && existing.IsEnabled():
+		return nil, ErrTwoFactorAlreadyEnabled
+	case err != nil && !errors.Is(err, apperrors.ErrResourceNotFound):
+		return nil, err
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := t.secrets.Seal(secret, secretContext(user.ID))
+	if err != nil {
+		return nil, err
+	}
+	if err = t.repo.SaveTOTP(ctx, &entity.UserTOTP{UserID: user.ID, Secret: sealed}); err != nil {
+		return nil, err
+	}
+	return &TOTPSetup{
+		Secret: secret,
+		URI:    auth.TOTPProvisioningURI(t.issuer, user.Phone, secret),
+	}, nil
+}
+
+// verify 校验 TOTP 验证码，已启用时也接受未使用的恢复码。
+// 通过的时间步被记录，同一验证码（及更早的验证码）不能再次使用。totp 需加锁读取。
+func (t *twoFactor) verify(ctx context.Context, totp *entity.UserTOTP, code string, now time.Time) (bool, error) {
+	secret, err := t.secrets.Open(totp.Secret, secretContext(totp.UserID))
+	if err != nil {
+		return false, err
+	}
+	if step, ok := auth.ValidateTOTP(secret, code, now); ok && step > totp.LastUsedStep {
+		totp.LastUsedStep = step
+		return true, t.repo.SaveTOTP(ctx, totp)
+	}
+	if !totp.IsEnabled() {
+		return false, nil
+	}
+	err = t.repo.UseRecoveryCode(ctx, totp.UserID, auth.HashRecoveryCode(code), now)
+	if errors.Is(err, apperrors.ErrResourceNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// enable 启用 TOTP 并生成首批恢复码
+func (t *twoFactor) enable(ctx context.Context, totp *entity.UserTOTP, now time.Time) ([]string, error) {
+	totp.EnabledAt = &now
+	if err := t.repo.SaveTOTP(ctx, totp); err != nil {
+		return nil, err
+	}
+	return t.regenerate(ctx, totp.UserID)
+}
+
+// regenerate 生成新的恢复码，旧的恢复码全部失效
+func (t *twoFactor) regenerate(ctx context.Context, userID int64) ([]string, error) {
+	codes, hashes, err := auth.NewRecoveryCodes(t.recoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
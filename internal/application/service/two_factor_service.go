@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"minigo/internal/domain/entity"
+	apperrors "minigo/internal/domain/errors"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/auth"
+	"minigo/internal/infrastructure/config"
+	"minigo/internal/infrastructure/tx"
+)
+
+// TwoFactorService 当前用户的两步验证设置与管理员重置
+type TwoFactorService struct {
+	userRepo   repository.UserRepository
+	roleRepo   repository.RoleRepository
+	twoFactors repository.TwoFactorRepository
+	twoFactor  *twoFactor
+	txManager  *tx.Manager
+}
+
+// NewTwoFactorService 创建两步验证服务实例，secrets 为 nil 时 TOTP 密钥以明文保存
+func NewTwoFactorService(
+	users repository.UserRepository,
+	roles repository.RoleRepository,
+	twoFactors repository.TwoFactorRepository,
+	txManager *tx.Manager,
+	secrets *auth.SecretBox,
+	cfg config.TwoFactorConfig,
+) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:   users,
+		roleRepo:   roles,
+		twoFactors: twoFactors,
+		twoFactor:  newTwoFactor(twoFactors, secrets, cfg),
+		txManager:  txManager,
+	}
+}
+
+// TwoFactorStatus 两步验证状态
+type TwoFactorStatus struct {
+	Enabled           bool
+	EnabledAt         *time.Time
+	Required          bool // 所属角色要求两步验证
+	RecoveryCodesLeft int
+}
+
+// Status 获取用户的两步验证状态
+func (s *TwoFactorService) Status(ctx context.Context, userID int64) (*TwoFactorStatus, error) {
+	roles, err := s.roleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &TwoFactorStatus{Required: requiresTwoFactor(roles)}
+
+	totp, err := s.twoFactors.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrResourceNotFound) {
+			return status, nil
+		}
+		return nil, err
+	}
+	if !totp.IsEnabled() {
+		return status, nil
+	}
+	status.Enabled = true
+	status.EnabledAt = totp.EnabledAt
+	if status.RecoveryCodesLeft, err = s.twoFactors.CountUnusedRecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Setup 生成待验证的 TOTP 密钥，调用 Enable 提交验证码后生效
+func (s *TwoFactorService) Setup(ctx context.Context, userID int64) (*TOTPSetup, error) {
+	var setup *TOTPSetup
+	err := s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		user, err := s.userRepo.GetByID(txCtx, userID)
+		if err != nil {
+			return ErrUserNotFound
+		}
+		setup, err = s.twoFactor.setup(txCtx, user)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return setup, nil
+}
+
+// Enable 校验验证器应用生成的验证码并启用两步验证，返回恢复码（仅此一次返回明文）
+func (s *TwoFactorService) Enable(ctx context.Context, userID int64, code string) ([]string, error) {
+	var codes []string
+	err := s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		totp, err := s.getTOTP(txCtx, userID, ErrTwoFactorSetupRequired)
+		if err != nil {
+			return err
+		}
+		if totp.IsEnabled() {
+			return ErrTwoFactorAlreadyEnabled
+		}
+		if err = s.verify(txCtx, totp, code); err != nil {
+			return err
+		}
+		codes, err = s.twoFactor.enable(txCtx, totp, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable 校验密码和验证码（或恢复码）后关闭两步验证，所属角色要求两步验证时不能关闭
+func (s *TwoFactorService) Disable(ctx context.Context, userID int64, password, code string) error {
+	return s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		user, err := s.userRepo.GetByID(txCtx, userID)
+		if err != nil {
+			return ErrUserNotFound
+		}
+		if user.CheckPassword(password) != nil {
+			return ErrInvalidCredentials
+		}
+		roles, err := s.roleRepo.ListByUserID(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		if requiresTwoFactor(roles) {
+			return ErrTwoFactorRequiredByRole
+		}
+		totp, err := s.getEnabledTOTP(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		if err = s.verify(txCtx, totp, code); err != nil {
+			return err
+		}
+		return s.twoFactors.DeleteTOTP(txCtx, userID)
+	})
+}
+
+// RegenerateRecoveryCodes 校验验证码后重新生成恢复码，旧的恢复码全部失效
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID int64, code string) ([]string, error) {
+	var codes []string
+	err := s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		totp, err := s.getEnabledTOTP(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		if err = s.verify(txCtx, totp, code); err != nil {
+			return err
+		}
+		codes, err = s.twoFactor.regenerate(txCtx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Reset 管理员清除用户的 TOTP 密钥和恢复码（如用户丢失设备），角色要求时用户下次登录需重新绑定
+func (s *TwoFactorService) Reset(ctx context.Context, userID int64) error {
+	return s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		if _, err := s.userRepo.GetByID(txCtx, userID); err != nil {
+			return ErrUserNotFound
+		}
+		if err := s.twoFactors.DeleteTOTP(txCtx, userID); err != nil {
+			if errors.Is(err, apperrors.ErrResourceNotFound) {
+				return ErrTwoFactorNotEnabled
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// getTOTP 加锁读取 TOTP 密钥，不存在时返回 notFound
+func (s *TwoFactorService) getTOTP(ctx context.Context, userID int64, notFound error) (*entity.UserTOTP, error) {
+	totp, err := s.twoFactors.GetTOTPForUpdate(ctx, userID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrResourceNotFound) {
+			return nil, notFound
+		}
+		return nil, err
+	}
+	return totp, nil
+}
+
+// getEnabledTOTP 加锁读取已启用的 TOTP 密钥
+func (s *TwoFactorService) getEnabledTOTP(ctx context.Context, userID int64) (*entity.UserTOTP, error) {
+	totp, err := s.getTOTP(ctx, userID, ErrTwoFactorNotEnabled)
+	if err != nil {
+		return nil, err
+	}
+	if !totp.IsEnabled() {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	return totp, nil
+}
+
+// verify 验证码错误时返回 ErrInvalidTwoFactorCode
+func (s *TwoFactorService) verify(ctx context.Context, totp *entity.UserTOTP, code string) error {
+	ok, err := s.twoFactor.verify(ctx, totp, code, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
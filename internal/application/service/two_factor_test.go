@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"minigo/internal/domain/entity"
+	apperrors "minigo/internal/domain/errors"
+	"minigo/internal/infrastructure/auth"
+	"minigo/internal/infrastructure/config"
+)
+
+// memoryTwoFactors 内存实现，仅覆盖 twoFactor 用到的方法
+type memoryTwoFactors struct {
+	totps map[int64]*entity.UserTOTP
+	codes map[string]*entity.RecoveryCode
+}
+
+func (m *memoryTwoFactors) GetTOTP(ctx context.Context, userID int64) (*entity.UserTOTP, error) {
+	t, ok := m.totps[userID]
+	if !ok {
+		return nil, apperrors.ErrResourceNotFound
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (m *memoryTwoFactors) GetTOTPForUpdate(ctx context.Context, userID int64) (*entity.UserTOTP, error) {
+	return m.GetTOTP(ctx, userID)
+}
+
+func (m *memoryTwoFactors) SaveTOTP(ctx context.Context, totp *entity.UserTOTP) error {
+	copied := *totp
+	m.totps[totp.UserID] = &copied
+	return nil
+}
+
+func (m *memoryTwoFactors) DeleteTOTP(ctx context.Context, userID int64) error {
+	delete(m.totps, userID)
+	return nil
+}
+
+func (m *memoryTwoFactors) ReplaceRecoveryCodes(ctx context.Context, userID int64, hashes []string) error {
+	m.codes = make(map[string]*entity.RecoveryCode)
+	for _, hash := range hashes {
+		m.codes[hash] = &entity.RecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	return nil
+}
+
+func (m *memoryTwoFactors) UseRecoveryCode(ctx context.Context, userID int64, hash string, usedAt time.Time) error {
+	code, ok := m.codes[hash]
+	if !ok || code.UserID != userID || code.UsedAt != nil {
+		return apperrors.ErrResourceNotFound
+	}
+	code.UsedAt = &usedAt
+	return nil
+}
+
+func (m *memoryTwoFactors) CountUnusedRecoveryCodes(ctx context.Context, userID int64) (int, error) {
+	n := 0
+	for _, code := range m.codes {
+		if code.UserID == userID && code.UsedAt == nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memoryTwoFactors) CreateChallenge(ctx context.Context, challenge *entity.TwoFactorChallenge) error {
+	return nil
+}
+
+func (m *memoryTwoFactors) GetChallengeByHashForUpdate(ctx context.Context, tokenHash string) (*entity.TwoFactorChallenge, error) {
+	return nil, apperrors.ErrResourceNotFound
+}
+
+func (m *memoryTwoFactors) IncrementChallengeAttempts(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (m *memoryTwoFactors) MarkChallengeUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	return nil
+}
+
+func (m *memoryTwoFactors) PurgeExpiredChallenges(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+func TestTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryTwoFactors{totps: make(map[int64]*entity.UserTOTP)}
+	box, err := auth.NewSecretBox(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	tf := newTwoFactor(repo, box, config.TwoFactorConfig{Issuer: "minigo", RecoveryCodes: 3})
+	user := &entity.User{ID: 1, Phone: "13800000000"}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	setup, err := tf.setup(ctx, user)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	code := func(at time.Time) string {
+		c, err := auth.TOTPCode(setup.Secret, at)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return c
+	}
+	verify := func(c string) bool {
+		totp, _ := repo.GetTOTPForUpdate(ctx, user.ID)
+		ok, err := tf.verify(ctx, totp, c, now)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return ok
+	}
+
+	t.Run("seals the secret for the user", func(t *testing.T) {
+		if stored := repo.totps[user.ID].Secret; stored == setup.Secret {
+			t.Fatal("Expected secret sealed at rest")
+		}
+		if _, err := box.Open(repo.totps[user.ID].Secret, secretContext(2)); err == nil {
+			t.Fatal("Expected secret bound to its owner")
+		}
+	})
+
+	var recovery []string
+	t.Run("enables with a valid code and rejects replay", func(t *testing.T) {
+		if !verify(code(now)) {
+			t.Fatal("Expected current code accepted")
+		}
+		totp, _ := repo.GetTOTPForUpdate(ctx, user.ID)
+		if recovery, err = tf.enable(ctx, totp, now); err != nil || len(recovery) != 3 {
+			t.Fatalf("Expected 3 recovery codes, got %v (%v)", recovery, err)
+		}
+		if verify(code(now)) {
+			t.Fatal("Expected reused code rejected")
+		}
+		if verify(code(now.Add(-30 * time.Second))) {
+			t.Fatal("Expected earlier code rejected")
+		}
+		if !verify(code(now.Add(30 * time.Second))) {
+			t.Fatal("Expected next code accepted within skew")
+		}
+	})
+
+	t.Run("accepts each recovery code once", func(t *testing.T) {
+		if !verify(recovery[0]) {
+			t.Fatal("Expected recovery code accepted")
+		}
+		if verify(recovery[0]) {
+			t.Fatal("Expected used recovery code rejected")
+		}
+		if left, _ := repo.CountUnusedRecoveryCodes(ctx, user.ID); left != 2 {
+			t.Fatalf("Expected 2 recovery codes left, got %d", left)
+		}
+	})
+
+	t.Run("refuses setup while enabled", func(t *testing.T) {
+		if _, err := tf.setup(ctx, user); !errors.Is(err, ErrTwoFactorAlreadyEnabled) {
+			t.Fatalf("Expected ErrTwoFactorAlreadyEnabled, got %v", err)
+		}
+	})
+}
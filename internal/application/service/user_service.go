@@ -2,8 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
+
 	"minigo/internal/domain/entity"
+	apperrors "minigo/internal/domain/errors"
 	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/auth"
 	"minigo/internal/infrastructure/id"
 	"minigo/internal/infrastructure/tx"
 
@@ -11,18 +16,30 @@ import (
 )
 
 type UserService struct {
-	userRepo  repository.UserRepository
-	txManager *tx.Manager
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	revocations      auth.RevocationStore
+	verification     *VerificationService
+	passwords        *PasswordPolicyService
+	txManager        *tx.Manager
 }
 
 // NewUserService 创建用户服务实例
 func NewUserService(
 	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	revocations auth.RevocationStore,
+	verification *VerificationService,
+	passwords *PasswordPolicyService,
 	txManager *tx.Manager,
 ) *UserService {
 	return &UserService{
-		userRepo:  userRepo,
-		txManager: txManager,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		revocations:      revocations,
+		verification:     verification,
+		passwords:        passwords,
+		txManager:        txManager,
 	}
 }
 
@@ -30,6 +47,7 @@ func NewUserService(
 type CreateUserParams struct {
 	Name       string
 	Phone      string
+	Email      string // 可选，用于找回密码
 	Password   string
 	Status     int16
 	ReferrerID *int64 // 邀请人ID
@@ -37,33 +55,140 @@ type CreateUserParams struct {
 
 // CreateUser 创建用户
 func (s *UserService) CreateUser(ctx context.Context, params CreateUserParams) (*entity.User, error) {
+	return s.createUser(ctx, params, nil)
+}
+
+// createUser 创建用户，inTx 非空时在插入之后、同一事务中执行（如消费注册验证码）
+func (s *UserService) createUser(ctx context.Context, params CreateUserParams, inTx func(txCtx context.Context) error) (*entity.User, error) {
 	// 定义变量
 	var (
 		err  error
 		user *entity.User
 	)
+	// 按密码策略校验
+	if err = s.passwords.Check(ctx, &entity.User{Phone: params.Phone}, fieldPassword, params.Password); err != nil {
+		return nil, err
+	}
 	// 构造用户实体
 	user = &entity.User{
-		ID:       id.NextID(),
-		Name:     params.Name,
-		Phone:    params.Phone,
-		Password: params.Password,
-		Status:   params.Status,
+		ID:                id.NextID(),
+		Name:              params.Name,
+		Phone:             params.Phone,
+		Email:             params.Email,
+		PasswordChangedAt: time.Now(),
+		Status:            params.Status,
 	}
+	user.SetPassword(params.Password)
 	// 在事务中执行
 	if err = s.txManager.InTx(ctx, func(txCtx context.Context) error {
 		// 先检查
-
+		if err := s.checkPhoneAvailable(txCtx, params.Phone, 0); err != nil {
+			return err
+		}
+		if err := s.checkEmailAvailable(txCtx, params.Email, 0); err != nil {
+			return err
+		}
 		// 再添加
-		return s.userRepo.Create(txCtx, user)
+		if err := s.userRepo.Create(txCtx, user); err != nil {
+			return err
+		}
+		if err := s.passwords.record(txCtx, user); err != nil {
+			return err
+		}
+		if inTx != nil {
+			return inTx(txCtx)
+		}
+		return nil
 	}); err != nil {
-		return nil, err
+		return nil, convertUserWriteError(err)
 	}
 
 	// 返回结果
 	return user, nil
 }
 
+// RegisterParams 用户注册参数
+type RegisterParams struct {
+	Name     string
+	Phone    string
+	Password string
+	Code     string // 短信验证码，VERIFY_CODE_REQUIRE_FOR_REGISTER 开启时必填
+}
+
+// Register 用户注册，提供验证码（或配置要求验证）时先校验手机号归属。
+// 验证码与用户在同一事务中消费，密码不符合策略或手机号已注册时验证码仍可使用。
+func (s *UserService) Register(ctx context.Context, params RegisterParams) (*entity.User, error) {
+	if params.Code == "" && s.verification.cfg.RequireForRegister {
+		return nil, ErrVerificationCodeRequired
+	}
+	var consume func(txCtx context.Context) error
+	if params.Code != "" {
+		verified, err := s.verification.check(ctx, params.Phone, entity.VerifyPurposeRegister, params.Code)
+		if err != nil {
+			return nil, err
+		}
+		consume = func(txCtx context.Context) error {
+			return s.verification.consume(txCtx, verified)
+		}
+	}
+	return s.createUser(ctx, CreateUserParams{
+		Name:     params.Name,
+		Phone:    params.Phone,
+		Password: params.Password,
+	}, consume)
+}
+
+// BatchCreateFailure 批量创建失败的行
+type BatchCreateFailure struct {
+	Params CreateUserParams
+	Err    error
+}
+
+// BatchCreateResult 批量创建结果
+type BatchCreateResult struct {
+	SuccessCount int
+	Failures     []BatchCreateFailure
+}
+
+// BatchCreateUsers 批量创建用户，每一行独立事务，失败的行不影响其他行
+func (s *UserService) BatchCreateUsers(ctx context.Context, items []CreateUserParams) *BatchCreateResult {
+	result := &BatchCreateResult{}
+	seen := make(map[string]bool, len(items))
+	for _, params := range items {
+		// 同一批次内手机号重复
+		if seen[params.Phone] {
+			result.Failures = append(result.Failures, BatchCreateFailure{Params: params, Err: ErrUserExists})
+			continue
+		}
+		seen[params.Phone] = true
+
+		if _, err := s.CreateUser(ctx, params); err != nil {
+			result.Failures = append(result.Failures, BatchCreateFailure{Params: params, Err: err})
+			continue
+		}
+		result.SuccessCount++
+	}
+	return result
+}
+
+// ListUsersParams 用户列表查询参数
+type ListUsersParams struct {
+	Keyword string
+	Status  *int16
+	Page    int
+	Size    int
+}
+
+// ListUsers 分页查询用户
+func (s *UserService) ListUsers(ctx context.Context, params ListUsersParams) ([]*entity.User, int, error) {
+	return s.userRepo.List(ctx, repository.UserListFilter{
+		Keyword: params.Keyword,
+		Status:  params.Status,
+		Offset:  (params.Page - 1) * params.Size,
+		Limit:   params.Size,
+	})
+}
+
 func (s *UserService) GetUserByID(ctx context.Context, id int64) (*entity.User, error) {
 	return s.userRepo.GetByID(ctx, id)
 }
@@ -72,6 +197,7 @@ func (s *UserService) GetUserByID(ctx context.Context, id int64) (*entity.User,
 type UpdateUserParams struct {
 	Name          string
 	Phone         string
+	Email         string
 	Status        *int16
 	IsAdmin       *bool
 	Remark        *string
@@ -98,17 +224,35 @@ func (s *UserService) UpdateUser(ctx context.Context, id int64, params UpdateUse
 		if user == nil {
 			return ErrUserNotFound
 		}
+		if user.Phone != params.Phone {
+			if err = s.checkPhoneAvailable(txCtx, params.Phone, id); err != nil {
+				return err
+			}
+		}
+		if err = s.checkEmailAvailable(txCtx, params.Email, id); err != nil {
+			return err
+		}
 
 		// 更新用户基本信息
 		user.Name = params.Name
 		user.Phone = params.Phone
+		user.Email = params.Email
+		disabling := false
 		if params.Status != nil {
+			disabling = user.Status != entity.StatusDisabled && *params.Status == entity.StatusDisabled
 			user.Status = *params.Status
 		}
 		// 更新用户实体
-		return s.userRepo.Update(txCtx, user)
+		if err = s.userRepo.Update(txCtx, user); err != nil {
+			return err
+		}
+		// 停用用户时吊销其所有令牌
+		if disabling {
+			return s.revokeUserTokens(txCtx, id)
+		}
+		return nil
 	}); err != nil {
-		return err
+		return convertUserWriteError(err)
 	}
 
 	// 返回结果
@@ -131,12 +275,230 @@ func (s *UserService) ChangePassword(ctx context.Context, id int64, oldPassword,
 		if err = user.CheckPassword(oldPassword); err != nil {
 			return err
 		}
-		// 使用实体的领域方法设置密码，然后通过通用 Update 方法持久化
-		user.Password = newPassword
+		if err = s.setPassword(txCtx, user, fieldNewPassword, newPassword); err != nil {
+			return err
+		}
+		// 修改密码后吊销所有已签发的令牌
+		return s.revokeUserTokens(txCtx, id)
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setPassword 按密码策略校验后设置新密码并记录到历史密码，需在事务中调用
+func (s *UserService) setPassword(ctx context.Context, user *entity.User, field, password string) error {
+	if err := s.passwords.Check(ctx, user, field, password); err != nil {
+		return err
+	}
+	user.SetPassword(password)
+	user.PasswordChangedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	return s.passwords.record(ctx, user)
+}
+
+// UpdateProfileParams 用户修改自己的资料参数
+type UpdateProfileParams struct {
+	Name  string
+	Phone string // 仅允许为空或当前手机号
+	Email string // 为空表示清除邮箱
+}
+
+// UpdateProfile 用户修改自己的资料，更换手机号需通过 ChangePhone 验证新手机号
+func (s *UserService) UpdateProfile(ctx context.Context, id int64, params UpdateProfileParams) error {
+	if err := s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		user, err := s.userRepo.GetForUpdate(txCtx, id)
+		if err != nil {
+			return ErrUserNotFound
+		}
+		if params.Phone != "" && params.Phone != user.Phone {
+			return ErrPhoneVerificationRequired
+		}
+		if err = s.checkEmailAvailable(txCtx, params.Email, id); err != nil {
+			return err
+		}
+		user.Name = params.Name
+		user.Email = params.Email
 		return s.userRepo.Update(txCtx, user)
 	}); err != nil {
+		return convertUserWriteError(err)
+	}
+	return nil
+}
+
+// ChangePhone 校验发送到新手机号的验证码后更换手机号
+func (s *UserService) ChangePhone(ctx context.Context, id int64, phone, code string) error {
+	verified, err := s.verification.check(ctx, phone, entity.VerifyPurposeChangePhone, code)
+	if err != nil {
+		return err
+	}
+	if err = s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		user, err := s.userRepo.GetForUpdate(txCtx, id)
+		if err != nil {
+			return ErrUserNotFound
+		}
+		if err = s.checkPhoneAvailable(txCtx, phone, id); err != nil {
+			return err
+		}
+		user.Phone = phone
+		if err = s.userRepo.Update(txCtx, user); err != nil {
+			return err
+		}
+		// 更换成功后才消费验证码
+		return s.verification.consume(txCtx, verified)
+	}); err != nil {
+		return convertUserWriteError(err)
+	}
+	return nil
+}
+
+// ResetPasswordByCode 忘记密码时凭短信验证码设置新密码，并吊销其所有令牌。
+// 新密码不符合策略时验证码不会被消费。
+func (s *UserService) ResetPasswordByCode(ctx context.Context, phone, code, password string) error {
+	verified, err := s.verification.check(ctx, phone, entity.VerifyPurposeResetPassword, code)
+	if err != nil {
+		return err
+	}
+	return s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		existing, err := s.userRepo.GetByPhone(txCtx, phone)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrResourceNotFound) {
+				return ErrInvalidVerificationCode
+			}
+			return err
+		}
+		user, err := s.userRepo.GetForUpdate(txCtx, existing.ID)
+		if err != nil {
+			return ErrUserNotFound
+		}
+		if err = s.setPassword(txCtx, user, fieldNewPassword, password); err != nil {
+			return err
+		}
+		if err = s.revokeUserTokens(txCtx, user.ID); err != nil {
+			return err
+		}
+		return s.verification.consume(txCtx, verified)
+	})
+}
+
+// SetUserStatus 启用/停用用户，停用时吊销其所有令牌
+func (s *UserService) SetUserStatus(ctx context.Context, id int64, status int16) error {
+	return s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		user, err := s.userRepo.GetForUpdate(txCtx, id)
+		if err != nil {
+			return ErrUserNotFound
+		}
+		if user.Status == status {
+			return nil
+		}
+		user.Status = status
+		if err = s.userRepo.Update(txCtx, user); err != nil {
+			return err
+		}
+		if status == entity.StatusDisabled {
+			return s.revokeUserTokens(txCtx, id)
+		}
+		return nil
+	})
+}
+
+// ResetPassword 管理员重置用户密码，并吊销其所有令牌
+func (s *UserService) ResetPassword(ctx context.Context, id int64, password string) error {
+	return s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		user, err := s.userRepo.GetForUpdate(txCtx, id)
+		if err != nil {
+			return ErrUserNotFound
+		}
+		if err = s.setPassword(txCtx, user, fieldPassword, password); err != nil {
+			return err
+		}
+		return s.revokeUserTokens(txCtx, id)
+	})
+}
+
+// DeleteUser 软删除用户，并吊销其所有令牌
+func (s *UserService) DeleteUser(ctx context.Context, id int64) error {
+	return s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.Delete(txCtx, id); err != nil {
+			if errors.Is(err, apperrors.ErrResourceNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		return s.revokeUserTokens(txCtx, id)
+	})
+}
+
+// RevokeUserTokens 强制用户下线：吊销其所有访问令牌和刷新令牌
+func (s *UserService) RevokeUserTokens(ctx context.Context, id int64) error {
+	if _, err := s.userRepo.GetByID(ctx, id); err != nil {
+		return ErrUserNotFound
+	}
+	return s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		return s.revokeUserTokens(txCtx, id)
+	})
+}
+
+// checkPhoneAvailable 检查手机号是否已被其他用户使用
+func (s *UserService) checkPhoneAvailable(ctx context.Context, phone string, excludeID int64) error {
+	existing, err := s.userRepo.GetByPhone(ctx, phone)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrResourceNotFound) {
+			return nil
+		}
 		return err
 	}
+	if existing.ID != excludeID {
+		return ErrUserExists
+	}
+	return nil
+}
 
+// checkEmailAvailable 检查邮箱是否已被其他用户使用，空邮箱不检查
+func (s *UserService) checkEmailAvailable(ctx context.Context, email string, excludeID int64) error {
+	if email == "" {
+		return nil
+	}
+	existing, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrResourceNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != excludeID {
+		return ErrEmailExists
+	}
 	return nil
 }
+
+// 用户唯一索引（见 migrations/001_init.up.sql、010_password_reset.up.sql）
+const (
+	userPhoneUniqueIndex = "uk_users_shop_phone"
+	userEmailUniqueIndex = "uk_users_email"
+)
+
+// convertUserWriteError 将手机号、邮箱唯一索引冲突转换为 ErrUserExists、ErrEmailExists
+// 并发注册/修改时预检查可能通过，最终以数据库约束为准。
+func convertUserWriteError(err error) error {
+	if appErr, ok := apperrors.AsAppError(err); ok && errors.Is(appErr, apperrors.ErrDuplicateResource) {
+		switch appErr.Details {
+		case userPhoneUniqueIndex:
+			return ErrUserExists
+		case userEmailUniqueIndex:
+			return ErrEmailExists
+		}
+	}
+	return err
+}
+
+// revokeUserTokens 吊销用户当前时间之前签发的所有令牌
+func (s *UserService) revokeUserTokens(ctx context.Context, id int64) error {
+	if err := s.refreshTokenRepo.RevokeByUserID(ctx, id); err != nil {
+		return err
+	}
+	return s.revocations.RevokeUser(ctx, id, time.Now())
+}
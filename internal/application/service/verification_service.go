@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"minigo/internal/domain/entity"
+	apperrors "minigo/internal/domain/errors"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/config"
+	"minigo/internal/infrastructure/id"
+	"minigo/internal/infrastructure/logging"
+	"minigo/internal/infrastructure/sms"
+	"minigo/internal/infrastructure/tx"
+	"minigo/pkg/validator"
+)
+
+// dailyWindow VERIFY_CODE_DAILY_LIMIT 的统计窗口
+const dailyWindow = 24 * time.Hour
+
+// VerificationCooldownError 同一手机号同一用途的验证码发送间隔未到，RetryAfter 为需等待的时间。
+// errors.Is(err, ErrVerificationCodeTooFrequent) 成立。
+type VerificationCooldownError struct {
+	RetryAfter time.Duration
+}
+
+func (e *VerificationCooldownError) Error() string {
+	return fmt.Sprintf("%s (retry after %s)", ErrVerificationCodeTooFrequent.Error(), e.RetryAfter)
+}
+
+func (e *VerificationCooldownError) Unwrap() error {
+	return ErrVerificationCodeTooFrequent
+}
+
+// VerificationService 短信验证码的发送与校验
+type VerificationService struct {
+	codeRepo   repository.VerificationCodeRepository
+	userRepo   repository.UserRepository
+	sender     sms.Sender
+	deliveries *DeliveryQueue
+	txManager  *tx.Manager
+	hashKey    []byte // VERIFY_CODE_HASH_KEY，为空时退化为不带密钥的哈希（仅限 dev）
+	cfg        config.VerifyCodeConfig
+}
+
+// NewVerificationService 创建短信验证码服务实例
+func NewVerificationService(
+	codeRepo repository.VerificationCodeRepository,
+	userRepo repository.UserRepository,
+	sender sms.Sender,
+	deliveries *DeliveryQueue,
+	txManager *tx.Manager,
+	hashKey []byte,
+	cfg config.VerifyCodeConfig,
+) *VerificationService {
+	return &VerificationService{
+		codeRepo:   codeRepo,
+		userRepo:   userRepo,
+		sender:     sender,
+		deliveries: deliveries,
+		txManager:  txManager,
+		hashKey:    hashKey,
+		cfg:        cfg,
+	}
+}
+
+// SendCodeParams 发送验证码参数
+type SendCodeParams struct {
+	Phone   string
+	Purpose string
+	IP      string
+}
+
+// SendCodeResult 验证码有效期与可再次发送前的等待时间
+type SendCodeResult struct {
+	ExpiresIn   time.Duration
+	ResendAfter time.Duration
+}
+
+// SendCode 生成并发送验证码。同一手机号同一用途在 VERIFY_CODE_RESEND_INTERVAL 内不能重复发送，
+// 24 小时内最多发送 VERIFY_CODE_DAILY_LIMIT 次。
+// 用途与手机号注册状态不符（如注册已存在的手机号）时不发送短信，但响应和频率限制保持一致；
+// 短信在后台发送，发送失败只记录日志，调用方无法据响应内容或时间判断手机号是否已注册。
+func (s *VerificationService) SendCode(ctx context.Context, params SendCodeParams) (*SendCodeResult, error) {
+	now := time.Now()
+	if !validator.IsPhone(params.Phone) {
+		return nil, ErrInvalidPhone
+	}
+	deliver, err := s.shouldDeliver(ctx, params.Phone, params.Purpose)
+	if err != nil {
+		return nil, err
+	}
+	code, err := newVerificationCode(s.cfg.Length)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		latest, err := s.codeRepo.GetLatestForUpdate(txCtx, params.Phone, params.Purpose)
+		switch {
+		case err == nil:
+			if wait := latest.CreatedAt.Add(s.cfg.ResendInterval).Sub(now); wait > 0 {
+				return &VerificationCooldownError{RetryAfter: wait}
+			}
+		case !errors.Is(err, apperrors.ErrResourceNotFound):
+			return err
+		}
+		sent, err := s.codeRepo.CountSince(txCtx, params.Phone, now.Add(-dailyWindow))
+		if err != nil {
+			return err
+		}
+		if sent >= s.cfg.DailyLimit {
+			return ErrVerificationCodeDailyLimit
+		}
+		return s.codeRepo.Create(txCtx, &entity.VerificationCode{
+			ID:        id.NextID(),
+			Phone:     params.Phone,
+			Purpose:   params.Purpose,
+			CodeHash:  hashVerificationCode(s.hashKey, params.Phone, params.Purpose, code),
+			IP:        params.IP,
+			ExpiresAt: now.Add(s.cfg.TTL),
+			CreatedAt: now,
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	log := logging.FromContext(ctx).WithFields(map[string]interface{}{
+		"phone":   params.Phone,
+		"purpose": params.Purpose,
+	})
+	if deliver {
+		msg := sms.Message{
+			Phone:    params.Phone,
+			Template: sms.TemplateVerifyCode,
+			Params:   map[string]string{"code": code, "minutes": fmt.Sprint(int(s.cfg.TTL.Minutes()))},
+		}
+		s.deliveries.run(ctx, func(ctx context.Context) {
+			if err := s.sender.Send(ctx, msg); err != nil {
+				log.WithError(err).Error("verification_code_send_failed")
+			}
+		})
+	} else {
+		log.Info("verification_code_not_delivered")
+	}
+	return &SendCodeResult{ExpiresIn: s.cfg.TTL, ResendAfter: s.cfg.ResendInterval}, nil
+}
+
+// Verify 校验并消费最近发送的验证码，每个验证码只能使用一次。
+// 在独立事务中执行，失败次数即时提交，调用方应在自己的业务事务之外调用。
+// 需要与业务写入一起提交时使用 check + consume。
+func (s *VerificationService) Verify(ctx context.Context, phone, purpose, code string) error {
+	verified, err := s.check(ctx, phone, purpose, code)
+	if err != nil {
+		return err
+	}
+	return s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		return s.consume(txCtx, verified)
+	})
+}
+
+// check 校验最近发送的验证码但不消费，失败次数在独立事务中即时提交。
+// 通过后调用方在业务事务中调用 consume，业务失败回滚时验证码仍可再次使用。
+func (s *VerificationService) check(ctx context.Context, phone, purpose, code string) (*entity.VerificationCode, error) {
+	var (
+		latest *entity.VerificationCode
+		failed bool
+		now    = time.Now()
+	)
+	if err := s.txManager.InTx(ctx, func(txCtx context.Context) error {
+		var err error
+		latest, err = s.codeRepo.GetLatestForUpdate(txCtx, phone, purpose)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrResourceNotFound) {
+				return ErrInvalidVerificationCode
+			}
+			return err
+		}
+		if !latest.IsUsable(now, s.cfg.MaxAttempts) {
+			return ErrInvalidVerificationCode
+		}
+		// 失败次数需要提交，因此不在此处返回错误
+		if subtle.ConstantTimeCompare([]byte(latest.CodeHash), []byte(hashVerificationCode(s.hashKey, phone, purpose, code))) != 1 {
+			failed = true
+			return s.codeRepo.IncrementAttempts(txCtx, latest.ID)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if failed {
+		return nil, ErrInvalidVerificationCode
+	}
+	return latest, nil
+}
+
+// consume 在调用方事务中消费 check 通过的验证码（需在事务上下文中使用）。
+// 加锁重新读取，期间已被使用或已有新验证码时返回 ErrInvalidVerificationCode。
+func (s *VerificationService) consume(ctx context.Context, verified *entity.VerificationCode) error {
+	now := time.Now()
+	latest, err := s.codeRepo.GetLatestForUpdate(ctx, verified.Phone, verified.Purpose)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrResourceNotFound) {
+			return ErrInvalidVerificationCode
+		}
+		return err
+	}
+	if latest.ID != verified.ID || !latest.IsUsable(now, s.cfg.MaxAttempts) {
+		return ErrInvalidVerificationCode
+	}
+	return s.codeRepo.MarkUsed(ctx, latest.ID, now)
+}
+
+// shouldDeliver 注册和更换手机号只发给未注册的手机号，登录和重置密码只发给已注册的手机号
+func (s *VerificationService) shouldDeliver(ctx context.Context, phone, purpose string) (bool, error) {
+	registered := true
+	if _, err := s.userRepo.GetByPhone(ctx, phone); err != nil {
+		if !errors.Is(err, apperrors.ErrResourceNotFound) {
+			return false, err
+		}
+		registered = false
+	}
+	switch purpose {
+	case entity.VerifyPurposeRegister, entity.VerifyPurposeChangePhone:
+		return !registered, nil
+	case entity.VerifyPurposeLogin, entity.VerifyPurposeResetPassword:
+		return registered, nil
+	}
+	return false, ErrInvalidVerifyPurpose
+}
+
+// newVerificationCode 生成 length 位数字验证码
+func newVerificationCode(length int) (string, error) {
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+	return string(code), nil
+}
+
+// hashVerificationCode 使用服务端密钥计算 HMAC-SHA256，绑定手机号和用途，验证码不能跨手机号或用途使用。
+// 验证码只有 10^6 种取值，不带密钥的哈希可被能读取数据库的人直接穷举。
+func hashVerificationCode(key []byte, phone, purpose, code string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(phone + ":" + purpose + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"minigo/internal/domain/entity"
+	"minigo/internal/infrastructure/auth"
+	"minigo/internal/infrastructure/config"
+	"minigo/internal/infrastructure/sms"
+	"minigo/pkg/utils"
+)
+
+func TestVerificationCode(t *testing.T) {
+	t.Run("generates numeric codes of the configured length", func(t *testing.T) {
+		seen := make(map[string]bool)
+		for i := 0; i < 50; i++ {
+			code, err := newVerificationCode(6)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(code) != 6 || code != stringOfDigits(code) {
+				t.Fatalf("Expected 6 digits, got %q", code)
+			}
+			seen[code] = true
+		}
+		if len(seen) < 45 {
+			t.Fatalf("Expected random codes, got %d distinct of 50", len(seen))
+		}
+	})
+
+	t.Run("binds the hash to phone and purpose", func(t *testing.T) {
+		key := []byte("verify-code-hash-key")
+		h := hashVerificationCode(key, "13800000000", entity.VerifyPurposeLogin, "123456")
+		if h == hashVerificationCode(key, "13900000000", entity.VerifyPurposeLogin, "123456") ||
+			h == hashVerificationCode(key, "13800000000", entity.VerifyPurposeRegister, "123456") {
+			t.Fatal("Expected hash to differ across phones and purposes")
+		}
+		if h == hashVerificationCode([]byte("another-key"), "13800000000", entity.VerifyPurposeLogin, "123456") {
+			t.Fatal("Expected hash to depend on the key")
+		}
+	})
+
+	t.Run("expires after use, timeout or too many attempts", func(t *testing.T) {
+		now := time.Now()
+		code := entity.VerificationCode{ExpiresAt: now.Add(time.Minute)}
+		if !code.IsUsable(now, 3) {
+			t.Fatal("Expected fresh code usable")
+		}
+		code.Attempts = 3
+		if code.IsUsable(now, 3) {
+			t.Fatal("Expected code unusable after max attempts")
+		}
+		code.Attempts = 0
+		if code.IsUsable(now.Add(time.Minute), 3) {
+			t.Fatal("Expected expired code unusable")
+		}
+		code.UsedAt = &now
+		if code.IsUsable(now, 3) {
+			t.Fatal("Expected used code unusable")
+		}
+	})
+
+	t.Run("reports cooldown as too frequent", func(t *testing.T) {
+		err := error(&VerificationCooldownError{RetryAfter: 30 * time.Second})
+		if !errors.Is(err, ErrVerificationCodeTooFrequent) {
+			t.Fatalf("Expected ErrVerificationCodeTooFrequent, got %v", err)
+		}
+	})
+}
+
+// testCodeKey 测试使用的验证码哈希密钥
+var testCodeKey = []byte("test-verify-code-key")
+
+func TestVerificationCodeConsumedWithWrite(t *testing.T) {
+	ctx := context.Background()
+	txManager := newTestTxManager()
+	users := newMemoryUsers(
+		&entity.User{ID: 1, Phone: "13800000001", Password: utils.BcryptHash("Old-pass-1")},
+		&entity.User{ID: 2, Phone: "13800000002"},
+	)
+	codes := &memoryVerificationCodes{}
+	verification := NewVerificationService(codes, users, nil, NewDeliveryQueue(), txManager, testCodeKey, config.VerifyCodeConfig{MaxAttempts: 3})
+	policy := NewPasswordPolicyService(&memoryPasswordHistory{}, nil, config.PasswordPolicyConfig{MinLength: 8, MaxLength: 64})
+	svc := NewUserService(users, &memoryRefreshTokens{}, auth.NewMemoryRevocationStore(), verification, policy, txManager)
+
+	issue := func(phone, purpose string) *entity.VerificationCode {
+		code := &entity.VerificationCode{
+			ID:        int64(len(codes.codes) + 1),
+			Phone:     phone,
+			Purpose:   purpose,
+			CodeHash:  hashVerificationCode(testCodeKey, phone, purpose, "123456"),
+			ExpiresAt: time.Now().Add(time.Minute),
+		}
+		codes.codes = append(codes.codes, code)
+		return code
+	}
+
+	t.Run("register keeps the code when the password is rejected", func(t *testing.T) {
+		code := issue("13900000000", entity.VerifyPurposeRegister)
+		_, err := svc.Register(ctx, RegisterParams{Name: "a", Phone: "13900000000", Password: "short", Code: "123456"})
+		if !errors.Is(err, ErrPasswordPolicy) {
+			t.Fatalf("Expected ErrPasswordPolicy, got %v", err)
+		}
+		if code.UsedAt != nil {
+			t.Fatal("Expected code not consumed")
+		}
+		if _, err = svc.Register(ctx, RegisterParams{Name: "a", Phone: "13900000000", Password: "Blue-sky42", Code: "123456"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if code.UsedAt == nil {
+			t.Fatal("Expected code consumed after registration")
+		}
+	})
+
+	t.Run("change phone keeps the code when the phone is taken", func(t *testing.T) {
+		code := issue("13800000002", entity.VerifyPurposeChangePhone)
+		if err := svc.ChangePhone(ctx, 1, "13800000002", "123456"); !errors.Is(err, ErrUserExists) {
+			t.Fatalf("Expected ErrUserExists, got %v", err)
+		}
+		if code.UsedAt != nil || code.Attempts != 0 {
+			t.Fatalf("Expected code untouched, got %+v", code)
+		}
+	})
+
+	t.Run("reset keeps the code when the password is rejected", func(t *testing.T) {
+		code := issue("13800000001", entity.VerifyPurposeResetPassword)
+		if err := svc.ResetPasswordByCode(ctx, "13800000001", "123456", "Old-pass-1"); err != nil {
+			t.Fatalf("Expected no error without history, got %v", err)
+		}
+		if code.UsedAt == nil {
+			t.Fatal("Expected code consumed after reset")
+		}
+		code = issue("13800000001", entity.VerifyPurposeResetPassword)
+		if err := svc.ResetPasswordByCode(ctx, "13800000001", "123456", "1234"); !errors.Is(err, ErrPasswordPolicy) {
+			t.Fatalf("Expected ErrPasswordPolicy, got %v", err)
+		}
+		if code.UsedAt != nil {
+			t.Fatal("Expected code not consumed")
+		}
+	})
+
+	t.Run("wrong codes still count attempts", func(t *testing.T) {
+		code := issue("13800000001", entity.VerifyPurposeResetPassword)
+		if err := svc.ResetPasswordByCode(ctx, "13800000001", "000000", "Blue-sky42"); !errors.Is(err, ErrInvalidVerificationCode) {
+			t.Fatalf("Expected ErrInvalidVerificationCode, got %v", err)
+		}
+		if code.Attempts != 1 || code.UsedAt != nil {
+			t.Fatalf("Expected one failed attempt, got %+v", code)
+		}
+	})
+}
+
+// blockingSMS 发送阻塞到 release 关闭，然后返回 err
+type blockingSMS struct {
+	release chan struct{}
+	sent    []sms.Message
+	err     error
+}
+
+func (b *blockingSMS) Send(ctx context.Context, msg sms.Message) error {
+	<-b.release
+	b.sent = append(b.sent, msg)
+	return b.err
+}
+
+func TestSendCodeUniform(t *testing.T) {
+	ctx := context.Background()
+	users := newMemoryUsers(&entity.User{ID: 1, Phone: "13800000001"})
+	sender := &blockingSMS{release: make(chan struct{}), err: errors.New("provider down")}
+	svc := NewVerificationService(&memoryVerificationCodes{}, users, sender, NewDeliveryQueue(), newTestTxManager(), testCodeKey, config.VerifyCodeConfig{
+		Length:         6,
+		TTL:            5 * time.Minute,
+		ResendInterval: time.Minute,
+		DailyLimit:     10,
+		MaxAttempts:    3,
+	})
+
+	// 已注册（发送且发送失败）与未注册（不发送）的手机号返回相同结果，且不等待发送
+	registered, err := svc.SendCode(ctx, SendCodeParams{Phone: "13800000001", Purpose: entity.VerifyPurposeLogin})
+	if err != nil {
+		t.Fatalf("Expected no error for registered phone, got %v", err)
+	}
+	unknown, err := svc.SendCode(ctx, SendCodeParams{Phone: "13800000002", Purpose: entity.VerifyPurposeLogin})
+	if err != nil {
+		t.Fatalf("Expected no error for unknown phone, got %v", err)
+	}
+	if *registered != *unknown {
+		t.Fatalf("Expected identical results, got %+v and %+v", registered, unknown)
+	}
+
+	close(sender.release)
+	svc.deliveries.wait()
+	if len(sender.sent) != 1 || sender.sent[0].Phone != "13800000001" {
+		t.Fatalf("Expected one message to the registered phone, got %+v", sender.sent)
+	}
+}
+
+// stringOfDigits 返回 s 中的数字字符
+func stringOfDigits(s string) string {
+	var out []byte
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			out = append(out, s[i])
+		}
+	}
+	return string(out)
+}
@@ -11,3 +11,14 @@ const (
 	StatusNormal   = 0
 	StatusDisabled = 1
 )
+
+// Permission constants（格式：资源:操作）
+const (
+	PermUserRead  = "user:read"
+	PermUserWrite = "user:write"
+	PermRoleRead  = "role:read"
+	PermRoleWrite = "role:write"
+	// 系统运维（日志级别等运行时设置）
+	PermSystemRead  = "system:read"
+	PermSystemWrite = "system:write"
+)
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// 登录锁定记录的动作
+const (
+	LockoutActionLocked   = "locked"
+	LockoutActionUnlocked = "unlocked"
+)
+
+// LoginThrottle 登录失败计数，Subject 为 phone:<手机号> 或 ip:<IP>
+type LoginThrottle struct {
+	bun.BaseModel `bun:"table:login_throttles,alias:lt"`
+
+	Subject      string     `bun:"subject,pk" json:"subject"`
+	Failures     int        `bun:"failures,notnull" json:"failures"`
+	LastFailedAt time.Time  `bun:"last_failed_at,notnull" json:"last_failed_at"`
+	LockedUntil  *time.Time `bun:"locked_until,nullzero" json:"locked_until,omitempty"`
+}
+
+// IsLocked - 是否处于锁定期
+func (t *LoginThrottle) IsLocked(now time.Time) bool {
+	return t.LockedUntil != nil && now.Before(*t.LockedUntil)
+}
+
+// LoginLockoutEvent 登录锁定与解锁记录
+type LoginLockoutEvent struct {
+	bun.BaseModel `bun:"table:login_lockout_events,alias:lle"`
+
+	ID          int64      `bun:"id,pk" json:"id,string"`
+	Subject     string     `bun:"subject,notnull" json:"subject"`
+	Action      string     `bun:"action,notnull" json:"action"`
+	Failures    int        `bun:"failures,notnull" json:"failures"`
+	LockedUntil *time.Time `bun:"locked_until,nullzero" json:"locked_until,omitempty"`
+	IP          string     `bun:"ip,notnull" json:"ip"`
+	OperatorID  *int64     `bun:"operator_id,nullzero" json:"operator_id,string,omitempty"`
+	CreatedAt   time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// PasswordHistory 用户使用过的密码（bcrypt 哈希）
+type PasswordHistory struct {
+	bun.BaseModel `bun:"table:password_history,alias:ph"`
+
+	ID           int64     `bun:"id,pk" json:"id,string"`
+	UserID       int64     `bun:"user_id,notnull" json:"user_id,string"`
+	PasswordHash string    `bun:"password_hash,notnull" json:"-"`
+	CreatedAt    time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// PasswordResetToken 邮件找回密码的一次性令牌（仅保存哈希值）
+type PasswordResetToken struct {
+	bun.BaseModel `bun:"table:password_reset_tokens,alias:prt"`
+
+	ID        int64      `bun:"id,pk" json:"id,string"`
+	UserID    int64      `bun:"user_id,notnull" json:"user_id,string"`
+	TokenHash string     `bun:"token_hash,notnull" json:"-"`
+	IP        string     `bun:"ip,notnull" json:"ip"`
+	ExpiresAt time.Time  `bun:"expires_at,notnull" json:"expires_at"`
+	UsedAt    *time.Time `bun:"used_at,nullzero" json:"used_at,omitempty"`
+	CreatedAt time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// IsUsable - 未使用且未过期
+func (t *PasswordResetToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// RefreshToken 刷新令牌（仅保存哈希值）
+type RefreshToken struct {
+	bun.BaseModel `bun:"table:refresh_tokens,alias:rt"`
+
+	ID        int64      `bun:"id,pk" json:"id,string"`
+	UserID    int64      `bun:"user_id,notnull" json:"user_id,string"`
+	FamilyID  string     `bun:"family_id,notnull" json:"family_id"`
+	TokenHash string     `bun:"token_hash,notnull" json:"-"`
+	ExpiresAt time.Time  `bun:"expires_at,notnull" json:"expires_at"`
+	UsedAt    *time.Time `bun:"used_at,nullzero" json:"used_at,omitempty"`
+	RevokedAt *time.Time `bun:"revoked_at,nullzero" json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// IsExpired - 是否已过期
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// IsUsed - 是否已被轮换使用
+func (t *RefreshToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+// IsRevoked - 是否已被吊销
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type Role struct {
+	bun.BaseModel `bun:"table:roles,alias:r"`
+
+	ID          int64  `bun:"id,pk" json:"id,string"`
+	Code        string `bun:"code,notnull" json:"code"`
+	Name        string `bun:"name,notnull" json:"name"`
+	Description string `bun:"description,notnull" json:"description"`
+	// RequireTwoFactor 该角色的用户登录时必须完成两步验证
+	RequireTwoFactor bool      `bun:"require_two_factor,notnull" json:"require_two_factor"`
+	CreatedAt        time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt        time.Time `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+
+	// -- 关系
+	Permissions []*Permission `bun:"m2m:role_permissions,join:Role=Permission" json:"permissions,omitempty"`
+}
+
+type Permission struct {
+	bun.BaseModel `bun:"table:permissions,alias:p"`
+
+	ID        int64     `bun:"id,pk" json:"id,string"`
+	Code      string    `bun:"code,notnull" json:"code"`
+	Name      string    `bun:"name,notnull" json:"name"`
+	CreatedAt time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// RolePermission 角色-权限关联
+type RolePermission struct {
+	bun.BaseModel `bun:"table:role_permissions,alias:rp"`
+
+	RoleID       int64       `bun:"role_id,pk"`
+	Role         *Role       `bun:"rel:belongs-to,join:role_id=id"`
+	PermissionID int64       `bun:"permission_id,pk"`
+	Permission   *Permission `bun:"rel:belongs-to,join:permission_id=id"`
+}
+
+// UserRole 用户-角色关联
+type UserRole struct {
+	bun.BaseModel `bun:"table:user_roles,alias:ur"`
+
+	UserID    int64     `bun:"user_id,pk"`
+	RoleID    int64     `bun:"role_id,pk"`
+	CreatedAt time.Time `bun:"created_at,notnull,default:current_timestamp"`
+}
+
+// PrimaryRole 返回写入令牌的主角色：拥有管理员角色即为 admin，否则为 user
+func PrimaryRole(roles []*Role) string {
+	for _, role := range roles {
+		if role.Code == RoleAdmin {
+			return RoleAdmin
+		}
+	}
+	return RoleUser
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// UserTOTP 用户 TOTP 密钥，EnabledAt 为空表示已生成但尚未验证
+type UserTOTP struct {
+	bun.BaseModel `bun:"table:user_totp,alias:ut"`
+
+	UserID       int64      `bun:"user_id,pk" json:"user_id,string"`
+	Secret       string     `bun:"secret,notnull" json:"-"`
+	EnabledAt    *time.Time `bun:"enabled_at,nullzero" json:"enabled_at,omitempty"`
+	LastUsedStep int64      `bun:"last_used_step,notnull" json:"-"`
+	CreatedAt    time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt    time.Time  `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// IsEnabled - 是否已启用
+func (t *UserTOTP) IsEnabled() bool {
+	return t.EnabledAt != nil
+}
+
+// RecoveryCode 两步验证恢复码（仅保存哈希值）
+type RecoveryCode struct {
+	bun.BaseModel `bun:"table:user_recovery_codes,alias:urc"`
+
+	ID        int64      `bun:"id,pk" json:"id,string"`
+	UserID    int64      `bun:"user_id,notnull" json:"user_id,string"`
+	CodeHash  string     `bun:"code_hash,notnull" json:"-"`
+	UsedAt    *time.Time `bun:"used_at,nullzero" json:"used_at,omitempty"`
+	CreatedAt time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// TwoFactorChallenge 登录第二步挑战（仅保存哈希值）
+type TwoFactorChallenge struct {
+	bun.BaseModel `bun:"table:two_factor_challenges,alias:tfc"`
+
+	ID        int64      `bun:"id,pk" json:"id,string"`
+	UserID    int64      `bun:"user_id,notnull" json:"user_id,string"`
+	TokenHash string     `bun:"token_hash,notnull" json:"-"`
+	Attempts  int        `bun:"attempts,notnull" json:"attempts"`
+	ExpiresAt time.Time  `bun:"expires_at,notnull" json:"expires_at"`
+	UsedAt    *time.Time `bun:"used_at,nullzero" json:"used_at,omitempty"`
+	CreatedAt time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// IsUsable - 未使用、未过期且验证次数未超过 maxAttempts
+func (c *TwoFactorChallenge) IsUsable(now time.Time, maxAttempts int) bool {
+	return c.UsedAt == nil && now.Before(c.ExpiresAt) && c.Attempts < maxAttempts
+}
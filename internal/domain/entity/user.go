@@ -16,12 +16,15 @@ type User struct {
 	ID        int64      `bun:"id,pk,autoincrement" json:"id,string"`
 	Name      string     `bun:"name,notnull" json:"name"`
 	Phone     string     `bun:"phone,notnull" json:"phone"`
+	Email     string     `bun:"email,nullzero" json:"email,omitempty"` // 可选，用于找回密码
 	Password  string     `bun:"password,notnull" json:"-"`
 	Status    int16      `bun:"status,notnull,default:0" json:"status"`
 	DeletedAt *time.Time `bun:"deleted_at,soft_delete,nullzero" json:"deleted_at,omitempty"`
 	CreatedAt time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
 	UpdatedAt time.Time  `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
 
+	PasswordChangedAt time.Time `bun:"password_changed_at,notnull,default:current_timestamp" json:"password_changed_at"`
+
 	// -- 关系
 }
 
@@ -31,25 +34,28 @@ var _ bun.BeforeUpdateHook = (*User)(nil)
 // BeforeInsert - 插入前处理
 func (u *User) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
 	account := query.GetModel().Value().(*User)
-	account.BcryptPassword()
 	account.Name = strings.ToLower(account.Name)
+	account.Email = NormalizeEmail(account.Email)
 	return nil
 }
 
 // BeforeUpdate - 更新前处理
 func (u *User) BeforeUpdate(ctx context.Context, query *bun.UpdateQuery) error {
 	account := query.GetModel().Value().(*User)
-	account.BcryptPassword()
 	account.Name = strings.ToLower(account.Name)
+	account.Email = NormalizeEmail(account.Email)
 	return nil
 }
 
-// BcryptPassword - 对密码进行加密处理
-func (u *User) BcryptPassword() {
-	// 使用BcryptHash进行加密
-	if u.Password != "" {
-		u.Password = utils.BcryptHash(u.Password)
-	}
+// NormalizeEmail - 邮箱忽略大小写和首尾空白
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// SetPassword - 设置新密码，保存其 bcrypt 哈希
+// 明文总是重新加密，即使它看起来已经是哈希值，避免用户直接提交哈希绕过密码策略
+func (u *User) SetPassword(password string) {
+	u.Password = utils.BcryptHash(password)
 }
 
 // CheckPassword - 校验密码是否一致
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"testing"
+
+	"minigo/pkg/utils"
+)
+
+func TestUserSetPassword(t *testing.T) {
+	t.Run("hashes the plaintext", func(t *testing.T) {
+		var u User
+		u.SetPassword("Blue-sky42")
+		if u.Password == "Blue-sky42" || u.CheckPassword("Blue-sky42") != nil {
+			t.Fatalf("Expected stored bcrypt hash of the password, got %q", u.Password)
+		}
+	})
+
+	t.Run("re-hashes a hash-shaped password", func(t *testing.T) {
+		// 直接提交 "123456" 的哈希不能让 "123456" 成为可登录的密码
+		submitted := utils.BcryptHash("123456")
+		var u User
+		u.SetPassword(submitted)
+		if u.Password == submitted {
+			t.Fatal("Expected hash-shaped password to be hashed again")
+		}
+		if u.CheckPassword("123456") == nil {
+			t.Fatal("Expected the hashed plaintext not to be accepted")
+		}
+		if u.CheckPassword(submitted) != nil {
+			t.Fatal("Expected the submitted value itself to be the password")
+		}
+	})
+}
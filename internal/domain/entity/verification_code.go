@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// 验证码用途，同一手机号不同用途的验证码互不影响
+const (
+	VerifyPurposeRegister      = "register"
+	VerifyPurposeLogin         = "login"
+	VerifyPurposeResetPassword = "reset_password"
+	VerifyPurposeChangePhone   = "change_phone"
+)
+
+// VerificationCode 短信验证码（仅保存哈希值）
+type VerificationCode struct {
+	bun.BaseModel `bun:"table:verification_codes,alias:vc"`
+
+	ID        int64      `bun:"id,pk" json:"id,string"`
+	Phone     string     `bun:"phone,notnull" json:"phone"`
+	Purpose   string     `bun:"purpose,notnull" json:"purpose"`
+	CodeHash  string     `bun:"code_hash,notnull" json:"-"`
+	Attempts  int        `bun:"attempts,notnull" json:"attempts"`
+	IP        string     `bun:"ip,notnull" json:"ip"`
+	ExpiresAt time.Time  `bun:"expires_at,notnull" json:"expires_at"`
+	UsedAt    *time.Time `bun:"used_at,nullzero" json:"used_at,omitempty"`
+	CreatedAt time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// IsUsable - 未使用、未过期且校验次数未超过 maxAttempts
+func (c *VerificationCode) IsUsable(now time.Time, maxAttempts int) bool {
+	return c.UsedAt == nil && now.Before(c.ExpiresAt) && c.Attempts < maxAttempts
+}
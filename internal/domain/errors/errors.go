@@ -20,15 +20,25 @@ const (
 	AuthError ErrorType = "AUTH_ERROR"
 	// NotFoundError 资源不存在错误
 	NotFoundError ErrorType = "NOT_FOUND_ERROR"
+	// TooManyRequestsError 请求过于频繁（登录失败次数过多等）
+	TooManyRequestsError ErrorType = "TOO_MANY_REQUESTS_ERROR"
 )
 
+// FieldError 字段级错误，Field 为请求中的字段名
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 // AppError 应用错误结构
 type AppError struct {
-	Type    ErrorType `json:"type"`
-	Code    string    `json:"code"`
-	Message string    `json:"message"`
-	Details string    `json:"details,omitempty"`
-	Cause   error     `json:"-"`
+	Type    ErrorType    `json:"type"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details string       `json:"details,omitempty"`
+	Fields  []FieldError `json:"fields,omitempty"`
+	Cause   error        `json:"-"`
 }
 
 // Error 实现error接口
@@ -52,6 +62,13 @@ func (e *AppError) Is(target error) bool {
 	return false
 }
 
+// WithFields 返回附带字段级错误的副本，预定义错误本身不被修改
+func (e *AppError) WithFields(fields ...FieldError) *AppError {
+	copied := *e
+	copied.Fields = append([]FieldError(nil), fields...)
+	return &copied
+}
+
 // GetHTTPStatus 根据错误类型返回HTTP状态码
 func (e *AppError) GetHTTPStatus() int {
 	switch e.Type {
@@ -61,6 +78,8 @@ func (e *AppError) GetHTTPStatus() int {
 		return http.StatusUnauthorized
 	case NotFoundError:
 		return http.StatusNotFound
+	case TooManyRequestsError:
+		return http.StatusTooManyRequests
 	case BusinessError:
 		return http.StatusBadRequest
 	case SystemError:
@@ -118,6 +137,15 @@ func NewNotFoundError(code, message string) *AppError {
 	}
 }
 
+// NewTooManyRequestsError 创建请求过于频繁错误
+func NewTooManyRequestsError(code, message string) *AppError {
+	return &AppError{
+		Type:    TooManyRequestsError,
+		Code:    code,
+		Message: message,
+	}
+}
+
 // 预定义的通用错误
 
 var (
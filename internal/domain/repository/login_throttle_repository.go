@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"minigo/internal/domain/entity"
+)
+
+// LockoutEventFilter 登录锁定记录查询条件
+type LockoutEventFilter struct {
+	Subject string // 为空查询全部
+	Offset  int
+	Limit   int
+}
+
+type LoginThrottleRepository interface {
+	// ListBySubjects returns the throttles of the subjects that have failures or locks.
+	ListBySubjects(ctx context.Context, subjects []string) ([]*entity.LoginThrottle, error)
+
+	// RecordFailure 原子地累加一次失败并返回最新计数；上次失败早于 resetBefore 时从 1 重新计数
+	RecordFailure(ctx context.Context, subject string, at, resetBefore time.Time) (*entity.LoginThrottle, error)
+
+	// Lock 锁定到 until 并清零失败次数
+	Lock(ctx context.Context, subject string, until time.Time) error
+
+	// Delete removes the failure counts and locks of the subjects.
+	Delete(ctx context.Context, subjects ...string) error
+
+	// PurgeStale 删除最后一次失败早于 before 且锁定已到期的计数
+	PurgeStale(ctx context.Context, before time.Time) error
+
+	// CreateEvent records a lockout or unlock event.
+	CreateEvent(ctx context.Context, event *entity.LoginLockoutEvent) error
+
+	// ListEvents returns a page of events matching the filter, newest first, and the total count.
+	ListEvents(ctx context.Context, filter LockoutEventFilter) ([]*entity.LoginLockoutEvent, int, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"minigo/internal/domain/entity"
+)
+
+type PasswordHistoryRepository interface {
+	// Create persists a password hash the user has set.
+	Create(ctx context.Context, history *entity.PasswordHistory) error
+
+	// ListRecent 返回用户最近 limit 条历史密码，按时间倒序
+	ListRecent(ctx context.Context, userID int64, limit int) ([]*entity.PasswordHistory, error)
+
+	// Prune 只保留用户最近 keep 条历史密码
+	Prune(ctx context.Context, userID int64, keep int) error
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"minigo/internal/domain/entity"
+)
+
+type PasswordResetTokenRepository interface {
+	// Create persists a new reset token.
+	Create(ctx context.Context, token *entity.PasswordResetToken) error
+
+	// GetLatestByUser 返回用户最近申请的重置令牌
+	GetLatestByUser(ctx context.Context, userID int64) (*entity.PasswordResetToken, error)
+
+	// GetByHashForUpdate 加悲观锁按哈希读取令牌（需在事务上下文中使用）
+	GetByHashForUpdate(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error)
+
+	// InvalidateByUser marks all unused tokens of the user as used.
+	InvalidateByUser(ctx context.Context, userID int64, usedAt time.Time) error
+
+	// PurgeExpired 删除 before 之前已过期的令牌
+	PurgeExpired(ctx context.Context, before time.Time) error
+}
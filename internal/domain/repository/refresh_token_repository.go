@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"minigo/internal/domain/entity"
+)
+
+type RefreshTokenRepository interface {
+	// Create persists a new refresh token.
+	Create(ctx context.Context, token *entity.RefreshToken) error
+
+	// GetByHashForUpdate 按哈希加悲观锁读取令牌（需在事务上下文中使用）
+	GetByHashForUpdate(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+
+	// MarkUsed marks the token as rotated.
+	MarkUsed(ctx context.Context, id int64, usedAt time.Time) error
+
+	// RevokeFamily revokes every token in the family that is not revoked yet.
+	RevokeFamily(ctx context.Context, familyID string) error
+
+	// RevokeByUserID revokes every active token of the user.
+	RevokeByUserID(ctx context.Context, userID int64) error
+
+	// PurgeExpired 删除 before 之前已全部过期或已吊销的令牌族。
+	// 令牌族中仍有未过期的令牌时保留已轮换的令牌，用于重放检测。
+	PurgeExpired(ctx context.Context, before time.Time) error
+}
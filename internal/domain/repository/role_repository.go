@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"minigo/internal/domain/entity"
+)
+
+type RoleRepository interface {
+	// List returns all roles with their permissions.
+	List(ctx context.Context) ([]*entity.Role, error)
+
+	// GetByCode returns role by code.
+	GetByCode(ctx context.Context, code string) (*entity.Role, error)
+
+	// ListByUserID returns roles assigned to the user.
+	ListByUserID(ctx context.Context, userID int64) ([]*entity.Role, error)
+
+	// ListPermissionCodesByUserID returns distinct permission codes granted to the user.
+	ListPermissionCodesByUserID(ctx context.Context, userID int64) ([]string, error)
+
+	// AssignToUser grants the role to the user (idempotent).
+	AssignToUser(ctx context.Context, userID, roleID int64) error
+
+	// RemoveFromUser revokes the role from the user.
+	RemoveFromUser(ctx context.Context, userID, roleID int64) error
+
+	// SetRequireTwoFactor sets whether users with the role must complete two-factor login.
+	SetRequireTwoFactor(ctx context.Context, roleID int64, required bool) error
+}
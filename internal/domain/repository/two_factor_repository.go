@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"minigo/internal/domain/entity"
+)
+
+type TwoFactorRepository interface {
+	// GetTOTP returns the TOTP secret of the user.
+	GetTOTP(ctx context.Context, userID int64) (*entity.UserTOTP, error)
+
+	// GetTOTPForUpdate 加悲观锁读取 TOTP 密钥（需在事务上下文中使用）
+	GetTOTPForUpdate(ctx context.Context, userID int64) (*entity.UserTOTP, error)
+
+	// SaveTOTP creates or replaces the TOTP secret of the user.
+	SaveTOTP(ctx context.Context, totp *entity.UserTOTP) error
+
+	// DeleteTOTP removes the TOTP secret and the recovery codes of the user.
+	DeleteTOTP(ctx context.Context, userID int64) error
+
+	// ReplaceRecoveryCodes 删除旧的恢复码并保存新的恢复码哈希
+	ReplaceRecoveryCodes(ctx context.Context, userID int64, hashes []string) error
+
+	// UseRecoveryCode 将未使用的恢复码标记为已使用，不存在或已使用时返回 ErrResourceNotFound
+	UseRecoveryCode(ctx context.Context, userID int64, hash string, usedAt time.Time) error
+
+	// CountUnusedRecoveryCodes returns how many recovery codes the user has left.
+	CountUnusedRecoveryCodes(ctx context.Context, userID int64) (int, error)
+
+	// CreateChallenge persists a new login challenge.
+	CreateChallenge(ctx context.Context, challenge *entity.TwoFactorChallenge) error
+
+	// GetChallengeByHashForUpdate 按哈希加悲观锁读取挑战（需在事务上下文中使用）
+	GetChallengeByHashForUpdate(ctx context.Context, tokenHash string) (*entity.TwoFactorChallenge, error)
+
+	// IncrementChallengeAttempts records a failed verification.
+	IncrementChallengeAttempts(ctx context.Context, id int64) error
+
+	// MarkChallengeUsed marks the challenge as consumed.
+	MarkChallengeUsed(ctx context.Context, id int64, usedAt time.Time) error
+
+	// PurgeExpiredChallenges 删除 before 之前已过期的登录挑战
+	PurgeExpiredChallenges(ctx context.Context, before time.Time) error
+}
@@ -5,6 +5,14 @@ import (
 	"minigo/internal/domain/entity"
 )
 
+// UserListFilter 用户列表查询条件
+type UserListFilter struct {
+	Keyword string // 匹配用户名或手机号
+	Status  *int16
+	Offset  int
+	Limit   int
+}
+
 type UserRepository interface {
 	// Create persists a new user.
 	Create(ctx context.Context, user *entity.User) error
@@ -18,9 +26,15 @@ type UserRepository interface {
 	// GetByPhone returns user by phone.
 	GetByPhone(ctx context.Context, phone string) (*entity.User, error)
 
+	// GetByEmail returns user by email, ignoring case.
+	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+
+	// List returns a page of users matching the filter and the total count.
+	List(ctx context.Context, filter UserListFilter) ([]*entity.User, int, error)
+
 	// GetForUpdate 加悲观锁读取用户（需在事务上下文中使用）
 	GetForUpdate(ctx context.Context, id int64) (*entity.User, error)
 
-	// Delete removes user by id.
+	// Delete soft-deletes user by id.
 	Delete(ctx context.Context, id int64) error
 }
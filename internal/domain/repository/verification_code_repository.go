@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"minigo/internal/domain/entity"
+)
+
+type VerificationCodeRepository interface {
+	// Create persists a new verification code.
+	Create(ctx context.Context, code *entity.VerificationCode) error
+
+	// GetLatest 返回手机号某用途最近发送的验证码
+	GetLatest(ctx context.Context, phone, purpose string) (*entity.VerificationCode, error)
+
+	// GetLatestForUpdate 加悲观锁读取最近发送的验证码（需在事务上下文中使用）
+	GetLatestForUpdate(ctx context.Context, phone, purpose string) (*entity.VerificationCode, error)
+
+	// CountSince returns how many codes were sent to the phone since the given time.
+	CountSince(ctx context.Context, phone string, since time.Time) (int, error)
+
+	// IncrementAttempts records a failed verification.
+	IncrementAttempts(ctx context.Context, id int64) error
+
+	// MarkUsed marks the code as consumed.
+	MarkUsed(ctx context.Context, id int64, usedAt time.Time) error
+
+	// PurgeSentBefore 删除 before 之前发送的验证码
+	PurgeSentBefore(ctx context.Context, before time.Time) error
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"os"
+	"strings"
+)
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// PasswordBlocklist 常见弱密码列表，忽略大小写精确匹配
+type PasswordBlocklist struct {
+	entries map[string]struct{}
+}
+
+// NewPasswordBlocklist 加载内置的常见密码列表，extraFile 非空时追加其中的条目（每行一个，# 开头为注释）
+func NewPasswordBlocklist(extraFile string) (*PasswordBlocklist, error) {
+	b := &PasswordBlocklist{entries: make(map[string]struct{})}
+	if err := b.load(strings.NewReader(commonPasswords)); err != nil {
+		return nil, err
+	}
+	if extraFile == "" {
+		return b, nil
+	}
+	f, err := os.Open(extraFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err = b.load(f); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *PasswordBlocklist) load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		b.entries[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// Contains reports whether the password is on the list. nil 表示不检查。
+func (b *PasswordBlocklist) Contains(password string) bool {
+	if b == nil {
+		return false
+	}
+	_, ok := b.entries[strings.ToLower(password)]
+	return ok
+}
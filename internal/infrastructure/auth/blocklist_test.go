@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPasswordBlocklist(t *testing.T) {
+	t.Run("matches built-in entries ignoring case", func(t *testing.T) {
+		b, err := NewPasswordBlocklist("")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, pw := range []string{"password", "PassWord", "12345678"} {
+			if !b.Contains(pw) {
+				t.Fatalf("Expected %q to be blocked", pw)
+			}
+		}
+		if b.Contains("k7#Vq9!mZ2") {
+			t.Fatalf("Expected random password not to be blocked")
+		}
+	})
+
+	t.Run("appends entries from the extra file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "extra.txt")
+		if err := os.WriteFile(path, []byte("# company words\nMinigo2024\n\n"), 0o600); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		b, err := NewPasswordBlocklist(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !b.Contains("minigo2024") || !b.Contains("qwerty123") {
+			t.Fatalf("Expected extra and built-in entries to be blocked")
+		}
+		if b.Contains("# company words") {
+			t.Fatalf("Expected comments to be skipped")
+		}
+	})
+
+	t.Run("missing file is an error", func(t *testing.T) {
+		if _, err := NewPasswordBlocklist(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+			t.Fatalf("Expected error for missing file")
+		}
+	})
+
+	t.Run("nil blocklist blocks nothing", func(t *testing.T) {
+		var b *PasswordBlocklist
+		if b.Contains("password") {
+			t.Fatalf("Expected nil blocklist to allow everything")
+		}
+	})
+}
@@ -1,42 +1,132 @@
 package auth
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"minigo/internal/infrastructure/config"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+func init() {
+	// iat/exp 使用毫秒精度（RFC 7519 允许小数），按用户吊销后立即签发的令牌才能与吊销时间区分
+	jwt.TimePrecision = time.Millisecond
+}
+
 type Claims struct {
-	UserID   int64  `json:"userId"`
-	UserRole string `json:"userRole"`
+	UserID      int64    `json:"userId"`
+	UserRole    string   `json:"userRole"`
+	Permissions []string `json:"perms,omitempty"`
+	// PasswordExpired 密码已超过最长使用期限，令牌只能用于修改密码
+	PasswordExpired bool `json:"pwdExpired,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID int64, userRole string, ttl time.Duration) (string, error) {
-	secret := config.GetJWTSecret()
+// HasPermission reports whether the token carries the permission.
+func (c *Claims) HasPermission(perm string) bool {
+	for _, p := range c.Permissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenIssuer signs and verifies access tokens. 使用 JWT_KEYS_DIR 中的非对称密钥，
+// 未配置时回退到 HS256 + JWT_SECRET。创建后不再修改，可并发使用。
+type TokenIssuer struct {
+	keys   *KeyManager // nil 表示使用 HS256
+	secret []byte
+}
+
+// NewTokenIssuer 使用给定的密钥创建签发器，keys 为 nil 时使用 HS256 + secret
+func NewTokenIssuer(secret []byte, keys *KeyManager) *TokenIssuer {
+	return &TokenIssuer{keys: keys, secret: secret}
+}
+
+// LoadTokenIssuer loads signing keys from JWT_KEYS_DIR when configured, otherwise
+// signs with the HS256 secret.
+func LoadTokenIssuer(cfg config.JWTConfig) (*TokenIssuer, error) {
+	if cfg.KeysDir == "" {
+		return NewTokenIssuer([]byte(cfg.Secret), nil), nil
+	}
+	m, err := LoadKeyManager(cfg.KeysDir, cfg.ActiveKID)
+	if err != nil {
+		return nil, err
+	}
+	return NewTokenIssuer([]byte(cfg.Secret), m), nil
+}
+
+// JWKS 返回验签公钥集合，HS256 模式下为空集合
+func (t *TokenIssuer) JWKS() JWKSet {
+	if t.keys == nil {
+		return JWKSet{Keys: []JWK{}}
+	}
+	return t.keys.JWKS()
+}
+
+func (t *TokenIssuer) GenerateToken(userID int64, userRole string, permissions []string, ttl time.Duration) (string, error) {
+	return t.GenerateTokenWithClaims(Claims{
+		UserID:      userID,
+		UserRole:    userRole,
+		Permissions: permissions,
+	}, ttl)
+}
+
+// GenerateTokenWithClaims signs claims after filling in the jti, issue and expiry times.
+func (t *TokenIssuer) GenerateTokenWithClaims(claims Claims, ttl time.Duration) (string, error) {
 	if ttl <= 0 {
 		ttl = 24 * time.Hour
 	}
-	claims := Claims{
-		UserID:   userID,
-		UserRole: userRole,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+
+	if t.keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString(t.secret)
+	}
+
+	key := t.keys.ActiveKey()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.KID
+	return token.SignedString(key.Private)
 }
 
-func ParseToken(tokenStr string) (*Claims, error) {
-	secret := config.GetJWTSecret()
+func (t *TokenIssuer) ParseToken(tokenStr string) (*Claims, error) {
 	var claims Claims
+
+	if t.keys == nil {
+		_, err := jwt.ParseWithClaims(tokenStr, &claims, func(token *jwt.Token) (interface{}, error) {
+			return t.secret, nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+		if err != nil {
+			return nil, err
+		}
+		return &claims, nil
+	}
+
+	m := t.keys
 	_, err := jwt.ParseWithClaims(tokenStr, &claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("missing kid header")
+		}
+		key, ok := m.Key(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+		// 防止算法混淆：令牌算法必须与密钥算法一致
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %q for kid %q", token.Method.Alg(), kid)
+		}
+		return key.Public, nil
+	}, jwt.WithValidMethods(m.Methods()))
 	if err != nil {
 		return nil, err
 	}
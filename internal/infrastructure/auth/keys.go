@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey 带 kid 的签名密钥
+// 已退役的密钥可以只提供公钥，仅用于验签。
+type SigningKey struct {
+	KID     string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// CanSign reports whether the key holds a private key.
+func (k *SigningKey) CanSign() bool {
+	return k.Private != nil
+}
+
+// KeyManager 管理多把签名密钥：新令牌使用活动密钥签名，所有已加载的密钥都可验签
+type KeyManager struct {
+	keys   map[string]*SigningKey
+	active string
+}
+
+// NewKeyManager 创建密钥管理器，activeKID 必须对应一把私钥
+func NewKeyManager(keys []*SigningKey, activeKID string) (*KeyManager, error) {
+	m := &KeyManager{keys: make(map[string]*SigningKey, len(keys))}
+	var signers []string
+	for _, key := range keys {
+		if _, exists := m.keys[key.KID]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", key.KID)
+		}
+		m.keys[key.KID] = key
+		if key.CanSign() {
+			signers = append(signers, key.KID)
+		}
+	}
+
+	// 未指定活动密钥时，仅在只有一把私钥的情况下自动选择
+	if activeKID == "" {
+		if len(signers) != 1 {
+			return nil, errors.New("active key id is required when zero or multiple private keys are loaded")
+		}
+		activeKID = signers[0]
+	}
+	active, ok := m.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("active key %q not found", activeKID)
+	}
+	if !active.CanSign() {
+		return nil, fmt.Errorf("active key %q has no private key", activeKID)
+	}
+	m.active = activeKID
+	return m, nil
+}
+
+// LoadKeyManager 从目录加载 PEM 密钥，文件名（去掉 .pem 后缀）作为 kid
+func LoadKeyManager(dir, activeKID string) (*KeyManager, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .pem keys found in %s", dir)
+	}
+
+	keys := make([]*SigningKey, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+		key, err := ParsePEMKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("load key %s: %w", file, err)
+		}
+		keys = append(keys, key)
+	}
+	return NewKeyManager(keys, activeKID)
+}
+
+// ParsePEMKey 解析 PEM 格式的 RSA / Ed25519 私钥或公钥
+func ParsePEMKey(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	var (
+		raw interface{}
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		raw, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		raw, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		raw, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		raw, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &SigningKey{KID: kid}
+	switch k := raw.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", raw)
+	}
+	return key, nil
+}
+
+// ActiveKey 返回当前用于签名的密钥
+func (m *KeyManager) ActiveKey() *SigningKey {
+	return m.keys[m.active]
+}
+
+// Key 按 kid 返回验签密钥
+func (m *KeyManager) Key(kid string) (*SigningKey, bool) {
+	key, ok := m.keys[kid]
+	return key, ok
+}
+
+// Keys 返回按 kid 排序的全部密钥
+func (m *KeyManager) Keys() []*SigningKey {
+	keys := make([]*SigningKey, 0, len(m.keys))
+	for _, key := range m.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].KID < keys[j].KID })
+	return keys
+}
+
+// Methods 返回已加载密钥使用的签名算法
+func (m *KeyManager) Methods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, key := range m.Keys() {
+		alg := key.Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+func mustPEM(t *testing.T, key interface{}) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestKeyRotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	oldKey, err := ParsePEMKey("old", mustPEM(t, rsaKey))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	newKey, err := ParsePEMKey("new", mustPEM(t, edKey))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Run("token signed with retired key still verifies", func(t *testing.T) {
+		m, err := NewKeyManager([]*SigningKey{oldKey}, "old")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		oldToken, err := NewTokenIssuer(nil, m).GenerateToken(1, "user", nil, time.Minute)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// 轮换：新密钥成为活动密钥，旧密钥仅保留公钥
+		retired := &SigningKey{KID: oldKey.KID, Method: oldKey.Method, Public: oldKey.Public}
+		m, err = NewKeyManager([]*SigningKey{retired, newKey}, "new")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		issuer := NewTokenIssuer(nil, m)
+
+		if _, err = issuer.ParseToken(oldToken); err != nil {
+			t.Fatalf("Expected retired key token to verify, got %v", err)
+		}
+		newToken, err := issuer.GenerateToken(2, "user", nil, time.Minute)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		claims, err := issuer.ParseToken(newToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if claims.UserID != 2 {
+			t.Fatalf("Expected user id 2, got %d", claims.UserID)
+		}
+		if got := len(issuer.JWKS().Keys); got != 2 {
+			t.Fatalf("Expected 2 keys in JWKS, got %d", got)
+		}
+	})
+
+	t.Run("retired key cannot be active", func(t *testing.T) {
+		retired := &SigningKey{KID: "old", Method: oldKey.Method, Public: oldKey.Public}
+		if _, err := NewKeyManager([]*SigningKey{retired}, "old"); err == nil {
+			t.Fatalf("Expected error for public-only active key")
+		}
+	})
+}
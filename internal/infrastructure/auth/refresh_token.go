@@ -0,0 +1,11 @@
+package auth
+
+// NewRefreshToken returns an opaque refresh token and its storage hash.
+func NewRefreshToken() (token string, hash string, err error) {
+	return NewOpaqueToken()
+}
+
+// HashRefreshToken returns the hex encoded SHA-256 of the token.
+func HashRefreshToken(token string) string {
+	return HashOpaqueToken(token)
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RevocationStore 令牌吊销存储
+// 支持按 jti 吊销单个令牌，以及按用户吊销某一时间点之前签发的全部令牌。
+type RevocationStore interface {
+	// RevokeToken revokes a single token until it expires.
+	RevokeToken(ctx context.Context, claims *Claims) error
+
+	// RevokeUser revokes every token of the user issued before the given time.
+	RevokeUser(ctx context.Context, userID int64, issuedBefore time.Time) error
+
+	// IsRevoked reports whether the token has been revoked.
+	IsRevoked(ctx context.Context, claims *Claims) (bool, error)
+}
+
+// issuedBefore 判断令牌是否签发于指定时间之前
+// iat 精度为毫秒（见 jwt.go），吊销后同一毫秒内签发的令牌视为已吊销，之后签发的令牌不受影响；
+// 升级前签发的整秒 iat 令牌同样按签发时间比较。
+func issuedBefore(claims *Claims, cutoff time.Time) bool {
+	if claims.IssuedAt == nil {
+		return true
+	}
+	return claims.IssuedAt.Time.Before(cutoff)
+}
+
+// tokenExpiresAt 返回令牌过期时间，未设置时按默认有效期计算
+func tokenExpiresAt(claims *Claims) time.Time {
+	if claims.ExpiresAt != nil {
+		return claims.ExpiresAt.Time
+	}
+	return time.Now().Add(24 * time.Hour)
+}
+
+// MemoryRevocationStore 基于进程内存的吊销存储（单实例部署或测试使用）
+type MemoryRevocationStore struct {
+	tokens map[string]time.Time // jti -> 过期时间
+	users  map[int64]time.Time  // userID -> 吊销截止时间
+	mutex  sync.RWMutex
+}
+
+// NewMemoryRevocationStore 创建内存吊销存储
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		tokens: make(map[string]time.Time),
+		users:  make(map[int64]time.Time),
+	}
+}
+
+func (s *MemoryRevocationStore) RevokeToken(ctx context.Context, claims *Claims) error {
+	if claims.ID == "" {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// 顺带清理已过期的记录
+	now := time.Now()
+	for jti, expiresAt := range s.tokens {
+		if !now.Before(expiresAt) {
+			delete(s.tokens, jti)
+		}
+	}
+	s.tokens[claims.ID] = tokenExpiresAt(claims)
+	return nil
+}
+
+func (s *MemoryRevocationStore) RevokeUser(ctx context.Context, userID int64, issuedBefore time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.users[userID] = issuedBefore
+	return nil
+}
+
+func (s *MemoryRevocationStore) IsRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if expiresAt, ok := s.tokens[claims.ID]; ok && claims.ID != "" && time.Now().Before(expiresAt) {
+		return true, nil
+	}
+	if cutoff, ok := s.users[claims.UserID]; ok && issuedBefore(claims, cutoff) {
+		return true, nil
+	}
+	return false, nil
+}
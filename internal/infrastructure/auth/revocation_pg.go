@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"minigo/internal/infrastructure/dbctx"
+
+	"github.com/uptrace/bun"
+)
+
+// revokedToken 已吊销令牌记录
+type revokedToken struct {
+	bun.BaseModel `bun:"table:revoked_tokens,alias:rvt"`
+
+	JTI       string    `bun:"jti,pk"`
+	UserID    int64     `bun:"user_id,notnull"`
+	ExpiresAt time.Time `bun:"expires_at,notnull"`
+	CreatedAt time.Time `bun:"created_at,notnull,default:current_timestamp"`
+}
+
+// userTokenRevocation 用户级吊销截止时间
+type userTokenRevocation struct {
+	bun.BaseModel `bun:"table:user_token_revocations,alias:utr"`
+
+	UserID        int64     `bun:"user_id,pk"`
+	RevokedBefore time.Time `bun:"revoked_before,notnull"`
+	UpdatedAt     time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+// PGRevocationStore 基于PostgreSQL的吊销存储（多实例共享）
+type PGRevocationStore struct {
+	DB *bun.DB
+}
+
+// NewPGRevocationStore 创建PostgreSQL吊销存储
+func NewPGRevocationStore(db *bun.DB) *PGRevocationStore {
+	return &PGRevocationStore{DB: db}
+}
+
+func (s *PGRevocationStore) RevokeToken(ctx context.Context, claims *Claims) error {
+	if claims.ID == "" {
+		return nil
+	}
+	db := dbctx.FromCtx(ctx, s.DB)
+	_, err := db.NewInsert().
+		Model(&revokedToken{
+			JTI:       claims.ID,
+			UserID:    claims.UserID,
+			ExpiresAt: tokenExpiresAt(claims),
+		}).
+		On("CONFLICT (jti) DO NOTHING").
+		Exec(ctx)
+	return err
+}
+
+func (s *PGRevocationStore) RevokeUser(ctx context.Context, userID int64, issuedBefore time.Time) error {
+	db := dbctx.FromCtx(ctx, s.DB)
+	_, err := db.NewInsert().
+		Model(&userTokenRevocation{
+			UserID:        userID,
+			RevokedBefore: issuedBefore,
+			UpdatedAt:     time.Now(),
+		}).
+		On("CONFLICT (user_id) DO UPDATE").
+		Set("revoked_before = EXCLUDED.revoked_before").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	return err
+}
+
+func (s *PGRevocationStore) IsRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	db := dbctx.FromCtx(ctx, s.DB)
+
+	if claims.ID != "" {
+		exists, err := db.NewSelect().
+			Model((*revokedToken)(nil)).
+			Where("jti = ?", claims.ID).
+			Where("expires_at > ?", time.Now()).
+			Exists(ctx)
+		if err != nil || exists {
+			return exists, err
+		}
+	}
+
+	var cutoff userTokenRevocation
+	err := db.NewSelect().
+		Model(&cutoff).
+		Where("user_id = ?", claims.UserID).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return issuedBefore(claims, cutoff.RevokedBefore), nil
+}
+
+// PurgeExpired 清理已过期的单令牌吊销记录
+func (s *PGRevocationStore) PurgeExpired(ctx context.Context) error {
+	db := dbctx.FromCtx(ctx, s.DB)
+	_, err := db.NewDelete().
+		Model((*revokedToken)(nil)).
+		Where("expires_at <= ?", time.Now()).
+		Exec(ctx)
+	return err
+}
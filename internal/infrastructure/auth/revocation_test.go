@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestClaims(userID int64, jti string, issuedAt time.Time) *Claims {
+	return &Claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(time.Hour)),
+		},
+	}
+}
+
+func TestMemoryRevocationStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("revoke single token", func(t *testing.T) {
+		store := NewMemoryRevocationStore()
+		revoked := newTestClaims(1, "jti-1", time.Now())
+		other := newTestClaims(1, "jti-2", time.Now())
+
+		if err := store.RevokeToken(ctx, revoked); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if ok, _ := store.IsRevoked(ctx, revoked); !ok {
+			t.Fatalf("Expected token %q to be revoked", revoked.ID)
+		}
+		if ok, _ := store.IsRevoked(ctx, other); ok {
+			t.Fatalf("Expected token %q not to be revoked", other.ID)
+		}
+	})
+
+	t.Run("revoke user tokens issued before cutoff", func(t *testing.T) {
+		store := NewMemoryRevocationStore()
+		cutoff := time.Now()
+		old := newTestClaims(2, "old", cutoff.Add(-time.Minute))
+		fresh := newTestClaims(2, "fresh", cutoff.Add(time.Minute))
+		otherUser := newTestClaims(3, "other", cutoff.Add(-time.Minute))
+
+		if err := store.RevokeUser(ctx, 2, cutoff); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if ok, _ := store.IsRevoked(ctx, old); !ok {
+			t.Fatalf("Expected token issued before cutoff to be revoked")
+		}
+		if ok, _ := store.IsRevoked(ctx, fresh); ok {
+			t.Fatalf("Expected token issued after cutoff to stay valid")
+		}
+		if ok, _ := store.IsRevoked(ctx, otherUser); ok {
+			t.Fatalf("Expected other user's token to stay valid")
+		}
+	})
+
+	t.Run("keeps tokens issued later in the same second", func(t *testing.T) {
+		store := NewMemoryRevocationStore()
+		second := time.Now().Truncate(time.Second)
+		if err := store.RevokeUser(ctx, 2, second.Add(100*time.Millisecond)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if ok, _ := store.IsRevoked(ctx, newTestClaims(2, "before", second.Add(50*time.Millisecond))); !ok {
+			t.Fatalf("Expected token issued before the cutoff to be revoked")
+		}
+		if ok, _ := store.IsRevoked(ctx, newTestClaims(2, "after", second.Add(600*time.Millisecond))); ok {
+			t.Fatalf("Expected token issued after the cutoff in the same second to stay valid")
+		}
+	})
+	t.Run("revokes a token signed before the cutoff but not one signed after", func(t *testing.T) {
+		store := NewMemoryRevocationStore()
+		issuer := NewTokenIssuer([]byte("test-secret"), nil)
+		sign := func() *Claims {
+			token, err := issuer.GenerateToken(4, "user", nil, time.Minute)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			claims, err := issuer.ParseToken(token)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			return claims
+		}
+		before := sign()
+		time.Sleep(2 * time.Millisecond)
+		if err := store.RevokeUser(ctx, 4, time.Now()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		time.Sleep(2 * time.Millisecond)
+		after := sign()
+		if ok, _ := store.IsRevoked(ctx, before); !ok {
+			t.Fatal("Expected token signed before the cutoff to be revoked")
+		}
+		if ok, _ := store.IsRevoked(ctx, after); ok {
+			t.Fatal("Expected token signed right after the cutoff to stay valid")
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret RFC 6238 附录 B 的 SHA1 测试密钥 "12345678901234567890"
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTP(t *testing.T) {
+	t.Run("matches the RFC 6238 test vectors", func(t *testing.T) {
+		vectors := map[int64]string{
+			59:         "287082",
+			1111111109: "081804",
+			1111111111: "050471",
+			1234567890: "005924",
+			2000000000: "279037",
+		}
+		for unix, want := range vectors {
+			got, err := TOTPCode(rfc6238Secret, time.Unix(unix, 0))
+			if err != nil || got != want {
+				t.Fatalf("Expected %s at %d, got %s (%v)", want, unix, got, err)
+			}
+		}
+	})
+
+	t.Run("accepts one step of clock skew", func(t *testing.T) {
+		now := time.Unix(1111111111, 0)
+		code, _ := TOTPCode(rfc6238Secret, now.Add(-30*time.Second))
+		step, ok := ValidateTOTP(rfc6238Secret, code, now)
+		if !ok || step != TOTPStep(now)-1 {
+			t.Fatalf("Expected previous step accepted, got %d %v", step, ok)
+		}
+		code, _ = TOTPCode(rfc6238Secret, now.Add(-90*time.Second))
+		if _, ok = ValidateTOTP(rfc6238Secret, code, now); ok {
+			t.Fatal("Expected code three steps old rejected")
+		}
+		if _, ok = ValidateTOTP(rfc6238Secret, "12345", now); ok {
+			t.Fatal("Expected short code rejected")
+		}
+	})
+
+	t.Run("builds a provisioning URI", func(t *testing.T) {
+		secret, err := GenerateTOTPSecret()
+		if err != nil || len(secret) != 32 {
+			t.Fatalf("Expected 32 character secret, got %q (%v)", secret, err)
+		}
+		uri := TOTPProvisioningURI("minigo", "13800000000", secret)
+		if !strings.HasPrefix(uri, "otpauth://totp/minigo:13800000000?") || !strings.Contains(uri, "secret="+secret) {
+			t.Fatalf("Expected otpauth URI, got %s", uri)
+		}
+	})
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := NewRecoveryCodes(10)
+	if err != nil || len(codes) != 10 || len(hashes) != 10 {
+		t.Fatalf("Expected 10 codes, got %v (%v)", codes, err)
+	}
+	if len(codes[0]) != 9 || codes[0][4] != '-' {
+		t.Fatalf("Expected xxxx-xxxx format, got %s", codes[0])
+	}
+	if HashRecoveryCode(" "+strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))) != hashes[0] {
+		t.Fatal("Expected hash to ignore case, spaces and hyphens")
+	}
+}
+
+func TestSecretBox(t *testing.T) {
+	box, err := NewSecretBox([]byte(strings.Repeat("k", 32)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	sealed, _ := box.Seal(rfc6238Secret, "user:1")
+	if sealed == rfc6238Secret {
+		t.Fatal("Expected secret encrypted")
+	}
+	if plain, err := box.Open(sealed, "user:1"); err != nil || plain != rfc6238Secret {
+		t.Fatalf("Expected secret decrypted, got %q (%v)", plain, err)
+	}
+	if _, err = box.Open(sealed, "user:2"); err == nil {
+		t.Fatal("Expected ciphertext bound to its owner")
+	}
+
+	var plainBox *SecretBox
+	if sealed, _ = plainBox.Seal(rfc6238Secret, "user:1"); sealed != rfc6238Secret {
+		t.Fatalf("Expected nil box to store plaintext, got %s", sealed)
+	}
+}
@@ -0,0 +1,295 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func viperWith(values map[string]interface{}) *viper.Viper {
+	v := viper.New()
+	for k, val := range values {
+		v.Set(k, val)
+	}
+	return v
+}
+
+func TestFromViper(t *testing.T) {
+	t.Run("applies defaults and typed values", func(t *testing.T) {
+		cfg, err := FromViper(viperWith(map[string]interface{}{
+			"ENV":                 "dev",
+			"JWT_EXPIRE_DURATION": "30m",
+			"LOG_REDACT_PATTERNS": `\d{3},\d{3};id_\w+`,
+			"LOG_REDACT_FIELDS":   []interface{}{"id_card", "bank_no"},
+		}))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cfg.JWT.ExpireDuration != 30*time.Minute {
+			t.Fatalf("Expected 30m, got %s", cfg.JWT.ExpireDuration)
+		}
+		if cfg.HTTP.Port != "8808" || !cfg.Log.Stdout || cfg.Tracing.SampleRatio != 1 {
+			t.Fatalf("Expected defaults applied, got %+v", cfg)
+		}
+		if len(cfg.Log.RedactPatterns) != 2 || len(cfg.Log.RedactFields) != 2 {
+			t.Fatalf("Expected list values split, got %v / %v", cfg.Log.RedactPatterns, cfg.Log.RedactFields)
+		}
+	})
+
+	t.Run("rejects unparsable values instead of falling back", func(t *testing.T) {
+		_, err := FromViper(viperWith(map[string]interface{}{
+			"ENV":                 "dev",
+			"JWT_EXPIRE_DURATION": "1 hour",
+			"DB_AUTO_MIGRATE":     "maybe",
+		}))
+		if err == nil {
+			t.Fatal("Expected error for invalid values")
+		}
+		for _, key := range []string{"JWT_EXPIRE_DURATION", "DB_AUTO_MIGRATE"} {
+			if !strings.Contains(err.Error(), key) {
+				t.Fatalf("Expected error to mention %s, got %v", key, err)
+			}
+		}
+	})
+
+	t.Run("rejects insecure secrets in prod", func(t *testing.T) {
+		_, err := FromViper(viperWith(map[string]interface{}{"ENV": "prod"}))
+		if err == nil || !strings.Contains(err.Error(), "JWT_SECRET") {
+			t.Fatalf("Expected JWT_SECRET error in prod, got %v", err)
+		}
+
+		totpKey, err := GenerateMasterKey()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		_, err = FromViper(viperWith(map[string]interface{}{
+			"ENV":        "prod",
+			"JWT_SECRET": strings.Repeat("k", minSecretLength),
+		}))
+		if err == nil || !strings.Contains(err.Error(), "TOTP_ENCRYPTION_KEY") {
+			t.Fatalf("Expected TOTP_ENCRYPTION_KEY required in prod, got %v", err)
+		}
+
+		_, err = FromViper(viperWith(map[string]interface{}{
+			"ENV":                 "prod",
+			"JWT_SECRET":          strings.Repeat("k", minSecretLength),
+			"TOTP_ENCRYPTION_KEY": totpKey,
+		}))
+		if err == nil || !strings.Contains(err.Error(), "VERIFY_CODE_HASH_KEY") {
+			t.Fatalf("Expected VERIFY_CODE_HASH_KEY required in prod, got %v", err)
+		}
+
+		codeKey, err := GenerateMasterKey()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		_, err = FromViper(viperWith(map[string]interface{}{
+			"ENV":                  "prod",
+			"JWT_SECRET":           strings.Repeat("k", minSecretLength),
+			"TOTP_ENCRYPTION_KEY":  totpKey,
+			"VERIFY_CODE_HASH_KEY": codeKey,
+		}))
+		if err == nil || !strings.Contains(err.Error(), "SMS_PROVIDER: console") || !strings.Contains(err.Error(), "MAIL_PROVIDER: console") {
+			t.Fatalf("Expected console providers rejected in prod, got %v", err)
+		}
+
+		_, err = FromViper(viperWith(map[string]interface{}{
+			"ENV":                  "prod",
+			"JWT_SECRET":           strings.Repeat("k", minSecretLength),
+			"TOTP_ENCRYPTION_KEY":  totpKey,
+			"VERIFY_CODE_HASH_KEY": codeKey,
+			"SMS_PROVIDER":         "webhook",
+			"SMS_WEBHOOK_URL":      "https://sms-gateway.internal/send",
+			"MAIL_PROVIDER":        "smtp",
+			"SMTP_HOST":            "smtp.example.com",
+			"MAIL_FROM":            "minigo <noreply@example.com>",
+		}))
+		if err != nil {
+			t.Fatalf("Expected strong secret accepted, got %v", err)
+		}
+	})
+}
+
+func TestValidateCORS(t *testing.T) {
+	_, err := FromViper(viperWith(map[string]interface{}{
+		"ENV":                  "dev",
+		"CORS_ALLOWED_ORIGINS": `https://app.example.com,https://*.example.com,^https://pr-\d+\.example\.com$,*`,
+	}))
+	if err != nil {
+		t.Fatalf("Expected origin patterns accepted, got %v", err)
+	}
+
+	_, err = FromViper(viperWith(map[string]interface{}{
+		"ENV":                  "dev",
+		"CORS_ALLOWED_ORIGINS": "https://a.*.example.com,^https://(,https://app.example.com/path",
+	}))
+	if err == nil || strings.Count(err.Error(), "CORS_ALLOWED_ORIGINS") != 3 {
+		t.Fatalf("Expected 3 origin errors, got %v", err)
+	}
+
+	_, err = FromViper(viperWith(map[string]interface{}{
+		"ENV":                  "prod",
+		"JWT_SECRET":           strings.Repeat("k", minSecretLength),
+		"CORS_ALLOWED_ORIGINS": "*",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "CORS_ALLOW_CREDENTIALS") {
+		t.Fatalf("Expected * with credentials rejected in prod, got %v", err)
+	}
+}
+
+func TestValidateMetricsAddr(t *testing.T) {
+	for _, addr := range []string{":8808", "0.0.0.0:8808", "9464"} {
+		_, err := FromViper(viperWith(map[string]interface{}{"ENV": "dev", "METRICS_ADDR": addr}))
+		if err == nil || !strings.Contains(err.Error(), "METRICS_ADDR") {
+			t.Fatalf("Expected METRICS_ADDR %q rejected, got %v", addr, err)
+		}
+	}
+	for _, addr := range []string{"none", "127.0.0.1:9464"} {
+		if _, err := FromViper(viperWith(map[string]interface{}{"ENV": "dev", "METRICS_ADDR": addr})); err != nil {
+			t.Fatalf("Expected METRICS_ADDR %q accepted, got %v", addr, err)
+		}
+	}
+}
+
+func TestValidateSMS(t *testing.T) {
+	_, err := FromViper(viperWith(map[string]interface{}{
+		"ENV":             "dev",
+		"SMS_PROVIDER":    "webhook",
+		"SMS_WEBHOOK_URL": "ftp://sms.example.com",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "SMS_WEBHOOK_URL") {
+		t.Fatalf("Expected SMS_WEBHOOK_URL error, got %v", err)
+	}
+
+	_, err = FromViper(viperWith(map[string]interface{}{
+		"ENV":          "prod",
+		"JWT_SECRET":   strings.Repeat("k", minSecretLength),
+		"SMS_PROVIDER": "file",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "SMS_PROVIDER") {
+		t.Fatalf("Expected file provider rejected in prod, got %v", err)
+	}
+}
+
+func TestValidateMail(t *testing.T) {
+	_, err := FromViper(viperWith(map[string]interface{}{
+		"ENV":           "dev",
+		"MAIL_PROVIDER": "smtp",
+		"SMTP_HOST":     "smtp.example.com",
+		"MAIL_FROM":     "not an address",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "MAIL_FROM") {
+		t.Fatalf("Expected MAIL_FROM error, got %v", err)
+	}
+
+	_, err = FromViper(viperWith(map[string]interface{}{
+		"ENV":                "dev",
+		"PASSWORD_RESET_URL": "/reset-password",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "PASSWORD_RESET_URL") {
+		t.Fatalf("Expected PASSWORD_RESET_URL error, got %v", err)
+	}
+
+	_, err = FromViper(viperWith(map[string]interface{}{
+		"ENV":           "prod",
+		"JWT_SECRET":    strings.Repeat("k", minSecretLength),
+		"MAIL_PROVIDER": "file",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "MAIL_PROVIDER") {
+		t.Fatalf("Expected file provider rejected in prod, got %v", err)
+	}
+}
+
+func TestValidatePasswordPolicy(t *testing.T) {
+	cfg, err := FromViper(viperWith(map[string]interface{}{"ENV": "dev"}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Password.MinLength != 8 || cfg.Password.History != 5 || !cfg.Password.Blocklist {
+		t.Fatalf("Expected default password policy, got %+v", cfg.Password)
+	}
+
+	for key, value := range map[string]interface{}{
+		"PASSWORD_MAX_LENGTH":       100,
+		"PASSWORD_MIN_LENGTH":       3,
+		"PASSWORD_MIN_CHAR_CLASSES": 5,
+		"PASSWORD_HISTORY":          -1,
+	} {
+		_, err = FromViper(viperWith(map[string]interface{}{"ENV": "dev", key: value}))
+		if err == nil || !strings.Contains(err.Error(), key) {
+			t.Fatalf("Expected %s error, got %v", key, err)
+		}
+	}
+}
+
+func TestRateLimitPolicies(t *testing.T) {
+	t.Run("parses the policy syntax", func(t *testing.T) {
+		p, err := ParseRateLimitPolicy("login post /api/auth/login limit=5/m algorithm=gcra burst=10 key=ip+body.phone")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if p.Name != "login" || p.Method != "POST" || p.Path != "/api/auth/login" || p.Limit != 5 ||
+			p.Period != time.Minute || p.Burst != 10 || p.Algorithm != "gcra" || strings.Join(p.Key, "+") != "ip+body.phone" {
+			t.Fatalf("Expected parsed policy, got %+v", p)
+		}
+		if p, _ = ParseRateLimitPolicy("export /api/export/* limit=2 algorithm=concurrency"); p.Method != "" || p.Period != 0 || p.Key[0] != "ip" {
+			t.Fatalf("Expected any method and ip key, got %+v", p)
+		}
+		for _, text := range []string{
+			"login /api/auth/login limit=5",
+			"login /api/auth/login limit=5/1m algorithm=leaky",
+			"login /api/auth/login limit=5/1m key=cookie",
+			"login /api/auth/login limit=5/1m algorithm=sliding_window burst=2",
+			"export /api/export limit=2/1m algorithm=concurrency",
+		} {
+			if _, err := ParseRateLimitPolicy(text); err == nil {
+				t.Fatalf("Expected error for %q", text)
+			}
+		}
+	})
+
+	t.Run("validates policies against the store", func(t *testing.T) {
+		_, err := FromViper(viperWith(map[string]interface{}{
+			"ENV":                   "dev",
+			"DB_DSN":                "postgres://localhost/minigo",
+			"RATE_LIMIT_STORE":      "postgres",
+			"RATE_LIMIT_POLICIES":   "a /a limit=1/1m;a /b limit=1/1m;sms /sms limit=1/1m algorithm=sliding_window",
+			"RATE_LIMIT_EXEMPT_IPS": "10.0.0.0/8,localhost",
+		}))
+		if err == nil {
+			t.Fatal("Expected validation error")
+		}
+		for _, want := range []string{"duplicate policy name a", "algorithm sliding_window is not supported", "RATE_LIMIT_EXEMPT_IPS"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Fatalf("Expected error to mention %q, got %v", want, err)
+			}
+		}
+	})
+}
+
+func TestRedacted(t *testing.T) {
+	cfg, err := FromViper(viperWith(map[string]interface{}{
+		"ENV":                   "dev",
+		"DB_DSN":                "postgres://app:s3cret@db:5432/app?sslmode=disable",
+		"OSS_ACCESS_KEY_SECRET": "oss-secret",
+	}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	dump := cfg.Redacted()
+	if dump["JWT_SECRET"] != "[REDACTED]" || dump["OSS_ACCESS_KEY_SECRET"] != "[REDACTED]" {
+		t.Fatalf("Expected secrets redacted, got %v / %v", dump["JWT_SECRET"], dump["OSS_ACCESS_KEY_SECRET"])
+	}
+	if dsn, _ := dump["DB_DSN"].(string); strings.Contains(dsn, "s3cret") || !strings.Contains(dsn, "app:") {
+		t.Fatalf("Expected DSN password hidden, got %v", dump["DB_DSN"])
+	}
+	if dump["JWT_EXPIRE_DURATION"] != "15m0s" || dump["PORT"] != "8808" {
+		t.Fatalf("Expected plain values kept, got %v / %v", dump["JWT_EXPIRE_DURATION"], dump["PORT"])
+	}
+	if dump["OSS_ACCESS_KEY_ID"] != "" {
+		t.Fatalf("Expected empty values kept, got %v", dump["OSS_ACCESS_KEY_ID"])
+	}
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitPolicy 按路由的限流策略，由 RATE_LIMIT_POLICIES 中的一项解析得到
+type RateLimitPolicy struct {
+	Name      string
+	Method    string        // 为空匹配任意方法
+	Path      string        // 路由模板，如 /api/users/:id；以 * 结尾时按前缀匹配
+	Algorithm string        // token_bucket/gcra/sliding_window/concurrency
+	Limit     int           // 每个周期的请求数；concurrency 为同时处理中的请求数
+	Period    time.Duration // concurrency 时为 0
+	Burst     int           // token_bucket/gcra 的突发容量，默认等于 Limit
+	Key       []string      // 限流键的组成：ip、user、header.<Name>、query.<name>、body.<field>
+}
+
+// rateLimitAlgorithms 支持的算法及可用的存储
+var rateLimitAlgorithms = map[string][]string{
+	"token_bucket":   {"memory", "postgres", "redis"},
+	"gcra":           {"memory", "postgres", "redis"},
+	"sliding_window": {"memory", "redis"},
+	"concurrency":    {"memory", "postgres", "redis"}, // 只在进程内计数，与存储无关
+}
+
+// ParseRateLimitPolicy 解析一条限流策略：
+//
+//	name [METHOD] PATH limit=N[/PERIOD] [algorithm=ALG] [burst=N] [key=PART+PART]
+//
+// 例如 "login POST /api/auth/login limit=5/1m algorithm=sliding_window key=ip+body.phone"。
+// METHOD 省略或为 * 时匹配任意方法；algorithm 默认 token_bucket；key 默认 ip。
+func ParseRateLimitPolicy(text string) (RateLimitPolicy, error) {
+	p := RateLimitPolicy{Algorithm: "token_bucket", Key: []string{"ip"}}
+	var positional []string
+	for _, field := range strings.Fields(text) {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			positional = append(positional, field)
+			continue
+		}
+		var err error
+		switch name {
+		case "limit":
+			count, period, hasPeriod := strings.Cut(value, "/")
+			if p.Limit, err = strconv.Atoi(count); err != nil || p.Limit <= 0 {
+				return p, fmt.Errorf("invalid limit %q", value)
+			}
+			if hasPeriod {
+				if p.Period, err = parsePeriod(period); err != nil {
+					return p, fmt.Errorf("invalid limit %q", value)
+				}
+			}
+		case "algorithm":
+			p.Algorithm = value
+		case "burst":
+			if p.Burst, err = strconv.Atoi(value); err != nil || p.Burst <= 0 {
+				return p, fmt.Errorf("invalid burst %q", value)
+			}
+		case "key":
+			p.Key = strings.Split(value, "+")
+		default:
+			return p, fmt.Errorf("unknown option %q", name)
+		}
+	}
+
+	switch len(positional) {
+	case 2:
+		p.Name, p.Path = positional[0], positional[1]
+	case 3:
+		p.Name, p.Method, p.Path = positional[0], strings.ToUpper(positional[1]), positional[2]
+	default:
+		return p, errors.New("want: name [METHOD] PATH limit=N[/PERIOD] [algorithm=ALG] [burst=N] [key=PART+PART]")
+	}
+	if p.Method == "*" {
+		p.Method = ""
+	}
+	if !strings.HasPrefix(p.Path, "/") {
+		return p, fmt.Errorf("invalid path %q", p.Path)
+	}
+	if _, ok := rateLimitAlgorithms[p.Algorithm]; !ok {
+		return p, fmt.Errorf("unknown algorithm %q", p.Algorithm)
+	}
+	if p.Limit == 0 {
+		return p, errors.New("limit is required")
+	}
+	if p.Algorithm == "concurrency" {
+		if p.Period != 0 || p.Burst != 0 {
+			return p, errors.New("concurrency takes limit=N without period or burst")
+		}
+	} else if p.Period == 0 {
+		return p, fmt.Errorf("algorithm %s requires limit=N/PERIOD", p.Algorithm)
+	}
+	if p.Burst != 0 && p.Algorithm == "sliding_window" {
+		return p, errors.New("sliding_window does not take burst")
+	}
+	if p.Burst == 0 && p.Algorithm != "concurrency" {
+		p.Burst = p.Limit
+	}
+	for _, part := range p.Key {
+		if !validKeyPart(part) {
+			return p, fmt.Errorf("invalid key part %q", part)
+		}
+	}
+	return p, nil
+}
+
+// RateLimitPolicies 解析全部策略，名称不能重复
+func (c RateLimitConfig) RateLimitPolicies() ([]RateLimitPolicy, error) {
+	var errs []error
+	policies := make([]RateLimitPolicy, 0, len(c.Policies))
+	seen := make(map[string]bool)
+	for _, text := range c.Policies {
+		p, err := ParseRateLimitPolicy(text)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%q: %w", text, err))
+			continue
+		case seen[p.Name]:
+			errs = append(errs, fmt.Errorf("%q: duplicate policy name %s", text, p.Name))
+			continue
+		}
+		seen[p.Name] = true
+		policies = append(policies, p)
+	}
+	return policies, errors.Join(errs...)
+}
+
+// parsePeriod 支持 time.ParseDuration 的格式，以及省略数字的 s/m/h/d（如 5/m）
+func parsePeriod(value string) (time.Duration, error) {
+	switch value {
+	case "s", "m", "h":
+		value = "1" + value
+	case "d":
+		return 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err == nil && d <= 0 {
+		err = errors.New("period must be positive")
+	}
+	return d, err
+}
+
+func validKeyPart(part string) bool {
+	if part == "ip" || part == "user" {
+		return true
+	}
+	source, name, ok := strings.Cut(part, ".")
+	return ok && name != "" && oneOf(source, "header", "query", "body")
+}
+
+// validIPOrCIDR 接受单个 IP 或 CIDR
+func validIPOrCIDR(value string) bool {
+	if _, _, err := net.ParseCIDR(value); err == nil {
+		return true
+	}
+	return net.ParseIP(value) != nil
+}
@@ -0,0 +1,281 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// masterKeySize AES-256 主密钥长度
+const masterKeySize = 32
+
+// secretFileVersion 加密密钥文件格式版本
+const secretFileVersion = 1
+
+// SecretStore is a local secrets file encrypted with AES-256-GCM.
+// 每个值单独加密，并以密钥名作为附加数据，防止密文被挪用到其他键。
+type SecretStore struct {
+	path string
+	key  []byte
+	data secretFile
+}
+
+type secretFile struct {
+	Version  int               `json:"version"`
+	KeyCheck string            `json:"key_check"` // 主密钥指纹，用于识别错误的主密钥
+	Secrets  map[string]string `json:"secrets"`   // 名称 -> base64(nonce|密文)
+}
+
+// GenerateMasterKey 生成 base64 编码的随机主密钥
+func GenerateMasterKey() (string, error) {
+	key := make([]byte, masterKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ParseMasterKey 解析 base64 编码的 32 字节主密钥
+func ParseMasterKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+	if len(key) != masterKeySize {
+		return nil, fmt.Errorf("invalid master key: want %d bytes, got %d", masterKeySize, len(key))
+	}
+	return key, nil
+}
+
+// OpenSecretStore 打开加密密钥文件；文件不存在时返回空的存储，Save 时创建
+func OpenSecretStore(path string, masterKey []byte) (*SecretStore, error) {
+	s := &SecretStore{
+		path: path,
+		key:  masterKey,
+		data: secretFile{Version: secretFileVersion, KeyCheck: keyCheck(masterKey), Secrets: map[string]string{}},
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read secrets file: %w", err)
+	}
+	if err = json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("parse secrets file: %w", err)
+	}
+	if s.data.Version != secretFileVersion {
+		return nil, fmt.Errorf("unsupported secrets file version %d", s.data.Version)
+	}
+	if s.data.KeyCheck != keyCheck(masterKey) {
+		return nil, errors.New("master key does not match secrets file")
+	}
+	if s.data.Secrets == nil {
+		s.data.Secrets = map[string]string{}
+	}
+	return s, nil
+}
+
+// Names 返回已保存的密钥名
+func (s *SecretStore) Names() []string {
+	names := make([]string, 0, len(s.data.Secrets))
+	for name := range s.data.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get 解密并返回 name 对应的值
+func (s *SecretStore) Get(name string) (string, bool, error) {
+	encoded, ok := s.data.Secrets[name]
+	if !ok {
+		return "", false, nil
+	}
+	value, err := decryptSecret(s.key, name, encoded)
+	if err != nil {
+		return "", false, fmt.Errorf("decrypt %s: %w", name, err)
+	}
+	return value, true, nil
+}
+
+// Set 加密保存 name 的值（需调用 Save 写入文件）
+func (s *SecretStore) Set(name, value string) error {
+	encoded, err := encryptSecret(s.key, name, value)
+	if err != nil {
+		return err
+	}
+	s.data.Secrets[name] = encoded
+	return nil
+}
+
+// Rotate 使用新主密钥重新加密全部值（需调用 Save 写入文件）
+func (s *SecretStore) Rotate(newKey []byte) error {
+	rotated := make(map[string]string, len(s.data.Secrets))
+	for name := range s.data.Secrets {
+		value, _, err := s.Get(name)
+		if err != nil {
+			return err
+		}
+		if rotated[name], err = encryptSecret(newKey, name, value); err != nil {
+			return err
+		}
+	}
+	s.key = newKey
+	s.data.KeyCheck = keyCheck(newKey)
+	s.data.Secrets = rotated
+	return nil
+}
+
+// Save 原子写入文件，权限为 0600
+func (s *SecretStore) Save() error {
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("write secrets file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err = tmp.Chmod(0o600); err == nil {
+		_, err = tmp.Write(append(raw, '\n'))
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write secrets file: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func keyCheck(key []byte) string {
+	sum := sha256.Sum256(append([]byte("minigo-secrets:"), key...))
+	return hex.EncodeToString(sum[:8])
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptSecret(key []byte, name, value string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), []byte(name))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptSecret(key []byte, name, encoded string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// SecretKeys 返回可从 *_FILE 或加密密钥文件读取的配置项
+func SecretKeys() []string {
+	var keys []string
+	eachField(reflect.ValueOf(&Config{}).Elem(), func(field reflect.StructField, _ reflect.Value) {
+		if key := field.Tag.Get("env"); field.Tag.Get("secret") != "" && key != "SECRETS_MASTER_KEY" {
+			keys = append(keys, key)
+		}
+	})
+	return keys
+}
+
+// OpenSecrets 按 SECRETS_FILE / SECRETS_MASTER_KEY 打开加密密钥文件，供 secrets 子命令使用
+func OpenSecrets() (*SecretStore, error) {
+	v, err := newViper()
+	if err != nil {
+		return nil, err
+	}
+	store, err := openSecretStore(v, false)
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, errors.New("SECRETS_FILE is not set")
+	}
+	return store, nil
+}
+
+// openSecretStore SECRETS_FILE 为空时返回 nil；mustExist 时文件不存在视为错误
+func openSecretStore(v *viper.Viper, mustExist bool) (*SecretStore, error) {
+	path := strings.TrimSpace(v.GetString("SECRETS_FILE"))
+	if path == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(path); mustExist && err != nil {
+		return nil, fmt.Errorf("SECRETS_FILE: %w", err)
+	}
+	encoded, ok, err := resolveSecret(v, nil, "SECRETS_MASTER_KEY")
+	if err != nil {
+		return nil, err
+	}
+	if !ok || encoded == "" {
+		return nil, errors.New("SECRETS_MASTER_KEY: required when SECRETS_FILE is set")
+	}
+	key, err := ParseMasterKey(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("SECRETS_MASTER_KEY: %w", err)
+	}
+	return OpenSecretStore(path, key)
+}
+
+// resolveSecret 依次从 KEY、KEY_FILE 指向的文件和加密密钥文件中查找密钥
+func resolveSecret(v *viper.Viper, store *SecretStore, key string) (string, bool, error) {
+	fileKey := key + "_FILE"
+	switch {
+	case v.IsSet(key) && v.IsSet(fileKey):
+		return "", false, fmt.Errorf("set either %s or %s, not both", key, fileKey)
+	case v.IsSet(key):
+		return strings.TrimSpace(v.GetString(key)), true, nil
+	case v.IsSet(fileKey):
+		raw, err := os.ReadFile(strings.TrimSpace(v.GetString(fileKey)))
+		if err != nil {
+			return "", false, fmt.Errorf("read %s: %w", fileKey, err)
+		}
+		// 挂载的密钥文件通常以换行结尾
+		return strings.TrimRight(string(raw), "\r\n"), true, nil
+	case store != nil:
+		return store.Get(key)
+	}
+	return "", false, nil
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func mustMasterKey(t *testing.T) (string, []byte) {
+	t.Helper()
+	encoded, err := GenerateMasterKey()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	key, err := ParseMasterKey(encoded)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return encoded, key
+}
+
+func TestSecretStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	_, key := mustMasterKey(t)
+
+	store, err := OpenSecretStore(path, key)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err = store.Set("JWT_SECRET", "s3cret"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err = store.Save(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Run("stores values encrypted", func(t *testing.T) {
+		raw, _ := os.ReadFile(path)
+		if strings.Contains(string(raw), "s3cret") {
+			t.Fatalf("Expected ciphertext only, got %s", raw)
+		}
+		info, _ := os.Stat(path)
+		if info.Mode().Perm() != 0o600 {
+			t.Fatalf("Expected 0600 permissions, got %v", info.Mode().Perm())
+		}
+	})
+
+	t.Run("rejects a different master key", func(t *testing.T) {
+		_, other := mustMasterKey(t)
+		if _, err := OpenSecretStore(path, other); err == nil {
+			t.Fatal("Expected error for wrong master key")
+		}
+	})
+
+	t.Run("rotate re-encrypts under the new key", func(t *testing.T) {
+		_, newKey := mustMasterKey(t)
+		if err := store.Rotate(newKey); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := store.Save(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := OpenSecretStore(path, key); err == nil {
+			t.Fatal("Expected old key to be rejected after rotation")
+		}
+		reopened, err := OpenSecretStore(path, newKey)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if value, ok, err := reopened.Get("JWT_SECRET"); err != nil || !ok || value != "s3cret" {
+			t.Fatalf("Expected s3cret, got %q (%v, %v)", value, ok, err)
+		}
+	})
+}
+
+func TestSecretResolution(t *testing.T) {
+	dir := t.TempDir()
+	encoded, key := mustMasterKey(t)
+	storePath := filepath.Join(dir, "secrets.json")
+	store, _ := OpenSecretStore(storePath, key)
+	_ = store.Set("JWT_SECRET", "from-store")
+	_ = store.Set("DB_PASSWORD", "p@ss/word")
+	if err := store.Save(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ossFile := filepath.Join(dir, "oss_secret")
+	if err := os.WriteFile(ossFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	cfg, err := FromViper(viperWith(map[string]interface{}{
+		"ENV":                        "dev",
+		"DB_DSN":                     "postgres://app@db:5432/app",
+		"SECRETS_FILE":               storePath,
+		"SECRETS_MASTER_KEY":         encoded,
+		"OSS_ACCESS_KEY_SECRET_FILE": ossFile,
+	}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.JWT.Secret != "from-store" || cfg.OSS.AccessKeySecret != "from-file" {
+		t.Fatalf("Expected secrets resolved, got %q / %q", cfg.JWT.Secret, cfg.OSS.AccessKeySecret)
+	}
+	if got := cfg.DB.ConnString(); got != "postgres://app:p%40ss%2Fword@db:5432/app" {
+		t.Fatalf("Expected password injected into DSN, got %s", got)
+	}
+
+	_, err = FromViper(viperWith(map[string]interface{}{
+		"ENV":             "dev",
+		"JWT_SECRET":      "a",
+		"JWT_SECRET_FILE": ossFile,
+	}))
+	if err == nil {
+		t.Fatal("Expected error when both JWT_SECRET and JWT_SECRET_FILE are set")
+	}
+}
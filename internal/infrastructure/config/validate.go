@@ -0,0 +1,190 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"minigo/pkg/origin"
+)
+
+// minSecretLength prod 环境 HS256 密钥的最小长度（字节）
+const minSecretLength = 32
+
+// insecureMarkers 示例配置中的占位值，出现在 prod 密钥中即视为未修改
+var insecureMarkers = []string{"change_me", "changeme"}
+
+// Validate 校验配置取值；prod 环境额外拒绝示例密钥等不安全设置
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(oneOf(c.Env, "dev", "test", "prod"), "ENV: must be one of dev, test, prod, got %q", c.Env)
+
+	port, err := strconv.Atoi(c.HTTP.Port)
+	check(err == nil && port > 0 && port < 65536, "PORT: invalid port %q", c.HTTP.Port)
+	check(c.HTTP.ReadHeaderTimeout > 0, "HTTP_READ_HEADER_TIMEOUT: must be positive")
+	check(c.HTTP.ReadTimeout > 0, "HTTP_READ_TIMEOUT: must be positive")
+	check(c.HTTP.WriteTimeout > 0, "HTTP_WRITE_TIMEOUT: must be positive")
+	check(c.HTTP.IdleTimeout > 0, "HTTP_IDLE_TIMEOUT: must be positive")
+	check(c.HTTP.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT: must be positive")
+	check(c.HTTP.ShutdownDrainDelay >= 0, "SHUTDOWN_DRAIN_DELAY: must not be negative")
+	if c.HTTP.MetricsEnabled() {
+		_, metricsPort, err := net.SplitHostPort(c.HTTP.MetricsAddr)
+		check(err == nil && metricsPort != "", "METRICS_ADDR: must be host:port, got %q", c.HTTP.MetricsAddr)
+		check(metricsPort != c.HTTP.Port, "METRICS_ADDR: must not use PORT, metrics are served on a separate listener")
+	}
+
+	for _, o := range c.CORS.AllowedOrigins {
+		err := origin.Validate(o)
+		check(err == nil, "CORS_ALLOWED_ORIGINS: %v", err)
+	}
+	for _, method := range c.CORS.AllowedMethods {
+		check(method != "" && !strings.ContainsAny(method, " \t,"), "CORS_ALLOWED_METHODS: invalid method %q", method)
+	}
+	check(c.CORS.MaxAge >= 0, "CORS_MAX_AGE: must not be negative")
+	check(c.RateLimit.Capacity >= 0, "RATE_LIMIT_CAPACITY: must not be negative")
+	check(c.RateLimit.Capacity == 0 || c.RateLimit.Rate > 0, "RATE_LIMIT_RATE: must be positive")
+	check(oneOf(c.RateLimit.Store, "memory", "postgres", "redis"), "RATE_LIMIT_STORE: must be one of memory, postgres, redis, got %q", c.RateLimit.Store)
+	policies, err := c.RateLimit.RateLimitPolicies()
+	check(err == nil, "RATE_LIMIT_POLICIES: %v", err)
+	for _, p := range policies {
+		check(oneOf(c.RateLimit.Store, rateLimitAlgorithms[p.Algorithm]...),
+			"RATE_LIMIT_POLICIES: %s: algorithm %s is not supported by RATE_LIMIT_STORE=%s", p.Name, p.Algorithm, c.RateLimit.Store)
+	}
+	for _, ip := range c.RateLimit.ExemptIPs {
+		check(validIPOrCIDR(ip), "RATE_LIMIT_EXEMPT_IPS: invalid IP or CIDR %q", ip)
+	}
+	if c.RateLimit.Store == "redis" {
+		u, err := url.Parse(c.RateLimit.RedisURL)
+		check(err == nil && (u.Scheme == "redis" || u.Scheme == "rediss") && u.Host != "",
+			"RATE_LIMIT_REDIS_URL: redis:// or rediss:// URL required when RATE_LIMIT_STORE=redis")
+	}
+
+	check(c.DB.DSN != "", "DB_DSN: required")
+	if c.DB.Password != "" {
+		u, err := url.Parse(c.DB.DSN)
+		check(err == nil && u.User != nil, "DB_PASSWORD: requires DB_DSN in URL form with a user name")
+	}
+
+	check(oneOf(c.Log.Level, "debug", "info", "warn", "error"), "LOG_LEVEL: must be one of debug, info, warn, error, got %q", c.Log.Level)
+	check(oneOf(c.Log.SamplingLevel, "debug", "info", "warn", "error"), "LOG_SAMPLING_LEVEL: must be one of debug, info, warn, error, got %q", c.Log.SamplingLevel)
+	check(c.Log.SamplingInitial >= 0, "LOG_SAMPLING_INITIAL: must not be negative")
+	check(c.Log.SamplingThereafter >= 0, "LOG_SAMPLING_THEREAFTER: must not be negative")
+	check(c.Log.SamplingTick > 0, "LOG_SAMPLING_TICK: must be positive")
+	check(c.Log.MaxSizeMB >= 0, "LOG_MAX_SIZE_MB: must not be negative")
+	check(c.Log.RotateInterval >= 0, "LOG_ROTATE_INTERVAL: must not be negative")
+	check(c.Log.MaxAge >= 0, "LOG_MAX_AGE: must not be negative")
+	check(c.Log.MaxBackups >= 0, "LOG_MAX_BACKUPS: must not be negative")
+
+	check(c.JWT.ExpireDuration > 0, "JWT_EXPIRE_DURATION: must be positive")
+	check(c.JWT.RefreshExpireDuration > c.JWT.ExpireDuration, "REFRESH_TOKEN_EXPIRE_DURATION: must be longer than JWT_EXPIRE_DURATION")
+	check(oneOf(c.JWT.RevocationStore, "postgres", "memory"), "TOKEN_REVOCATION_STORE: must be postgres or memory, got %q", c.JWT.RevocationStore)
+	check(c.JWT.ActiveKID == "" || c.JWT.KeysDir != "", "JWT_ACTIVE_KID: requires JWT_KEYS_DIR")
+	check(c.JWT.KeysDir != "" || c.JWT.Secret != "", "JWT_SECRET: required when JWT_KEYS_DIR is not set")
+
+	check(c.Login.PhoneFreeAttempts >= 0, "LOGIN_PHONE_FREE_ATTEMPTS: must not be negative")
+	check(c.Login.PhoneMaxFailures > c.Login.PhoneFreeAttempts, "LOGIN_PHONE_MAX_FAILURES: must be greater than LOGIN_PHONE_FREE_ATTEMPTS")
+	check(c.Login.IPFreeAttempts >= 0, "LOGIN_IP_FREE_ATTEMPTS: must not be negative")
+	check(c.Login.IPMaxFailures > c.Login.IPFreeAttempts, "LOGIN_IP_MAX_FAILURES: must be greater than LOGIN_IP_FREE_ATTEMPTS")
+	check(c.Login.DelayBase > 0, "LOGIN_DELAY_BASE: must be positive")
+	check(c.Login.DelayMax >= c.Login.DelayBase, "LOGIN_DELAY_MAX: must not be shorter than LOGIN_DELAY_BASE")
+	check(c.Login.LockoutDuration > 0, "LOGIN_LOCKOUT_DURATION: must be positive")
+	check(c.Login.FailureWindow > 0, "LOGIN_FAILURE_WINDOW: must be positive")
+
+	check(c.Password.MinLength >= 4, "PASSWORD_MIN_LENGTH: must be at least 4")
+	check(c.Password.MaxLength >= c.Password.MinLength && c.Password.MaxLength <= 72,
+		"PASSWORD_MAX_LENGTH: must be between PASSWORD_MIN_LENGTH and 72")
+	check(c.Password.MinCharClasses >= 0 && c.Password.MinCharClasses <= 4, "PASSWORD_MIN_CHAR_CLASSES: must be between 0 and 4")
+	check(c.Password.History >= 0, "PASSWORD_HISTORY: must not be negative")
+	check(c.Password.MaxAge >= 0, "PASSWORD_MAX_AGE: must not be negative")
+
+	check(c.TwoFactor.Issuer != "" && !strings.Contains(c.TwoFactor.Issuer, ":"), "TOTP_ISSUER: must be non-empty without ':'")
+	if c.TwoFactor.EncryptionKey != "" {
+		_, err = ParseMasterKey(c.TwoFactor.EncryptionKey)
+		check(err == nil, "TOTP_ENCRYPTION_KEY: %v", err)
+	}
+	if c.Verify.HashKey != "" {
+		_, err = ParseMasterKey(c.Verify.HashKey)
+		check(err == nil, "VERIFY_CODE_HASH_KEY: %v", err)
+	}
+	check(c.TwoFactor.ChallengeTTL > 0, "TWO_FACTOR_CHALLENGE_TTL: must be positive")
+	check(c.TwoFactor.MaxAttempts > 0, "TWO_FACTOR_MAX_ATTEMPTS: must be positive")
+	check(c.TwoFactor.RecoveryCodes > 0, "TWO_FACTOR_RECOVERY_CODES: must be positive")
+
+	check(oneOf(c.SMS.Provider, "console", "file", "webhook"), "SMS_PROVIDER: must be one of console, file, webhook, got %q", c.SMS.Provider)
+	check(c.SMS.Provider != "file" || c.SMS.FilePath != "", "SMS_FILE_PATH: required when SMS_PROVIDER=file")
+	if c.SMS.Provider == "webhook" {
+		u, err := url.Parse(c.SMS.WebhookURL)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"SMS_WEBHOOK_URL: http:// or https:// URL required when SMS_PROVIDER=webhook")
+	}
+	check(c.Verify.Length >= 4 && c.Verify.Length <= 10, "VERIFY_CODE_LENGTH: must be between 4 and 10")
+	check(c.Verify.TTL > 0, "VERIFY_CODE_TTL: must be positive")
+	check(c.Verify.MaxAttempts > 0, "VERIFY_CODE_MAX_ATTEMPTS: must be positive")
+	check(c.Verify.ResendInterval >= 0, "VERIFY_CODE_RESEND_INTERVAL: must not be negative")
+	check(c.Verify.DailyLimit > 0, "VERIFY_CODE_DAILY_LIMIT: must be positive")
+
+	check(oneOf(c.Mail.Provider, "console", "file", "smtp"), "MAIL_PROVIDER: must be one of console, file, smtp, got %q", c.Mail.Provider)
+	check(c.Mail.Provider != "file" || c.Mail.FilePath != "", "MAIL_FILE_PATH: required when MAIL_PROVIDER=file")
+	if c.Mail.Provider == "smtp" {
+		check(c.Mail.SMTPHost != "", "SMTP_HOST: required when MAIL_PROVIDER=smtp")
+		check(c.Mail.SMTPPort > 0 && c.Mail.SMTPPort < 65536, "SMTP_PORT: invalid port %d", c.Mail.SMTPPort)
+		_, err := mail.ParseAddress(c.Mail.From)
+		check(err == nil, "MAIL_FROM: valid address required when MAIL_PROVIDER=smtp")
+	}
+	check(c.Reset.TokenTTL > 0, "PASSWORD_RESET_TOKEN_TTL: must be positive")
+	check(c.Reset.ResendInterval >= 0, "PASSWORD_RESET_RESEND_INTERVAL: must not be negative")
+	resetURL, err := url.Parse(c.Reset.URL)
+	check(err == nil && (resetURL.Scheme == "http" || resetURL.Scheme == "https") && resetURL.Host != "",
+		"PASSWORD_RESET_URL: http:// or https:// URL required")
+
+	check(oneOf(c.Tracing.Exporter, "none", "otlp", "stdout", "memory"), "TRACING_EXPORTER: must be one of none, otlp, stdout, memory, got %q", c.Tracing.Exporter)
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO: must be between 0 and 1")
+
+	check(c.OSS.TokenExpireSeconds > 0, "OSS_TOKEN_EXPIRE_SECONDS: must be positive")
+
+	if c.IsProd() {
+		if c.JWT.KeysDir == "" {
+			check(!insecure(c.JWT.Secret), "JWT_SECRET: example secret is not allowed in prod")
+			check(len(c.JWT.Secret) >= minSecretLength, "JWT_SECRET: must be at least %d bytes in prod", minSecretLength)
+		}
+		check(!insecure(c.OSS.AccessKeySecret), "OSS_ACCESS_KEY_SECRET: example secret is not allowed in prod")
+		check(c.Tracing.Exporter != "memory", "TRACING_EXPORTER: memory exporter is for tests only")
+		// console 会把验证码和重置链接写入日志，file 仅用于测试
+		check(!oneOf(c.SMS.Provider, "console", "file"), "SMS_PROVIDER: %s provider is not allowed in prod", c.SMS.Provider)
+		check(!oneOf(c.Mail.Provider, "console", "file"), "MAIL_PROVIDER: %s provider is not allowed in prod", c.Mail.Provider)
+		check(c.TwoFactor.EncryptionKey != "", "TOTP_ENCRYPTION_KEY: required in prod")
+		check(c.Verify.HashKey != "", "VERIFY_CODE_HASH_KEY: required in prod")
+		check(!(c.CORS.AllowCredentials && oneOf("*", c.CORS.AllowedOrigins...)),
+			"CORS_ALLOWED_ORIGINS: * with CORS_ALLOW_CREDENTIALS is not allowed in prod")
+	}
+	return errors.Join(errs...)
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+func insecure(secret string) bool {
+	lower := strings.ToLower(secret)
+	for _, marker := range insecureMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"minigo/internal/infrastructure/logging"
+)
+
+// reloadDebounce 编辑器保存时会连续产生多个事件，合并后只重新加载一次
+const reloadDebounce = 200 * time.Millisecond
+
+// Subscriber 配置热更新回调，old 与 cfg 只读
+type Subscriber func(old, cfg *Config)
+
+// Watcher holds the config currently in effect and reloads it when the
+// config file changes. 只有带 reload 标签的字段会热更新，其余字段的变化
+// 需要重启才能生效；新配置未通过校验时保留上一份有效配置。
+type Watcher struct {
+	current atomic.Pointer[Config]
+
+	mu   sync.Mutex // 串行化 Reload 与 Subscribe
+	subs []Subscriber
+}
+
+func NewWatcher(cfg *Config) *Watcher {
+	w := &Watcher{}
+	w.current.Store(cfg)
+	return w
+}
+
+// Current 返回当前生效的配置，调用方不应修改
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe 注册热更新回调；回调只在配置变化时调用，初始值需调用方自行应用
+func (w *Watcher) Subscribe(fn Subscriber) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subs = append(w.subs, fn)
+}
+
+// Reload 重新读取配置文件和环境变量，校验通过后原子替换当前配置并通知订阅者
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	old := w.current.Load()
+	if old.file == "" {
+		return errors.New("no config file in use")
+	}
+	next, err := loadFile(old.file)
+	if err != nil {
+		return err
+	}
+
+	merged, changed, restart := mergeReloadable(old, next)
+	if len(restart) > 0 {
+		logging.L().WithField("keys", restart).Warn("config_change_requires_restart")
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	if err = merged.Validate(); err != nil {
+		return err
+	}
+	w.current.Store(merged)
+	for _, fn := range w.subs {
+		fn(old, merged)
+	}
+	logging.L().WithField("keys", changed).Info("config_reloaded")
+	return nil
+}
+
+// Run 监听配置文件变化直到 ctx 取消；未使用配置文件时直接返回。
+// 监听所在目录而不是文件本身，以支持编辑器的原子保存和 k8s ConfigMap 的符号链接替换。
+func (w *Watcher) Run(ctx context.Context) error {
+	file := w.Current().file
+	if file == "" {
+		return nil
+	}
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create config watcher: %w", err)
+	}
+	defer fw.Close()
+	if err = fw.Add(filepath.Dir(file)); err != nil {
+		return fmt.Errorf("watch config dir: %w", err)
+	}
+
+	realFile, _ := filepath.EvalSymlinks(file)
+	timer := time.NewTimer(reloadDebounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-fw.Events:
+			if !ok {
+				return nil
+			}
+			current, _ := filepath.EvalSymlinks(file)
+			if filepath.Clean(event.Name) == filepath.Clean(file) || current != realFile {
+				realFile = current
+				timer.Reset(reloadDebounce)
+			}
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return nil
+			}
+			logging.L().WithError(err).Error("config_watch_error")
+		case <-timer.C:
+			if err := w.Reload(); err != nil {
+				logging.L().WithError(err).Error("config_reload_rejected")
+			}
+		}
+	}
+}
+
+// mergeReloadable 以 old 为基础应用 next 中可热更新字段的变化，
+// 返回合并结果、已生效的变化项和需要重启才能生效的变化项
+func mergeReloadable(old, next *Config) (merged *Config, changed, restart []string) {
+	copied := *old
+	merged = &copied
+
+	nextFields := make(map[string]reflect.Value)
+	eachField(reflect.ValueOf(next).Elem(), func(field reflect.StructField, fv reflect.Value) {
+		nextFields[field.Tag.Get("env")] = fv
+	})
+	eachField(reflect.ValueOf(merged).Elem(), func(field reflect.StructField, fv reflect.Value) {
+		key := field.Tag.Get("env")
+		nv := nextFields[key]
+		if reflect.DeepEqual(fv.Interface(), nv.Interface()) {
+			return
+		}
+		if field.Tag.Get("reload") != "true" {
+			restart = append(restart, key)
+			return
+		}
+		fv.Set(nv)
+		changed = append(changed, key)
+	})
+	sort.Strings(changed)
+	sort.Strings(restart)
+	return merged, changed, restart
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "env: dev\nlog_level: info\nrate_limit_capacity: 200\n")
+	cfg, err := loadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	w := NewWatcher(cfg)
+
+	var (
+		calls            int
+		lastOld, lastNew *Config
+	)
+	w.Subscribe(func(old, cfg *Config) {
+		calls++
+		lastOld, lastNew = old, cfg
+	})
+
+	t.Run("applies reloadable changes and keeps static ones", func(t *testing.T) {
+		writeConfig(t, path, "env: dev\nlog_level: debug\nrate_limit_capacity: 200\nhttp_read_timeout: 1m\n")
+		if err := w.Reload(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		current := w.Current()
+		if current.Log.Level != "debug" || calls != 1 {
+			t.Fatalf("Expected level debug after one callback, got %s (%d calls)", current.Log.Level, calls)
+		}
+		if lastOld.Log.Level != "info" || lastNew.Log.Level != "debug" {
+			t.Fatalf("Expected info -> debug, got %s -> %s", lastOld.Log.Level, lastNew.Log.Level)
+		}
+		if current.HTTP.ReadTimeout != 15*time.Second {
+			t.Fatalf("Expected static HTTP_READ_TIMEOUT kept until restart, got %s", current.HTTP.ReadTimeout)
+		}
+	})
+
+	t.Run("reloads feature flags", func(t *testing.T) {
+		if flags := w.Current().Features.Enabled; len(flags) != 1 || flags[0] != "sms_login" {
+			t.Fatalf("Expected sms_login on by default, got %v", flags)
+		}
+		writeConfig(t, path, "env: dev\nlog_level: debug\nrate_limit_capacity: 200\nfeature_flags: beta\n")
+		if err := w.Reload(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if flags := w.Current().Features.Enabled; len(flags) != 1 || flags[0] != "beta" || calls != 2 {
+			t.Fatalf("Expected only beta enabled after reload, got %v (%d calls)", flags, calls)
+		}
+	})
+
+	t.Run("rejects invalid config and keeps the last good one", func(t *testing.T) {
+		before := w.Current()
+		writeConfig(t, path, "env: dev\nlog_level: verbose\nrate_limit_capacity: 0\n")
+		if err := w.Reload(); err == nil {
+			t.Fatal("Expected invalid config to be rejected")
+		}
+		if w.Current() != before || calls != 2 {
+			t.Fatalf("Expected config unchanged and no callbacks, got %d calls", calls)
+		}
+	})
+}
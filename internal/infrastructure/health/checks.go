@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"minigo/internal/infrastructure/config"
+	"minigo/internal/infrastructure/migrate"
+
+	"github.com/uptrace/bun"
+)
+
+// DatabaseCheck pings PostgreSQL.
+func DatabaseCheck(db *bun.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// Cached 在 ttl 内复用 fn 上一次的结果，用于开销较大、结果变化缓慢的检查。
+// 并发调用串行执行，同一时间最多一次实际检查；超时或取消的结果不缓存
+func Cached(ttl time.Duration, fn CheckFunc) CheckFunc {
+	var (
+		mu        sync.Mutex
+		err       error
+		checkedAt time.Time
+	)
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !checkedAt.IsZero() && time.Since(checkedAt) < ttl {
+			return err
+		}
+		result := fn(ctx)
+		if ctx.Err() != nil {
+			return result
+		}
+		err, checkedAt = result, time.Now()
+		return err
+	}
+}
+
+// MigrationCheck 存在未应用的迁移时视为异常（只读查询，不加锁）
+func MigrationCheck(migrator *migrate.Migrator) CheckFunc {
+	return func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d pending migrations", pending)
+		}
+		return nil
+	}
+}
+
+// OSSConfigCheck 检查 OSS 配置是否完整
+func OSSConfigCheck(cfg config.OSSConfig) CheckFunc {
+	return func(ctx context.Context) error {
+		var missing []string
+		for key, value := range map[string]string{
+			"OSS_ENDPOINT":          cfg.Endpoint,
+			"OSS_ACCESS_KEY_ID":     cfg.AccessKeyID,
+			"OSS_ACCESS_KEY_SECRET": cfg.AccessKeySecret,
+			"OSS_BUCKET_NAME":       cfg.BucketName,
+		} {
+			if value == "" {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+		}
+		return nil
+	}
+}
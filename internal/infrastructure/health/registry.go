@@ -0,0 +1,186 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status 组件或整体健康状态
+type Status string
+
+const (
+	StatusHealthy   Status = "healthy"
+	StatusDegraded  Status = "degraded"  // 非关键组件异常，仍可对外服务
+	StatusUnhealthy Status = "unhealthy" // 关键组件异常或正在停机
+)
+
+// defaultTimeout 单个检查的默认超时
+const defaultTimeout = 2 * time.Second
+
+// CheckFunc 执行一次检查，返回 nil 表示健康
+type CheckFunc func(ctx context.Context) error
+
+// Check 注册到 Registry 的一个检查项
+type Check struct {
+	Name     string
+	Critical bool          // 关键检查失败时整体不健康、readiness 失败；否则仅降级
+	Timeout  time.Duration // 为 0 时使用 defaultTimeout
+	Fn       CheckFunc
+}
+
+// ComponentResult 单个组件的检查结果
+type ComponentResult struct {
+	Name      string  `json:"name"`
+	Status    Status  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report 健康检查汇总
+type Report struct {
+	Status       Status            `json:"status"`
+	ShuttingDown bool              `json:"shuttingDown,omitempty"`
+	CheckedAt    time.Time         `json:"checkedAt"`
+	Components   []ComponentResult `json:"components"`
+}
+
+// Redacted 返回去掉组件错误信息的副本，供未鉴权的探针输出；错误可能包含配置项名称等内部信息
+func (r Report) Redacted() Report {
+	components := make([]ComponentResult, len(r.Components))
+	for i, component := range r.Components {
+		component.Error = ""
+		components[i] = component
+	}
+	r.Components = components
+	return r
+}
+
+// Registry 可插拔的健康检查注册表
+type Registry struct {
+	mu           sync.RWMutex
+	checks       []Check
+	shuttingDown atomic.Bool
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a check; checks run in registration order in the report.
+func (r *Registry) Register(check Check) {
+	if check.Timeout <= 0 {
+		check.Timeout = defaultTimeout
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check)
+}
+
+// SetShuttingDown marks the process as draining so readiness fails.
+func (r *Registry) SetShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// ShuttingDown reports whether graceful shutdown has started.
+func (r *Registry) ShuttingDown() bool {
+	return r.shuttingDown.Load()
+}
+
+// Run executes all checks concurrently and rolls up the result.
+func (r *Registry) Run(ctx context.Context) Report {
+	return r.run(ctx, false)
+}
+
+// Ready 仅执行关键检查；停机过程中直接返回不健康
+func (r *Registry) Ready(ctx context.Context) Report {
+	if r.ShuttingDown() {
+		return Report{
+			Status:       StatusUnhealthy,
+			ShuttingDown: true,
+			CheckedAt:    time.Now(),
+			Components:   []ComponentResult{},
+		}
+	}
+	return r.run(ctx, true)
+}
+
+func (r *Registry) run(ctx context.Context, criticalOnly bool) Report {
+	r.mu.RLock()
+	checks := make([]Check, 0, len(r.checks))
+	for _, check := range r.checks {
+		if criticalOnly && !check.Critical {
+			continue
+		}
+		checks = append(checks, check)
+	}
+	r.mu.RUnlock()
+
+	results := make([]ComponentResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:       rollup(results),
+		ShuttingDown: r.ShuttingDown(),
+		CheckedAt:    time.Now(),
+		Components:   results,
+	}
+	if report.ShuttingDown {
+		report.Status = StatusUnhealthy
+	}
+	return report
+}
+
+// runCheck 在超时控制下执行单个检查
+func runCheck(ctx context.Context, check Check) ComponentResult {
+	ctx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check.Fn(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := ComponentResult{
+		Name:      check.Name,
+		Status:    StatusHealthy,
+		Critical:  check.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusUnhealthy
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// rollup 关键组件失败为不健康，非关键组件失败为降级
+func rollup(results []ComponentResult) Status {
+	status := StatusHealthy
+	for _, result := range results {
+		if result.Status == StatusHealthy {
+			continue
+		}
+		if result.Critical {
+			return StatusUnhealthy
+		}
+		status = StatusDegraded
+	}
+	return status
+}
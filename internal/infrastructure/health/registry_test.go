@@ -0,0 +1,130 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func ok(ctx context.Context) error { return nil }
+
+func fail(ctx context.Context) error { return errors.New("boom") }
+
+func TestRegistry(t *testing.T) {
+	t.Run("all checks passing is healthy", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register(Check{Name: "db", Critical: true, Fn: ok})
+		r.Register(Check{Name: "oss", Fn: ok})
+
+		report := r.Run(context.Background())
+		if report.Status != StatusHealthy {
+			t.Fatalf("Expected healthy, got %s", report.Status)
+		}
+		if len(report.Components) != 2 || report.Components[0].Name != "db" {
+			t.Fatalf("Expected components in registration order, got %+v", report.Components)
+		}
+	})
+
+	t.Run("non-critical failure degrades", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register(Check{Name: "db", Critical: true, Fn: ok})
+		r.Register(Check{Name: "oss", Fn: fail})
+
+		report := r.Run(context.Background())
+		if report.Status != StatusDegraded {
+			t.Fatalf("Expected degraded, got %s", report.Status)
+		}
+		if report.Components[1].Error != "boom" {
+			t.Fatalf("Expected error to be reported, got %q", report.Components[1].Error)
+		}
+		if ready := r.Ready(context.Background()); ready.Status != StatusHealthy {
+			t.Fatalf("Expected readiness to ignore non-critical checks, got %s", ready.Status)
+		}
+	})
+
+	t.Run("critical failure is unhealthy", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register(Check{Name: "db", Critical: true, Fn: fail})
+		r.Register(Check{Name: "oss", Fn: fail})
+
+		if report := r.Run(context.Background()); report.Status != StatusUnhealthy {
+			t.Fatalf("Expected unhealthy, got %s", report.Status)
+		}
+	})
+
+	t.Run("slow check times out", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register(Check{Name: "slow", Critical: true, Timeout: 10 * time.Millisecond, Fn: func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		}})
+
+		start := time.Now()
+		report := r.Run(context.Background())
+		if report.Status != StatusUnhealthy {
+			t.Fatalf("Expected unhealthy on timeout, got %s", report.Status)
+		}
+		if time.Since(start) > 500*time.Millisecond {
+			t.Fatalf("Expected run to return after the check timeout")
+		}
+	})
+
+	t.Run("readiness fails during shutdown", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register(Check{Name: "db", Critical: true, Fn: ok})
+		r.SetShuttingDown()
+
+		if report := r.Ready(context.Background()); report.Status != StatusUnhealthy || !report.ShuttingDown {
+			t.Fatalf("Expected unhealthy while shutting down, got %+v", report)
+		}
+	})
+}
+
+func TestCached(t *testing.T) {
+	var calls int
+	err := errors.New("boom")
+	check := Cached(time.Minute, func(ctx context.Context) error {
+		calls++
+		return err
+	})
+
+	t.Run("reuses the result within the ttl", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			if got := check(context.Background()); got != err {
+				t.Fatalf("Expected cached error, got %v", got)
+			}
+		}
+		if calls != 1 {
+			t.Fatalf("Expected 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("does not cache cancelled checks", func(t *testing.T) {
+		calls = 0
+		slow := Cached(time.Minute, func(ctx context.Context) error {
+			calls++
+			return ctx.Err()
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		slow(ctx)
+		if got := slow(context.Background()); got != nil || calls != 2 {
+			t.Fatalf("Expected a fresh check after cancellation, got %v (%d calls)", got, calls)
+		}
+	})
+}
+
+func TestReportRedacted(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Check{Name: "oss", Fn: fail})
+	report := r.Run(context.Background())
+
+	redacted := report.Redacted()
+	if redacted.Components[0].Error != "" || redacted.Components[0].Status != StatusUnhealthy {
+		t.Fatalf("Expected error dropped and status kept, got %+v", redacted.Components[0])
+	}
+	if report.Components[0].Error != "boom" {
+		t.Fatal("Expected the original report untouched")
+	}
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 上下文日志字段名
+const (
+	FieldRequestID = "request_id"
+	FieldUserID    = "user_id"
+	FieldRoute     = "route"
+	FieldTraceID   = "trace_id"
+	FieldSpanID    = "span_id"
+)
+
+type fieldsKey struct{}
+
+// WithFields returns a context whose logger carries fields in addition to
+// those already attached to ctx.
+// 中间件在请求开始时写入 request_id、route，认证后写入 user_id
+func WithFields(ctx context.Context, fields logrus.Fields) context.Context {
+	merged := make(logrus.Fields, len(fields))
+	for k, v := range contextFields(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// WithField is WithFields for a single field.
+func WithField(ctx context.Context, key string, value interface{}) context.Context {
+	return WithFields(ctx, logrus.Fields{key: value})
+}
+
+// FromContext returns the global logger bound to the request fields and the
+// current trace/span IDs carried by ctx.
+func FromContext(ctx context.Context) *logrus.Entry {
+	return entryFromContext(L(), ctx)
+}
+
+// AccessFromContext is FromContext for the access logger.
+func AccessFromContext(ctx context.Context) *logrus.Entry {
+	return entryFromContext(Access(), ctx)
+}
+
+func entryFromContext(l *logrus.Logger, ctx context.Context) *logrus.Entry {
+	entry := logrus.NewEntry(l).WithContext(ctx)
+	if ctx == nil {
+		return entry
+	}
+	return entry.WithFields(Fields(ctx))
+}
+
+// Fields 返回上下文中携带的全部日志字段（含 trace_id/span_id）
+func Fields(ctx context.Context) logrus.Fields {
+	fields := make(logrus.Fields)
+	for k, v := range contextFields(ctx) {
+		fields[k] = v
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields[FieldTraceID] = sc.TraceID().String()
+		fields[FieldSpanID] = sc.SpanID().String()
+	}
+	return fields
+}
+
+func contextFields(ctx context.Context) logrus.Fields {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).(logrus.Fields)
+	return fields
+}
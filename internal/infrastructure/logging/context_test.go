@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func captureLogger(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.SetOutput(&buf)
+	l.SetFormatter(&logrus.JSONFormatter{})
+	prev := logger
+	logger = l
+	t.Cleanup(func() { logger = prev })
+	return &buf
+}
+
+func decodeLine(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Expected one JSON log line, got %q: %v", buf.String(), err)
+	}
+	return line
+}
+
+func TestFromContext(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	ctx = WithFields(ctx, logrus.Fields{FieldRequestID: "req-1", FieldRoute: "/api/auth/me"})
+	ctx = WithField(ctx, FieldUserID, int64(7))
+
+	t.Run("carries request, user and trace fields", func(t *testing.T) {
+		buf := captureLogger(t)
+		FromContext(ctx).Info("hello")
+
+		line := decodeLine(t, buf)
+		expected := map[string]interface{}{
+			FieldRequestID: "req-1",
+			FieldRoute:     "/api/auth/me",
+			FieldUserID:    float64(7),
+			FieldTraceID:   traceID.String(),
+			FieldSpanID:    spanID.String(),
+		}
+		for k, v := range expected {
+			if line[k] != v {
+				t.Fatalf("Expected %s=%v, got %v", k, v, line[k])
+			}
+		}
+	})
+
+	t.Run("slog goes through the same logger", func(t *testing.T) {
+		buf := captureLogger(t)
+		slog.New(NewSlogHandler(L())).ErrorContext(ctx, "db_error", "error", errors.New("boom"), slog.Group("db", "table", "users"))
+
+		line := decodeLine(t, buf)
+		if line["level"] != "error" || line["msg"] != "db_error" {
+			t.Fatalf("Expected error level db_error, got %v", line)
+		}
+		if line[FieldRequestID] != "req-1" || line["error"] != "boom" || line["db.table"] != "users" {
+			t.Fatalf("Expected context, error and grouped fields, got %v", line)
+		}
+	})
+}
@@ -1,38 +1,162 @@
 package logging
 
 import (
-	"os"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
 )
 
-var logger *logrus.Logger
+var (
+	logger *logrus.Logger
+	// accessLogger 单独配置访问日志输出时使用，否则访问日志写入 logger
+	accessLogger *logrus.Logger
+	closers      []io.Closer
+	// levelOverridden 级别已通过管理接口在运行时调整，重启前不再跟随 LOG_LEVEL 热更新
+	levelOverridden atomic.Bool
+)
+
+// Options 日志初始化参数
+type Options struct {
+	Level          string
+	RedactFields   []string // 在默认字段之外追加的脱敏字段名
+	RedactPatterns []string // 在默认规则之外追加的脱敏正则
+	Sampling       SamplingOptions
+	// Output 应用日志输出，未配置任何目标时写 stdout
+	Output SinkOptions
+	// Access 访问日志输出，未配置任何目标时与应用日志共用输出
+	Access SinkOptions
+}
 
-// Init configures a global logrus logger.
-func Init(level string) {
+// Init configures a global logrus logger and routes log/slog through it.
+// 输出前先采样、再脱敏；脱敏规则无效或日志文件无法打开时返回错误并保持未初始化
+func Init(opts Options) error {
 	if logger != nil {
-		return
+		return nil
+	}
+	redactor, err := NewRedactor(opts.RedactFields, opts.RedactPatterns)
+	if err != nil {
+		return err
+	}
+
+	out, closer, err := openSink(opts.Output)
+	if err != nil {
+		return err
 	}
+	var opened []io.Closer
+	if closer != nil {
+		opened = append(opened, closer)
+	}
+
 	l := logrus.New()
-	l.SetOutput(os.Stdout)
-	l.SetFormatter(&logrus.JSONFormatter{})
-	switch level {
-	case "debug":
-		l.SetLevel(logrus.DebugLevel)
-	case "warn":
-		l.SetLevel(logrus.WarnLevel)
-	case "error":
-		l.SetLevel(logrus.ErrorLevel)
-	default:
-		l.SetLevel(logrus.InfoLevel)
+	l.SetOutput(out)
+	l.SetFormatter(&samplingFormatter{
+		inner:   &redactingFormatter{inner: &logrus.JSONFormatter{}, redactor: redactor},
+		sampler: newSampler(opts.Sampling),
+	})
+	l.SetLevel(parseLevel(opts.Level))
+
+	var access *logrus.Logger
+	if opts.Access.enabled() {
+		accessOut, accessCloser, err := openSink(opts.Access)
+		if err != nil {
+			closeAll(opened)
+			return err
+		}
+		if accessCloser != nil {
+			opened = append(opened, accessCloser)
+		}
+		// 访问日志均为 info 级别，不随应用日志级别变化，也不采样
+		access = logrus.New()
+		access.SetOutput(accessOut)
+		access.SetFormatter(&redactingFormatter{inner: &logrus.JSONFormatter{}, redactor: redactor})
+		access.SetLevel(logrus.InfoLevel)
 	}
+
 	logger = l
+	accessLogger = access
+	configuredRedactor.Store(redactor)
+	closers = opened
+	slog.SetDefault(slog.New(NewSlogHandler(l)))
+	return nil
 }
 
 // L returns the global logger.
 func L() *logrus.Logger {
 	if logger == nil {
-		Init("info")
+		_ = Init(Options{Level: "info"})
 	}
 	return logger
 }
+
+// Access returns the logger used for HTTP access logs.
+func Access() *logrus.Logger {
+	if accessLogger != nil {
+		return accessLogger
+	}
+	return L()
+}
+
+// Level 返回应用日志当前级别
+func Level() string {
+	return L().GetLevel().String()
+}
+
+// SetLevel 运行时调整应用日志级别，支持 debug、info、warn、error
+func SetLevel(level string) error {
+	switch level {
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("unsupported log level %q", level)
+	}
+	L().SetLevel(parseLevel(level))
+	return nil
+}
+
+// OverrideLevel 运行时调整级别（管理接口），此后配置热更新不再覆盖，直到重启
+func OverrideLevel(level string) error {
+	if err := SetLevel(level); err != nil {
+		return err
+	}
+	levelOverridden.Store(true)
+	return nil
+}
+
+// LevelOverridden 当前级别是否来自运行时调整
+func LevelOverridden() bool {
+	return levelOverridden.Load()
+}
+
+// Close 关闭日志文件，进程退出前调用
+func Close() error {
+	err := closeAll(closers)
+	closers = nil
+	return err
+}
+
+func closeAll(cs []io.Closer) error {
+	var errs []error
+	for _, c := range cs {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseLevel 未知级别按 info 处理
+func parseLevel(level string) logrus.Level {
+	switch level {
+	case "debug":
+		return logrus.DebugLevel
+	case "warn":
+		return logrus.WarnLevel
+	case "error":
+		return logrus.ErrorLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
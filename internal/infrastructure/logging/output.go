@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"io"
+	"os"
+)
+
+// SinkOptions 日志输出目标，Stdout 与 File 可同时开启
+type SinkOptions struct {
+	Stdout bool
+	File   string // 日志文件路径，为空时不写文件
+	Rotate RotateOptions
+}
+
+func (o SinkOptions) enabled() bool {
+	return o.Stdout || o.File != ""
+}
+
+// openSink 返回写入全部目标的 writer；写文件时同时返回需要在退出前关闭的文件
+func openSink(opts SinkOptions) (io.Writer, io.Closer, error) {
+	if opts.File == "" {
+		return os.Stdout, nil, nil
+	}
+	file, err := NewRotatingFile(opts.File, opts.Rotate)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.Stdout {
+		return io.MultiWriter(os.Stdout, file), file, nil
+	}
+	return file, file, nil
+}
@@ -0,0 +1,205 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Redacted 脱敏后的占位值
+const Redacted = "[REDACTED]"
+
+// defaultRedactFields 默认脱敏的字段名（比较时忽略大小写、下划线和连字符）
+var defaultRedactFields = []string{
+	"password", "old_password", "new_password", "password_hash",
+	"authorization", "cookie", "set-cookie", "x-api-key", "api_key",
+	"token", "access_token", "refresh_token", "challenge_token",
+	"secret", "jwt_secret", "access_key_secret", "oss_access_key_secret",
+}
+
+// phoneFields 按手机号规则打码的字段名
+var phoneFields = []string{"phone", "mobile", "new_phone"}
+
+var (
+	phonePattern = regexp.MustCompile(`\b(1[3-9]\d)\d{4}(\d{4})\b`)
+	// defaultRedactPatterns 在任意字符串值（包括消息和 SQL）中替换的敏感片段
+	defaultRedactPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\$2[aby]\$\d{2}\$[./A-Za-z0-9]{53}`),                  // bcrypt 哈希
+		regexp.MustCompile(`(?i)\bBearer\s+[A-Za-z0-9\-._~+/]+=*`),                // Authorization 头
+		regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`), // JWT
+	}
+)
+
+// Redactor masks sensitive fields and substrings before log entries are written.
+type Redactor struct {
+	fields   map[string]struct{}
+	phones   map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor 在默认规则之上追加字段名和正则
+func NewRedactor(extraFields []string, extraPatterns []string) (*Redactor, error) {
+	r := &Redactor{
+		fields:   make(map[string]struct{}),
+		phones:   make(map[string]struct{}),
+		patterns: append([]*regexp.Regexp{}, defaultRedactPatterns...),
+	}
+	for _, f := range append(append([]string{}, defaultRedactFields...), extraFields...) {
+		if f = normalizeField(f); f != "" {
+			r.fields[f] = struct{}{}
+		}
+	}
+	for _, f := range phoneFields {
+		r.phones[normalizeField(f)] = struct{}{}
+	}
+	for _, p := range extraPatterns {
+		if p == "" {
+			continue
+		}
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// defaultRedactor 仅包含默认规则
+var defaultRedactor, _ = NewRedactor(nil, nil)
+
+// configuredRedactor Init 按 LOG_REDACT_* 构建的脱敏器，未初始化时为 nil
+var configuredRedactor atomic.Pointer[Redactor]
+
+// currentRedactor 返回 Init 配置的脱敏器，未初始化时使用默认规则
+func currentRedactor() *Redactor {
+	if r := configuredRedactor.Load(); r != nil {
+		return r
+	}
+	return defaultRedactor
+}
+
+// MaskPhone 手机号打码：13812341234 -> 138****1234
+func MaskPhone(phone string) string {
+	if len(phone) < 7 {
+		return strings.Repeat("*", len(phone))
+	}
+	return phone[:3] + strings.Repeat("*", len(phone)-7) + phone[len(phone)-4:]
+}
+
+// RedactString 使用 Init 配置的规则脱敏任意文本（如 SQL 语句）
+func RedactString(s string) string {
+	return currentRedactor().String(s)
+}
+
+// String masks phone numbers and configured patterns in s.
+func (r *Redactor) String(s string) string {
+	s = phonePattern.ReplaceAllString(s, "$1****$2")
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
+
+// Fields returns a redacted copy of fields.
+func (r *Redactor) Fields(fields logrus.Fields) logrus.Fields {
+	out := make(logrus.Fields, len(fields))
+	for k, v := range fields {
+		out[k] = r.value(k, v)
+	}
+	return out
+}
+
+// value 按字段名和值类型脱敏；结构体和 map 通过 JSON 展开后递归处理
+func (r *Redactor) value(key string, v interface{}) interface{} {
+	name := normalizeField(key)
+	if _, ok := r.fields[name]; ok {
+		return Redacted
+	}
+	if _, ok := r.phones[name]; ok {
+		if s, ok := v.(string); ok {
+			return MaskPhone(s)
+		}
+	}
+
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return r.String(val)
+	case error:
+		return r.String(val.Error())
+	case fmt.Stringer:
+		return r.String(val.String())
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = r.value(k, item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = r.value(key, item)
+		}
+		return out
+	}
+
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		var decoded interface{}
+		if err = json.Unmarshal(raw, &decoded); err != nil {
+			return v
+		}
+		return r.value(key, decoded)
+	}
+	return v
+}
+
+// normalizeField 统一字段名：小写并去掉下划线、连字符
+func normalizeField(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("_", "", "-", "").Replace(name)
+}
+
+// redactingFormatter 在格式化前对消息和字段脱敏
+type redactingFormatter struct {
+	inner    logrus.Formatter
+	redactor *Redactor
+}
+
+func (f *redactingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	clone := *entry
+	clone.Message = f.redactor.String(entry.Message)
+	clone.Data = f.redactor.Fields(entry.Data)
+	return f.inner.Format(&clone)
+}
+
+// redactingWriter 对写入的每段文本脱敏，用于 bundebug 等直接写输出的组件。
+// 每次写入时取当前脱敏器，先于 Init 创建的 writer 也使用配置的规则
+type redactingWriter struct {
+	w io.Writer
+}
+
+// RedactWriter wraps w so that everything written through it is redacted
+// with the rules configured in Init.
+func RedactWriter(w io.Writer) io.Writer {
+	return &redactingWriter{w: w}
+}
+
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, currentRedactor().String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
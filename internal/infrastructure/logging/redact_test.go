@@ -0,0 +1,125 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRedactor(t *testing.T) {
+	r, err := NewRedactor([]string{"id_card"}, []string{`\b\d{17}[\dXx]\b`})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Run("masks phone numbers", func(t *testing.T) {
+		if got := MaskPhone("13812341234"); got != "138****1234" {
+			t.Fatalf("Expected 138****1234, got %s", got)
+		}
+		if got := r.String("login failed for 13812341234"); got != "login failed for 138****1234" {
+			t.Fatalf("Expected phone in message to be masked, got %s", got)
+		}
+		if got := r.String("user 1234567890123456789"); got != "user 1234567890123456789" {
+			t.Fatalf("Expected snowflake ID untouched, got %s", got)
+		}
+	})
+
+	t.Run("redacts configured fields at any depth", func(t *testing.T) {
+		type loginRequest struct {
+			Phone    string `json:"phone"`
+			Password string `json:"password"`
+		}
+		fields := r.Fields(logrus.Fields{
+			"Authorization": "Bearer abc.def",
+			"phone":         "13812341234",
+			"id_card":       "110101199001011234",
+			"payload":       loginRequest{Phone: "13812341234", Password: "secret1"},
+			"user_id":       int64(7),
+		})
+		if fields["Authorization"] != Redacted || fields["id_card"] != Redacted {
+			t.Fatalf("Expected sensitive fields redacted, got %v", fields)
+		}
+		if fields["phone"] != "138****1234" {
+			t.Fatalf("Expected phone masked, got %v", fields["phone"])
+		}
+		payload, _ := fields["payload"].(map[string]interface{})
+		if payload["password"] != Redacted || payload["phone"] != "138****1234" {
+			t.Fatalf("Expected nested payload redacted, got %v", fields["payload"])
+		}
+		if fields["user_id"] != int64(7) {
+			t.Fatalf("Expected non-sensitive fields untouched, got %v", fields["user_id"])
+		}
+	})
+
+	t.Run("redacts bcrypt hashes and custom patterns in SQL", func(t *testing.T) {
+		sql := `INSERT INTO "users" ("phone", "password", "id_card") VALUES ('13812341234', '$2a$10$abcdefghijklmnopqrstuuJ3c0n5Vbn0O8lGJp1p0yG0c2Xq1Hh2a', '110101199001011234')`
+		got := r.String(sql)
+		if strings.Contains(got, "$2a$10$") || strings.Contains(got, "110101199001011234") || !strings.Contains(got, "138****1234") {
+			t.Fatalf("Expected SQL redacted, got %s", got)
+		}
+	})
+
+	t.Run("RedactString and RedactWriter use the configured rules", func(t *testing.T) {
+		previous := configuredRedactor.Load()
+		defer configuredRedactor.Store(previous)
+
+		var buf strings.Builder
+		w := RedactWriter(&buf)
+		configuredRedactor.Store(r)
+		if got := RedactString("id 110101199001011234"); got != "id "+Redacted {
+			t.Fatalf("Expected custom pattern redacted, got %s", got)
+		}
+		if _, err := w.Write([]byte("id 110101199001011234")); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if buf.String() != "id "+Redacted {
+			t.Fatalf("Expected writer to use the configured rules, got %s", buf.String())
+		}
+	})
+
+	t.Run("rejects invalid patterns", func(t *testing.T) {
+		if _, err := NewRedactor(nil, []string{"("}); err == nil {
+			t.Fatalf("Expected error for invalid pattern")
+		}
+	})
+}
+
+func TestSampler(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newSampler(SamplingOptions{Initial: 2, Thereafter: 3, Tick: time.Second, MinLevel: logrus.WarnLevel})
+	s.now = func() time.Time { return now }
+
+	allowed := func(level logrus.Level, msg string, n int) int {
+		count := 0
+		for i := 0; i < n; i++ {
+			if s.allow(&logrus.Entry{Level: level, Message: msg}) {
+				count++
+			}
+		}
+		return count
+	}
+
+	t.Run("keeps initial then every nth per message", func(t *testing.T) {
+		if got := allowed(logrus.ErrorLevel, "db_error", 8); got != 4 {
+			t.Fatalf("Expected 4 of 8 entries (2 initial + 2 sampled), got %d", got)
+		}
+		if got := allowed(logrus.ErrorLevel, "other_error", 2); got != 2 {
+			t.Fatalf("Expected other messages counted separately, got %d", got)
+		}
+	})
+
+	t.Run("does not sample below the minimum level", func(t *testing.T) {
+		if got := allowed(logrus.InfoLevel, "http_request", 10); got != 10 {
+			t.Fatalf("Expected info entries unsampled, got %d", got)
+		}
+	})
+
+	t.Run("resets every tick", func(t *testing.T) {
+		now = now.Add(time.Second)
+		if got := allowed(logrus.ErrorLevel, "db_error", 2); got != 2 {
+			t.Fatalf("Expected counter reset after tick, got %d", got)
+		}
+	})
+}
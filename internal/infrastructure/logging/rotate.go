@@ -0,0 +1,286 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateOptions 日志文件切割与保留策略，各项为 0 时表示不启用该限制
+type RotateOptions struct {
+	MaxSizeMB  int           // 单个文件达到该大小后切割
+	Interval   time.Duration // 文件打开超过该时长后切割（如每天一次）
+	MaxAge     time.Duration // 备份文件保留时长
+	MaxBackups int           // 备份文件保留个数
+	Compress   bool          // 备份文件是否 gzip 压缩
+}
+
+// backupTimeFormat 备份文件名中的时间戳：app.log -> app-20240101T150405.000.log
+const backupTimeFormat = "20060102T150405.000"
+
+var errFileClosed = errors.New("log file is closed")
+
+// RotatingFile is an io.WriteCloser that appends to a file and rotates it by
+// size or age. 压缩和清理旧备份在后台完成，不阻塞写入。
+type RotatingFile struct {
+	filename string
+	opts     RotateOptions
+	now      func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	millCh   chan struct{}
+	millDone chan struct{}
+}
+
+// NewRotatingFile 打开（必要时创建）日志文件，已有内容会被追加
+func NewRotatingFile(filename string, opts RotateOptions) (*RotatingFile, error) {
+	return newRotatingFile(filename, opts, time.Now)
+}
+
+func newRotatingFile(filename string, opts RotateOptions, now func() time.Time) (*RotatingFile, error) {
+	r := &RotatingFile{
+		filename: filename,
+		opts:     opts,
+		now:      now,
+		millCh:   make(chan struct{}, 1),
+		millDone: make(chan struct{}),
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return nil, fmt.Errorf("create log dir: %w", err)
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	go r.millLoop()
+	// 启动时清理上次运行遗留的过期备份
+	r.mill()
+	return r, nil
+}
+
+// Write 写入前判断是否需要切割；单条日志不会被拆到两个文件
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return 0, errFileClosed
+	}
+	if r.shouldRotate(len(p)) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Rotate 立即切割当前文件
+func (r *RotatingFile) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return errFileClosed
+	}
+	return r.rotate()
+}
+
+// Close 关闭文件并等待后台清理结束
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	if r.file == nil {
+		r.mu.Unlock()
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	close(r.millCh)
+	r.mu.Unlock()
+
+	<-r.millDone
+	return err
+}
+
+func (r *RotatingFile) shouldRotate(n int) bool {
+	// 空文件不切割，避免超大单条日志产生空备份
+	if r.size == 0 {
+		return false
+	}
+	if r.opts.MaxSizeMB > 0 && r.size+int64(n) > int64(r.opts.MaxSizeMB)*1024*1024 {
+		return true
+	}
+	return r.opts.Interval > 0 && r.now().Sub(r.openedAt) >= r.opts.Interval
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+	r.file = f
+	r.size = info.Size()
+	r.openedAt = r.now()
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("close log file: %w", err)
+	}
+	r.file = nil
+	// 改名失败时重新打开原文件继续写入，避免后续日志全部丢失
+	renameErr := os.Rename(r.filename, r.backupName(r.now()))
+	if err := r.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return fmt.Errorf("rename log file: %w", renameErr)
+	}
+	r.mill()
+	return nil
+}
+
+func (r *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := r.nameParts()
+	return filepath.Join(dir, prefix+t.Format(backupTimeFormat)+ext)
+}
+
+// nameParts /var/log/app.log -> /var/log, "app-", ".log"
+func (r *RotatingFile) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(r.filename)
+	base := filepath.Base(r.filename)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// mill 通知后台协程处理备份；已有待处理的通知时直接合并
+func (r *RotatingFile) mill() {
+	select {
+	case r.millCh <- struct{}{}:
+	default:
+	}
+}
+
+func (r *RotatingFile) millLoop() {
+	defer close(r.millDone)
+	for range r.millCh {
+		// 日志系统自身的错误无法再写入日志，只能输出到 stderr
+		if err := r.millOnce(); err != nil {
+			fmt.Fprintf(os.Stderr, "log rotation: %v\n", err)
+		}
+	}
+}
+
+type logBackup struct {
+	path       string
+	time       time.Time
+	compressed bool
+}
+
+// millOnce 删除超出个数或时长的备份，并压缩剩余的未压缩备份
+func (r *RotatingFile) millOnce() error {
+	backups, err := r.listBackups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	cutoff := r.now().Add(-r.opts.MaxAge)
+	for i, b := range backups {
+		expired := r.opts.MaxAge > 0 && b.time.Before(cutoff)
+		if expired || (r.opts.MaxBackups > 0 && i >= r.opts.MaxBackups) {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if r.opts.Compress && !b.compressed {
+			if err := compressFile(b.path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// listBackups 按时间从新到旧返回备份文件
+func (r *RotatingFile) listBackups() ([]logBackup, error) {
+	dir, prefix, ext := r.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read log dir: %w", err)
+	}
+
+	var backups []logBackup
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		compressed := strings.HasSuffix(name, ".gz")
+		stem := strings.TrimSuffix(name, ".gz")
+		if !strings.HasPrefix(stem, prefix) || !strings.HasSuffix(stem, ext) {
+			continue
+		}
+		ts := strings.TrimSuffix(strings.TrimPrefix(stem, prefix), ext)
+		t, err := time.ParseInLocation(backupTimeFormat, ts, time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, logBackup{path: filepath.Join(dir, name), time: t, compressed: compressed})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].time.After(backups[j].time) })
+	return backups, nil
+}
+
+// compressFile 将 path 压缩为 path.gz 并删除原文件
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		return fmt.Errorf("compress %s: %w", path, err)
+	}
+	if err = gz.Close(); err != nil {
+		_ = dst.Close()
+		return fmt.Errorf("compress %s: %w", path, err)
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, path+".gz"); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,148 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock 后台清理协程也会读取时间，需要加锁
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func newFakeClock(t time.Time) *fakeClock { return &fakeClock{t: t} }
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func TestRotatingFile(t *testing.T) {
+	t.Run("rotates by size and keeps MaxBackups compressed", func(t *testing.T) {
+		dir := t.TempDir()
+		clock := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local))
+
+		r, err := newRotatingFile(filepath.Join(dir, "app.log"), RotateOptions{
+			MaxSizeMB:  1,
+			MaxBackups: 2,
+			Compress:   true,
+		}, clock.now)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		line := []byte(strings.Repeat("x", 600*1024) + "\n")
+		for i := 0; i < 5; i++ {
+			if _, err = r.Write(line); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			clock.advance(time.Second)
+		}
+		if err = r.Close(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		entries, _ := os.ReadDir(dir)
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		want := []string{
+			"app-20240101T000003.000.log.gz",
+			"app-20240101T000004.000.log.gz",
+			"app.log",
+		}
+		if strings.Join(names, ",") != strings.Join(want, ",") {
+			t.Fatalf("Expected %v, got %v", want, names)
+		}
+		info, _ := os.Stat(filepath.Join(dir, "app.log"))
+		if info.Size() != int64(len(line)) {
+			t.Fatalf("Expected current file to hold one line, got %d bytes", info.Size())
+		}
+	})
+
+	t.Run("rotates by interval and removes expired backups", func(t *testing.T) {
+		dir := t.TempDir()
+		clock := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local))
+
+		stale := filepath.Join(dir, "app-20231201T000000.000.log")
+		if err := os.WriteFile(stale, []byte("old\n"), 0o644); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		r, err := newRotatingFile(filepath.Join(dir, "app.log"), RotateOptions{
+			Interval: time.Hour,
+			MaxAge:   7 * 24 * time.Hour,
+		}, clock.now)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		_, _ = r.Write([]byte("first\n"))
+		clock.advance(time.Hour)
+		_, _ = r.Write([]byte("second\n"))
+		_ = r.Close()
+
+		if _, err = os.Stat(stale); !os.IsNotExist(err) {
+			t.Fatalf("Expected expired backup removed, got %v", err)
+		}
+		backup, err := os.ReadFile(filepath.Join(dir, "app-20240101T010000.000.log"))
+		if err != nil || string(backup) != "first\n" {
+			t.Fatalf("Expected rotated backup with first line, got %q (%v)", backup, err)
+		}
+		current, _ := os.ReadFile(filepath.Join(dir, "app.log"))
+		if string(current) != "second\n" {
+			t.Fatalf("Expected current file with second line, got %q", current)
+		}
+	})
+
+	t.Run("rejects writes after close", func(t *testing.T) {
+		r, err := NewRotatingFile(filepath.Join(t.TempDir(), "logs", "access.log"), RotateOptions{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		_ = r.Close()
+		if _, err = r.Write([]byte("late\n")); err == nil {
+			t.Fatal("Expected error writing to closed file")
+		}
+	})
+}
+
+func TestSetLevel(t *testing.T) {
+	previous := Level()
+	defer func() { _ = SetLevel(previous) }()
+
+	if err := SetLevel("debug"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if Level() != "debug" {
+		t.Fatalf("Expected debug, got %s", Level())
+	}
+	if err := SetLevel("verbose"); err == nil {
+		t.Fatal("Expected error for unsupported level")
+	}
+	if LevelOverridden() {
+		t.Fatal("Expected SetLevel not to mark a runtime override")
+	}
+
+	defer levelOverridden.Store(false)
+	if err := OverrideLevel("verbose"); err == nil || LevelOverridden() {
+		t.Fatal("Expected unsupported level rejected without an override")
+	}
+	if err := OverrideLevel("warn"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if Level() != "warning" || !LevelOverridden() {
+		t.Fatalf("Expected runtime override to warning, got %s", Level())
+	}
+}
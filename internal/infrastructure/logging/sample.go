@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SamplingOptions 按消息限速：每个 tick 内同一级别同一消息先输出 Initial 条，
+// 之后每 Thereafter 条输出一条。Initial 为 0 时不采样。
+type SamplingOptions struct {
+	Initial    int
+	Thereafter int
+	Tick       time.Duration
+	// MinLevel 采样的最低级别，低于该级别（如 info 访问日志）的日志不采样
+	MinLevel logrus.Level
+}
+
+// sampler 记录每条消息在当前 tick 内的次数
+type sampler struct {
+	opts   SamplingOptions
+	mu     sync.Mutex
+	counts map[sampleKey]*sampleCounter
+	now    func() time.Time
+}
+
+type sampleKey struct {
+	level   logrus.Level
+	message string
+}
+
+type sampleCounter struct {
+	resetAt time.Time
+	n       int
+}
+
+// maxSampleKeys 计数表上限，超过后整体清空，避免消息种类过多时内存增长
+const maxSampleKeys = 4096
+
+func newSampler(opts SamplingOptions) *sampler {
+	if opts.Tick <= 0 {
+		opts.Tick = time.Second
+	}
+	if opts.Thereafter <= 0 {
+		opts.Thereafter = 1
+	}
+	return &sampler{opts: opts, counts: make(map[sampleKey]*sampleCounter), now: time.Now}
+}
+
+// allow 判断该条日志是否输出
+func (s *sampler) allow(entry *logrus.Entry) bool {
+	// logrus 级别数值越小越严重
+	if s.opts.Initial <= 0 || entry.Level > s.opts.MinLevel {
+		return true
+	}
+
+	now := s.now()
+	key := sampleKey{level: entry.Level, message: entry.Message}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.counts[key]
+	if !ok || !now.Before(c.resetAt) {
+		if !ok && len(s.counts) >= maxSampleKeys {
+			s.counts = make(map[sampleKey]*sampleCounter)
+		}
+		c = &sampleCounter{resetAt: now.Add(s.opts.Tick)}
+		s.counts[key] = c
+	}
+	c.n++
+	if c.n <= s.opts.Initial {
+		return true
+	}
+	return (c.n-s.opts.Initial)%s.opts.Thereafter == 0
+}
+
+// samplingFormatter 被采样丢弃的日志格式化为空，logrus 因此不会写出任何内容
+type samplingFormatter struct {
+	inner   logrus.Formatter
+	sampler *sampler
+}
+
+func (f *samplingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if !f.sampler.allow(entry) {
+		return nil, nil
+	}
+	return f.inner.Format(entry)
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sirupsen/logrus"
+)
+
+// slogHandler 将 log/slog 记录转发到 logrus，使两者共享输出格式、级别和上下文字段
+type slogHandler struct {
+	logger *logrus.Logger
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewSlogHandler returns a slog.Handler that writes through logger.
+func NewSlogHandler(logger *logrus.Logger) slog.Handler {
+	return &slogHandler{logger: logger}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.IsLevelEnabled(toLogrusLevel(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := Fields(ctx)
+	for _, attr := range h.attrs {
+		addAttr(fields, h.groups, attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(fields, h.groups, attr)
+		return true
+	})
+
+	entry := logrus.NewEntry(h.logger).WithContext(ctx).WithFields(fields)
+	entry.Time = record.Time
+	entry.Log(toLogrusLevel(record.Level), record.Message)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(append([]string{}, h.groups...), name)
+	return &clone
+}
+
+// addAttr 分组属性展开为 group.key 形式的字段
+func addAttr(fields logrus.Fields, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			groups = append(append([]string{}, groups...), attr.Key)
+		}
+		for _, a := range attr.Value.Group() {
+			addAttr(fields, groups, a)
+		}
+		return
+	}
+
+	key := attr.Key
+	for i := len(groups) - 1; i >= 0; i-- {
+		key = groups[i] + "." + key
+	}
+	if err, ok := attr.Value.Any().(error); ok && key == "error" {
+		fields[logrus.ErrorKey] = err.Error()
+		return
+	}
+	fields[key] = attr.Value.Any()
+}
+
+func toLogrusLevel(level slog.Level) logrus.Level {
+	switch {
+	case level >= slog.LevelError:
+		return logrus.ErrorLevel
+	case level >= slog.LevelWarn:
+		return logrus.WarnLevel
+	case level >= slog.LevelInfo:
+		return logrus.InfoLevel
+	default:
+		return logrus.DebugLevel
+	}
+}
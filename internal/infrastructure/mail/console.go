@@ -0,0 +1,24 @@
+package mail
+
+import (
+	"context"
+
+	"minigo/internal/infrastructure/logging"
+)
+
+// ConsoleSender 将邮件内容写入日志，不实际发送（开发环境使用）
+type ConsoleSender struct{}
+
+// NewConsoleSender 创建日志输出的邮件发送器
+func NewConsoleSender() *ConsoleSender {
+	return &ConsoleSender{}
+}
+
+func (ConsoleSender) Send(ctx context.Context, msg Message) error {
+	logging.FromContext(ctx).WithFields(map[string]interface{}{
+		"to":      msg.To,
+		"subject": msg.Subject,
+		"body":    msg.Body,
+	}).Info("mail_console")
+	return nil
+}
@@ -0,0 +1,32 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+)
+
+// Message 一封纯文本邮件
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Sender sends email through a provider.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SendError 服务商拒绝或无法发送
+type SendError struct {
+	Provider string
+	Err      error
+}
+
+func (e *SendError) Error() string {
+	return fmt.Sprintf("mail %s: %v", e.Provider, e.Err)
+}
+
+func (e *SendError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,158 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFileSender(t *testing.T) {
+	ctx := context.Background()
+	sender, err := NewFileSender(filepath.Join(t.TempDir(), "mail", "mail.log"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, found, err := sender.LastMessage("a@example.com"); err != nil || found {
+		t.Fatalf("Expected no message before sending, got %v (%v)", found, err)
+	}
+	for _, body := range []string{"first", "second"} {
+		if err = sender.Send(ctx, Message{To: "a@example.com", Subject: "reset", Body: body}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	_ = sender.Send(ctx, Message{To: "b@example.com", Subject: "reset", Body: "other"})
+
+	msg, found, err := sender.LastMessage("a@example.com")
+	if err != nil || !found || msg.Body != "second" {
+		t.Fatalf("Expected last body second, got %+v %v (%v)", msg, found, err)
+	}
+}
+
+// fakeSMTP 最小的 SMTP 服务端，记录收到的信封和邮件内容，rcptReply 可模拟服务器拒收
+type fakeSMTP struct {
+	ln        net.Listener
+	rcptReply string
+
+	mu   sync.Mutex
+	auth string
+	from string
+	rcpt string
+	data string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	f := &fakeSMTP{ln: ln, rcptReply: "250 OK"}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		f.mu.Lock()
+		switch cmd {
+		case "EHLO":
+			reply("250-fake")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			f.auth = line
+			reply("235 Authenticated")
+		case "MAIL":
+			f.from = line
+			reply("250 OK")
+		case "RCPT":
+			f.rcpt = line
+			reply(f.rcptReply)
+		case "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			f.data = data.String()
+			reply("250 Queued")
+		case "QUIT":
+			reply("221 Bye")
+			f.mu.Unlock()
+			return
+		default:
+			reply("250 OK")
+		}
+		f.mu.Unlock()
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	fake := newFakeSMTP(t)
+	host, port, _ := net.SplitHostPort(fake.ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	sender := NewSMTPSender(host, portNum, "mailer", "s3cret", "minigo <noreply@example.com>")
+	msg := Message{To: "a@example.com", Subject: "重置密码", Body: "https://example.com/reset?token=abc"}
+
+	t.Run("authenticates and delivers the encoded message", func(t *testing.T) {
+		if err := sender.Send(context.Background(), msg); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		wantAuth := "AUTH PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00mailer\x00s3cret"))
+		if fake.auth != wantAuth {
+			t.Fatalf("Expected %q, got %q", wantAuth, fake.auth)
+		}
+		if fake.from != "MAIL FROM:<noreply@example.com>" || fake.rcpt != "RCPT TO:<a@example.com>" {
+			t.Fatalf("Expected envelope from noreply to a@example.com, got %q %q", fake.from, fake.rcpt)
+		}
+		if !strings.Contains(fake.data, "Subject: =?UTF-8?b?") {
+			t.Fatalf("Expected encoded subject, got %q", fake.data)
+		}
+		body := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+		if !strings.Contains(strings.ReplaceAll(fake.data, "\r\n", ""), body) {
+			t.Fatalf("Expected base64 body, got %q", fake.data)
+		}
+	})
+
+	t.Run("reports rejected recipients", func(t *testing.T) {
+		fake.mu.Lock()
+		fake.rcptReply = "550 No such user"
+		fake.mu.Unlock()
+		err := sender.Send(context.Background(), msg)
+		var sendErr *SendError
+		if !errors.As(err, &sendErr) || sendErr.Provider != "smtp" {
+			t.Fatalf("Expected SendError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPSender 通过 SMTP 服务器发送邮件，服务器支持时自动启用 STARTTLS；
+// username 非空时使用 PLAIN 认证（net/smtp 仅允许在 TLS 或本机连接上认证）。
+type SMTPSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string // From 头，可带显示名
+	envelope string // MAIL FROM 使用的地址
+}
+
+// NewSMTPSender 创建 SMTP 邮件发送器，from 可带显示名（如 minigo <noreply@example.com>）
+func NewSMTPSender(host string, port int, username, password, from string) *SMTPSender {
+	envelope := from
+	if addr, err := netmail.ParseAddress(from); err == nil {
+		envelope = addr.Address
+	}
+	return &SMTPSender{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+		envelope: envelope,
+	}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+	// net/smtp 不接受 context，在独立的 goroutine 中发送以便调用方取消时及时返回
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(s.addr, auth, s.envelope, []string{msg.To}, s.compose(msg))
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return &SendError{Provider: "smtp", Err: err}
+		}
+		return nil
+	case <-ctx.Done():
+		return &SendError{Provider: "smtp", Err: ctx.Err()}
+	}
+}
+
+// compose 生成 RFC 5322 邮件，主题按 RFC 2047 编码，正文使用 base64 传输
+func (s *SMTPSender) compose(msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	body := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace 所有指标的前缀
+const namespace = "minigo"
+
+// registry 独立的指标注册表，避免第三方库向默认注册表注册的指标混入
+var registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestsTotal 按路由模板统计请求数
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration 请求耗时分布
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method and route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// HTTPRequestsInFlight 正在处理的请求数
+	HTTPRequestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests currently being served by method and route template.",
+	}, []string{"method", "route"})
+
+	// DBQueryDuration bun 查询耗时分布
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Database query latency by operation.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation"})
+
+	// DBQueryErrors bun 查询错误数（不含 sql.ErrNoRows）
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "Database query errors by operation.",
+	}, []string{"operation"})
+
+	// LoginAttempts 登录结果统计
+	LoginAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "login_attempts_total",
+		Help:      "Login attempts by result (success/failure) and reason.",
+	}, []string{"result", "reason"})
+
+	// RateLimitRejections 被限流拒绝的请求数
+	RateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ratelimit",
+		Name:      "rejections_total",
+		Help:      "Requests rejected by the rate limiter by route template and policy.",
+	}, []string{"route", "policy"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		DBQueryDuration,
+		DBQueryErrors,
+		LoginAttempts,
+		RateLimitRejections,
+	)
+}
+
+// RegisterDBStats exports sql.DBStats connection pool gauges for db.
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	return registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Register adds custom collectors to the metrics registry.
+func Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves the registry in Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// RecordLogin 记录一次登录结果，reason 为空表示成功
+func RecordLogin(reason string) {
+	if reason == "" {
+		LoginAttempts.WithLabelValues("success", "").Inc()
+		return
+	}
+	LoginAttempts.WithLabelValues("failure", reason).Inc()
+}
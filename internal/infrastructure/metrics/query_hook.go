@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// QueryHook records bun query durations and errors.
+type QueryHook struct{}
+
+var _ bun.QueryHook = (*QueryHook)(nil)
+
+func NewQueryHook() *QueryHook {
+	return &QueryHook{}
+}
+
+func (h *QueryHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	operation := strings.ToLower(event.Operation())
+	DBQueryDuration.WithLabelValues(operation).Observe(time.Since(event.StartTime).Seconds())
+	// 查无记录属于正常业务结果，不计为错误
+	if event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows) {
+		DBQueryErrors.WithLabelValues(operation).Inc()
+	}
+}
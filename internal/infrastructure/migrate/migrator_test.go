@@ -0,0 +1,62 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"minigo/migrations"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("pairs up and down files by version", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"002_add_orders.up.sql":   {Data: []byte("CREATE TABLE orders ();")},
+			"002_add_orders.down.sql": {Data: []byte("DROP TABLE orders;")},
+			"001_init.up.sql":         {Data: []byte("CREATE TABLE users ();")},
+			"embed.go":                {Data: []byte("package migrations")},
+		}
+		list, err := Load(fsys)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(list) != 2 {
+			t.Fatalf("Expected 2 migrations, got %d", len(list))
+		}
+		if list[0].Version != 1 || list[1].Version != 2 {
+			t.Fatalf("Expected versions sorted, got %d, %d", list[0].Version, list[1].Version)
+		}
+		if list[1].Down == "" {
+			t.Fatalf("Expected down migration for version 2")
+		}
+	})
+
+	t.Run("rejects down without up", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"001_init.down.sql": {Data: []byte("DROP TABLE users;")},
+		}
+		if _, err := Load(fsys); err == nil {
+			t.Fatalf("Expected error for missing up file")
+		}
+	})
+
+	t.Run("rejects invalid file names", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"init.sql": {Data: []byte("SELECT 1;")},
+		}
+		if _, err := Load(fsys); err == nil {
+			t.Fatalf("Expected error for invalid file name")
+		}
+	})
+
+	t.Run("embedded migrations are valid", func(t *testing.T) {
+		list, err := Load(migrations.FS)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, m := range list {
+			if m.Down == "" {
+				t.Fatalf("Expected down file for migration %d_%s", m.Version, m.Name)
+			}
+		}
+	})
+}
@@ -21,17 +21,19 @@ type OSSService struct {
 	bucketName      string
 	region          string
 	expireSeconds   int
+	devMode         bool
 }
 
-// NewOSSService 创建OSS服务实例
-func NewOSSService() *OSSService {
+// NewOSSService 创建OSS服务实例；devMode 下文件地址返回占位图片
+func NewOSSService(cfg config.OSSConfig, devMode bool) *OSSService {
 	return &OSSService{
-		endpoint:        config.GetOSSEndpoint(),
-		accessKeyID:     config.GetOSSAccessKeyID(),
-		accessKeySecret: config.GetOSSAccessKeySecret(),
-		bucketName:      config.GetOSSBucketName(),
-		region:          config.GetOSSRegion(),
-		expireSeconds:   config.GetOSSTokenExpireSeconds(),
+		endpoint:        cfg.Endpoint,
+		accessKeyID:     cfg.AccessKeyID,
+		accessKeySecret: cfg.AccessKeySecret,
+		bucketName:      cfg.BucketName,
+		region:          cfg.Region,
+		expireSeconds:   cfg.TokenExpireSeconds,
+		devMode:         devMode,
 	}
 }
 
@@ -156,7 +158,7 @@ func (s *OSSService) GetFileURL(objectKey string) string {
 	}
 
 	// 如果是开发环境，返回随机图片URL
-	if config.IsDevEnv() {
+	if s.devMode {
 		return fmt.Sprintf("https://loremflickr.com/400/400?lock=%d", time.Now().UnixNano())
 	}
 
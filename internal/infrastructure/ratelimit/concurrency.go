@@ -0,0 +1,43 @@
+package ratelimit
+
+import "sync"
+
+// ConcurrencyLimiter 限制同一个键同时处理中的请求数。
+// 只统计本进程内的请求：处理中的请求随进程退出而结束，不需要共享存储。
+type ConcurrencyLimiter struct {
+	inFlight map[string]int
+	mutex    sync.Mutex
+}
+
+// NewConcurrencyLimiter 创建并发限制器
+func NewConcurrencyLimiter() *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{inFlight: make(map[string]int)}
+}
+
+// Acquire 处理中的请求少于 limit 时占用一个名额，返回的 release 必须在请求结束时调用且只调用一次
+func (l *ConcurrencyLimiter) Acquire(key string, limit int) (release func(), res Result) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	res.Limit = limit
+	n := l.inFlight[key]
+	if n >= limit {
+		return nil, res
+	}
+	l.inFlight[key] = n + 1
+	res.Allowed = true
+	res.Remaining = limit - n - 1
+
+	var once sync.Once
+	return func() { once.Do(func() { l.release(key) }) }, res
+}
+
+func (l *ConcurrencyLimiter) release(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.inFlight[key] <= 1 {
+		delete(l.inFlight, key)
+		return
+	}
+	l.inFlight[key]--
+}
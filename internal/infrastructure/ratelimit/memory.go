@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore 基于进程内存的限流存储（单实例部署或测试使用），支持全部算法
+type MemoryStore struct {
+	entries map[string]memoryEntry
+	mutex   sync.Mutex
+	now     func() time.Time
+}
+
+type memoryEntry struct {
+	bucket
+	log       []time.Time // 滑动窗口内的请求时间
+	expiresAt time.Time
+}
+
+// NewMemoryStore 创建内存限流存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if err := limit.Validate(); err != nil {
+		return Result{}, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	entry, exists := s.entries[key]
+	var res Result
+	if limit.algorithm() == SlidingWindow {
+		entry.log, res = limit.slide(entry.log, now)
+	} else {
+		entry.bucket, res = limit.take(entry.bucket, exists, now)
+	}
+	entry.expiresAt = now.Add(limit.ttl())
+	s.entries[key] = entry
+	return res, nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// PurgeExpired 删除已恢复到初始状态的条目
+func (s *MemoryStore) PurgeExpired(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Capacity: 2, Rate: 100 * time.Millisecond}
+
+	t.Run("drains the bucket then rejects with retry-after", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if res, _ := store.Allow(ctx, "k", limit); !res.Allowed || res.Remaining != 1-i {
+				t.Fatalf("Expected request %d allowed with %d remaining, got %+v", i, 1-i, res)
+			}
+		}
+		res, _ := store.Allow(ctx, "k", limit)
+		if res.Allowed || res.RetryAfter != 100*time.Millisecond || res.ResetAfter != 200*time.Millisecond {
+			t.Fatalf("Expected rejection with 100ms retry and 200ms reset, got %+v", res)
+		}
+	})
+
+	t.Run("refills fractional tokens without losing time", func(t *testing.T) {
+		// 两次 60ms 的间隔累计 1.2 个令牌，整数补充会丢掉两次的零头
+		now = now.Add(60 * time.Millisecond)
+		if res, _ := store.Allow(ctx, "k", limit); res.Allowed {
+			t.Fatalf("Expected rejection after 0.6 tokens, got %+v", res)
+		}
+		now = now.Add(60 * time.Millisecond)
+		if res, _ := store.Allow(ctx, "k", limit); !res.Allowed {
+			t.Fatalf("Expected request allowed after 1.2 tokens, got %+v", res)
+		}
+	})
+
+	t.Run("purges full buckets and honours reset", func(t *testing.T) {
+		_ = store.Reset(ctx, "k")
+		if res, _ := store.Allow(ctx, "k", limit); res.Remaining != 1 {
+			t.Fatalf("Expected full bucket after reset, got %+v", res)
+		}
+		now = now.Add(limit.ttl() + time.Millisecond)
+		_ = store.PurgeExpired(ctx)
+		if len(store.entries) != 0 {
+			t.Fatalf("Expected entries purged, got %d", len(store.entries))
+		}
+	})
+}
+
+func TestAlgorithms(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	t.Run("gcra matches token bucket decisions", func(t *testing.T) {
+		bucket := Limit{Capacity: 3, Rate: time.Second}
+		gcra := Limit{Algorithm: GCRA, Capacity: 3, Rate: time.Second}
+		steps := []time.Duration{0, 0, 0, 0, 500 * time.Millisecond, 500 * time.Millisecond, 0, 3 * time.Second, 0}
+		for i, step := range steps {
+			now = now.Add(step)
+			a, _ := store.Allow(ctx, "bucket", bucket)
+			b, _ := store.Allow(ctx, "gcra", gcra)
+			if a.Allowed != b.Allowed || a.Remaining != b.Remaining || a.RetryAfter != b.RetryAfter || a.ResetAfter != b.ResetAfter {
+				t.Fatalf("Expected step %d to match, got token bucket %+v and gcra %+v", i, a, b)
+			}
+		}
+	})
+
+	t.Run("sliding window has no boundary burst", func(t *testing.T) {
+		limit := Limit{Algorithm: SlidingWindow, Capacity: 2, Window: time.Minute}
+		for i := 0; i < 2; i++ {
+			if res, _ := store.Allow(ctx, "window", limit); !res.Allowed {
+				t.Fatalf("Expected request %d allowed, got %+v", i, res)
+			}
+			now = now.Add(20 * time.Second)
+		}
+		// 第一个请求 40s 前，窗口内仍有 2 个请求
+		res, _ := store.Allow(ctx, "window", limit)
+		if res.Allowed || res.RetryAfter != 20*time.Second {
+			t.Fatalf("Expected rejection with 20s retry, got %+v", res)
+		}
+		now = now.Add(20 * time.Second)
+		if res, _ = store.Allow(ctx, "window", limit); !res.Allowed || res.Remaining != 0 {
+			t.Fatalf("Expected request allowed once the first expires, got %+v", res)
+		}
+	})
+
+	t.Run("rejects incomplete limits", func(t *testing.T) {
+		if _, err := store.Allow(ctx, "bad", Limit{Algorithm: SlidingWindow, Capacity: 1}); err == nil {
+			t.Fatal("Expected error for sliding window without window")
+		}
+	})
+}
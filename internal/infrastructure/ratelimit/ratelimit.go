@@ -0,0 +1,190 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"minigo/internal/infrastructure/logging"
+)
+
+// Algorithm 限流算法
+type Algorithm string
+
+const (
+	// TokenBucket 令牌桶：桶容量 Capacity，每隔 Rate 补充一个令牌（按时间连续补充）
+	TokenBucket Algorithm = "token_bucket"
+	// GCRA 通用信元速率算法：与令牌桶等价，状态只有一个理论到达时间，写入量更小
+	GCRA Algorithm = "gcra"
+	// SlidingWindow 滑动窗口日志：任意 Window 长度的时间段内最多 Capacity 个请求，没有固定窗口边界的突发
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+// ErrUnsupportedAlgorithm 存储不支持所请求的限流算法
+var ErrUnsupportedAlgorithm = errors.New("unsupported rate limit algorithm")
+
+// Limit 限流参数。Algorithm 为空时使用 TokenBucket
+type Limit struct {
+	Algorithm Algorithm
+	Capacity  int           // 令牌桶/GCRA 的突发容量，滑动窗口内的最大请求数
+	Rate      time.Duration // 令牌桶/GCRA 每生成一个令牌的间隔
+	Window    time.Duration // 滑动窗口长度
+}
+
+// Result 一次限流判定的结果，用于生成 RateLimit-* 响应头
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int           // 本次判定后剩余的可用次数
+	ResetAfter time.Duration // 恢复到完全可用所需时间
+	RetryAfter time.Duration // 被拒绝时，距下一次可用的时间
+}
+
+// RateLimiter is a rate limit store shared by every request handled by the
+// process (memory) or by every replica (postgres, redis).
+// 限流参数随每次调用传入，参数变化（配置热更新）对已有的状态立即生效。
+// 同一个键只应使用一种算法，不同策略应使用不同的键前缀。
+type RateLimiter interface {
+	// Allow consumes one request from the quota identified by key.
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+
+	// Reset clears the state identified by key.
+	Reset(ctx context.Context, key string) error
+}
+
+// RunCleanup 定期清理过期的限流状态，ctx 取消时返回；存储自行过期（如 Redis）时直接返回
+func RunCleanup(ctx context.Context, store RateLimiter, interval time.Duration) {
+	purger, ok := store.(interface {
+		PurgeExpired(ctx context.Context) error
+	})
+	if !ok {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := purger.PurgeExpired(ctx); err != nil {
+				logging.L().WithError(err).Warn("rate_limit_purge_failed")
+			}
+		}
+	}
+}
+
+// Validate 检查限流参数是否完整
+func (l Limit) Validate() error {
+	if l.Capacity <= 0 {
+		return errors.New("capacity must be positive")
+	}
+	switch l.algorithm() {
+	case TokenBucket, GCRA:
+		if l.Rate <= 0 {
+			return errors.New("rate must be positive")
+		}
+	case SlidingWindow:
+		if l.Window <= 0 {
+			return errors.New("window must be positive")
+		}
+	default:
+		return fmt.Errorf("%w %q", ErrUnsupportedAlgorithm, l.Algorithm)
+	}
+	return nil
+}
+
+func (l Limit) algorithm() Algorithm {
+	if l.Algorithm == "" {
+		return TokenBucket
+	}
+	return l.Algorithm
+}
+
+// bucket 令牌桶/GCRA 状态：令牌桶使用 tokens 和 updated，GCRA 的理论到达时间保存在 updated 中
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take 在 now 时刻消耗一次配额，exists 为 false 表示状态尚未创建
+func (l Limit) take(b bucket, exists bool, now time.Time) (bucket, Result) {
+	if l.algorithm() == GCRA {
+		return l.takeGCRA(b, exists, now)
+	}
+
+	capacity := float64(l.Capacity)
+	tokens := capacity
+	if exists {
+		elapsed := now.Sub(b.updated)
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		tokens = math.Min(capacity, b.tokens+float64(elapsed)/float64(l.Rate))
+	}
+
+	res := Result{Limit: l.Capacity}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration((1 - tokens) * float64(l.Rate))
+	}
+	res.Remaining = int(tokens)
+	res.ResetAfter = time.Duration((capacity - tokens) * float64(l.Rate))
+	return bucket{tokens: tokens, updated: now}, res
+}
+
+// takeGCRA 每个请求把理论到达时间（TAT）推后 Rate，TAT 超前当前时间不超过 Capacity 个间隔即放行
+func (l Limit) takeGCRA(b bucket, exists bool, now time.Time) (bucket, Result) {
+	tat := now
+	if exists && b.updated.After(now) {
+		tat = b.updated
+	}
+	burst := time.Duration(l.Capacity) * l.Rate
+	res := Result{Limit: l.Capacity}
+
+	next := tat.Add(l.Rate)
+	if allowAt := next.Add(-burst); now.Before(allowAt) {
+		res.RetryAfter = allowAt.Sub(now)
+		res.ResetAfter = tat.Sub(now)
+		res.Remaining = int((burst - tat.Sub(now)) / l.Rate)
+		return bucket{updated: tat}, res
+	}
+	res.Allowed = true
+	res.ResetAfter = next.Sub(now)
+	res.Remaining = int((burst - next.Sub(now)) / l.Rate)
+	return bucket{updated: next}, res
+}
+
+// slide 滑动窗口日志：丢弃窗口外的请求时间，窗口内未满则记录本次请求
+func (l Limit) slide(log []time.Time, now time.Time) ([]time.Time, Result) {
+	start := now.Add(-l.Window)
+	kept := log[:0]
+	for _, t := range log {
+		if t.After(start) {
+			kept = append(kept, t)
+		}
+	}
+
+	res := Result{Limit: l.Capacity}
+	if len(kept) < l.Capacity {
+		kept = append(kept, now)
+		res.Allowed = true
+	} else {
+		res.RetryAfter = kept[len(kept)-l.Capacity].Add(l.Window).Sub(now)
+	}
+	res.Remaining = max(l.Capacity-len(kept), 0)
+	res.ResetAfter = kept[len(kept)-1].Add(l.Window).Sub(now)
+	return kept, res
+}
+
+// ttl 超过该时间未访问的状态与新建状态等价，可以删除
+func (l Limit) ttl() time.Duration {
+	if l.algorithm() == SlidingWindow {
+		return l.Window + time.Second
+	}
+	return time.Duration(l.Capacity)*l.Rate + time.Second
+}
@@ -0,0 +1,357 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redisScripts 与 Limit.take / Limit.slide 相同的算法，在 Redis 中原子执行，时间取 Redis 服务器时钟。
+// KEYS[1] 状态键；ARGV[1] 容量，ARGV[2] 令牌间隔或窗口长度（微秒），ARGV[3] 过期时间（毫秒）。
+// 返回 {是否放行, 剩余次数, 重试等待（微秒）, 完全恢复所需时间（微秒）}。
+// 微秒时间戳超过 Lua tostring 的有效位数，写入时需用 %d 格式化。
+var redisScripts = map[Algorithm]*redisScript{
+	TokenBucket: newRedisScript(`
+redis.replicate_commands()
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = capacity
+if state[1] then
+  local elapsed = math.max(0, now - tonumber(state[2]))
+  tokens = math.min(capacity, tonumber(state[1]) + elapsed / rate)
+end
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = math.ceil((1 - tokens) * rate)
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', string.format('%d', now))
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return {allowed, math.floor(tokens), retry, math.ceil((capacity - tokens) * rate)}
+`),
+	GCRA: newRedisScript(`
+redis.replicate_commands()
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local tat = math.max(tonumber(redis.call('GET', KEYS[1]) or now), now)
+local burst = capacity * rate
+local next = tat + rate
+local allow_at = next - burst
+if now < allow_at then
+  return {0, math.floor((burst - (tat - now)) / rate), allow_at - now, tat - now}
+end
+redis.call('SET', KEYS[1], string.format('%d', next), 'PX', ARGV[3])
+return {1, math.floor((burst - (next - now)) / rate), 0, next - now}
+`),
+	SlidingWindow: newRedisScript(`
+redis.replicate_commands()
+local capacity = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', string.format('%d', now - window))
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+local retry = 0
+if count < capacity then
+  local score = string.format('%d', now)
+  redis.call('ZADD', KEYS[1], score, score .. '-' .. count)
+  redis.call('PEXPIRE', KEYS[1], ARGV[3])
+  count = count + 1
+  allowed = 1
+else
+  local oldest = redis.call('ZRANGE', KEYS[1], count - capacity, count - capacity, 'WITHSCORES')
+  retry = tonumber(oldest[2]) + window - now
+end
+local newest = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+return {allowed, math.max(capacity - count, 0), retry, tonumber(newest[2]) + window - now}
+`),
+}
+
+type redisScript struct {
+	src string
+	sha string
+}
+
+func newRedisScript(src string) *redisScript {
+	sum := sha1.Sum([]byte(src))
+	return &redisScript{src: src, sha: hex.EncodeToString(sum[:])}
+}
+
+const (
+	redisKeyPrefix = "ratelimit:"
+	redisPoolSize  = 16
+	redisTimeout   = time.Second
+)
+
+// redisError Redis 返回的错误应答，连接本身仍然可用
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// RedisStore 基于 Redis 协议（RESP）的限流存储（多实例共享），兼容 Redis、Valkey、KeyDB 等。
+// 限流脚本通过 EVALSHA 执行，服务端未缓存脚本时回退到 EVAL；支持全部算法。
+type RedisStore struct {
+	addr     string
+	username string
+	password string
+	db       int
+	tls      *tls.Config
+	pool     chan *redisConn
+}
+
+// NewRedisStore 按 redis://[user:password@]host:port[/db] 创建存储，rediss:// 使用 TLS
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+	s := &RedisStore{addr: u.Host, pool: make(chan *redisConn, redisPoolSize)}
+	switch u.Scheme {
+	case "redis":
+	case "rediss":
+		s.tls = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+	default:
+		return nil, fmt.Errorf("invalid redis url: unsupported scheme %q", u.Scheme)
+	}
+	if u.Port() == "" {
+		s.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		s.username = u.User.Username()
+		s.password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if s.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid redis url: bad database %q", db)
+		}
+	}
+	return s, nil
+}
+
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if err := limit.Validate(); err != nil {
+		return Result{}, err
+	}
+	script := redisScripts[limit.algorithm()]
+	interval := limit.Rate
+	if limit.algorithm() == SlidingWindow {
+		interval = limit.Window
+	}
+	args := []string{
+		"1", redisKeyPrefix + key,
+		strconv.Itoa(limit.Capacity),
+		strconv.FormatInt(max(interval.Microseconds(), 1), 10),
+		strconv.FormatInt(limit.ttl().Milliseconds(), 10),
+	}
+	reply, err := s.do(ctx, append([]string{"EVALSHA", script.sha}, args...)...)
+	var rerr redisError
+	if errors.As(err, &rerr) && strings.HasPrefix(string(rerr), "NOSCRIPT") {
+		reply, err = s.do(ctx, append([]string{"EVAL", script.src}, args...)...)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 4 {
+		return Result{}, fmt.Errorf("redis: unexpected script reply %v", reply)
+	}
+	var n [4]int64
+	for i, v := range values {
+		if n[i], ok = v.(int64); !ok {
+			return Result{}, fmt.Errorf("redis: unexpected script reply %v", reply)
+		}
+	}
+	return Result{
+		Allowed:    n[0] == 1,
+		Limit:      limit.Capacity,
+		Remaining:  int(n[1]),
+		RetryAfter: time.Duration(n[2]) * time.Microsecond,
+		ResetAfter: time.Duration(n[3]) * time.Microsecond,
+	}, nil
+}
+
+func (s *RedisStore) Reset(ctx context.Context, key string) error {
+	_, err := s.do(ctx, "DEL", redisKeyPrefix+key)
+	return err
+}
+
+// Close 关闭连接池中的空闲连接
+func (s *RedisStore) Close() error {
+	for {
+		select {
+		case c := <-s.pool:
+			_ = c.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do 执行一条命令。网络或协议错误时丢弃连接，Redis 错误应答不影响连接复用
+func (s *RedisStore) do(ctx context.Context, args ...string) (interface{}, error) {
+	c, err := s.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := c.do(ctx, args...)
+	var rerr redisError
+	if err != nil && !errors.As(err, &rerr) {
+		_ = c.conn.Close()
+		return nil, err
+	}
+	s.put(c)
+	return reply, err
+}
+
+func (s *RedisStore) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-s.pool:
+		return c, nil
+	default:
+	}
+
+	dialer := &net.Dialer{Timeout: redisTimeout}
+	var conn net.Conn
+	var err error
+	if s.tls != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: s.tls}).DialContext(ctx, "tcp", s.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", s.addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+
+	if s.password != "" {
+		auth := []string{"AUTH", s.password}
+		if s.username != "" {
+			auth = []string{"AUTH", s.username, s.password}
+		}
+		if _, err = c.do(ctx, auth...); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis auth: %w", err)
+		}
+	}
+	if s.db != 0 {
+		if _, err = c.do(ctx, "SELECT", strconv.Itoa(s.db)); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis select: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func (s *RedisStore) put(c *redisConn) {
+	select {
+	case s.pool <- c:
+	default:
+		_ = c.conn.Close()
+	}
+}
+
+// redisConn 单个 RESP 连接，不支持并发使用
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+func (c *redisConn) do(ctx context.Context, args ...string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisTimeout)
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(c.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	return readReply(c.r)
+}
+
+// readReply 解析一个 RESP2 应答：简单字符串、错误、整数、批量字符串和数组
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad integer reply %q", line)
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad array length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = readReply(r); err != nil {
+				var rerr redisError
+				if errors.As(err, &rerr) {
+					// 数组中的错误元素按值保留，保证应答被完整读取
+					values[i] = rerr
+					continue
+				}
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("redis: unknown reply type %q", line)
+}
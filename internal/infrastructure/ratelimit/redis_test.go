@@ -0,0 +1,214 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis 实现限流所需命令的本地 RESP 服务端，限流脚本由 Limit.take / Limit.slide 代替执行
+type fakeRedis struct {
+	ln       net.Listener
+	password string
+
+	mu       sync.Mutex
+	now      time.Time
+	buckets  map[string]bucket
+	logs     map[string][]time.Time
+	scripts  map[string]bool
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	f := &fakeRedis{
+		ln:       ln,
+		password: password,
+		now:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		buckets:  make(map[string]bucket),
+		logs:     make(map[string][]time.Time),
+		scripts:  make(map[string]bool),
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := f.password == ""
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, v := range reply.([]interface{}) {
+			args = append(args, v.(string))
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, strings.ToUpper(args[0]))
+		var out string
+		if !authed && args[0] != "AUTH" {
+			out = "-NOAUTH Authentication required.\r\n"
+		} else {
+			out = f.exec(args, &authed)
+		}
+		f.mu.Unlock()
+		if _, err = conn.Write([]byte(out)); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) exec(args []string, authed *bool) string {
+	switch strings.ToUpper(args[0]) {
+	case "AUTH":
+		if args[len(args)-1] != f.password {
+			return "-WRONGPASS invalid password\r\n"
+		}
+		*authed = true
+		return "+OK\r\n"
+	case "SELECT":
+		return "+OK\r\n"
+	case "DEL":
+		delete(f.buckets, args[1])
+		delete(f.logs, args[1])
+		return ":1\r\n"
+	case "EVALSHA":
+		if !f.scripts[args[1]] {
+			return "-NOSCRIPT No matching script. Please use EVAL.\r\n"
+		}
+		return f.runScript(args[1], args[3:])
+	case "EVAL":
+		sum := newRedisScript(args[1]).sha
+		f.scripts[sum] = true
+		return f.runScript(sum, args[3:])
+	}
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+}
+
+// runScript args: key, capacity, rate or window (µs), ttl (ms)
+func (f *fakeRedis) runScript(sha string, args []string) string {
+	var algorithm Algorithm
+	for a, script := range redisScripts {
+		if script.sha == sha {
+			algorithm = a
+		}
+	}
+	if algorithm == "" {
+		return "-ERR unknown script\r\n"
+	}
+	capacity, _ := strconv.Atoi(args[1])
+	interval, _ := strconv.ParseInt(args[2], 10, 64)
+	limit := Limit{Algorithm: algorithm, Capacity: capacity}
+
+	var res Result
+	if algorithm == SlidingWindow {
+		limit.Window = time.Duration(interval) * time.Microsecond
+		f.logs[args[0]], res = limit.slide(f.logs[args[0]], f.now)
+	} else {
+		limit.Rate = time.Duration(interval) * time.Microsecond
+		b, exists := f.buckets[args[0]]
+		f.buckets[args[0]], res = limit.take(b, exists, f.now)
+	}
+	allowed := 0
+	if res.Allowed {
+		allowed = 1
+	}
+	return fmt.Sprintf("*4\r\n:%d\r\n:%d\r\n:%d\r\n:%d\r\n",
+		allowed, res.Remaining, res.RetryAfter.Microseconds(), res.ResetAfter.Microseconds())
+}
+
+func (f *fakeRedis) advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeRedis(t, "s3cret")
+	store, err := NewRedisStore("redis://:s3cret@" + fake.ln.Addr().String() + "/2")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer store.Close()
+	limit := Limit{Capacity: 2, Rate: time.Second}
+
+	t.Run("falls back to EVAL once then reuses the cached script", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			res, err := store.Allow(ctx, "ip:1", limit)
+			if err != nil || !res.Allowed || res.Remaining != 1-i {
+				t.Fatalf("Expected request %d allowed, got %+v (%v)", i, res, err)
+			}
+		}
+		got := strings.Join(fake.commands, ",")
+		if got != "AUTH,SELECT,EVALSHA,EVAL,EVALSHA" {
+			t.Fatalf("Expected AUTH,SELECT,EVALSHA,EVAL,EVALSHA on one connection, got %s", got)
+		}
+		if _, ok := fake.buckets[redisKeyPrefix+"ip:1"]; !ok {
+			t.Fatalf("Expected prefixed key, got %v", fake.buckets)
+		}
+	})
+
+	t.Run("decodes rejection timings", func(t *testing.T) {
+		res, err := store.Allow(ctx, "ip:1", limit)
+		if err != nil || res.Allowed || res.RetryAfter != time.Second || res.ResetAfter != 2*time.Second {
+			t.Fatalf("Expected rejection with 1s retry and 2s reset, got %+v (%v)", res, err)
+		}
+		fake.advance(time.Second)
+		if res, _ = store.Allow(ctx, "ip:1", limit); !res.Allowed {
+			t.Fatalf("Expected request allowed after refill, got %+v", res)
+		}
+	})
+
+	t.Run("reset deletes the bucket", func(t *testing.T) {
+		if err := store.Reset(ctx, "ip:1"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res, _ := store.Allow(ctx, "ip:1", limit); res.Remaining != 1 {
+			t.Fatalf("Expected full bucket after reset, got %+v", res)
+		}
+	})
+
+	t.Run("selects the script by algorithm", func(t *testing.T) {
+		window := Limit{Algorithm: SlidingWindow, Capacity: 1, Window: time.Minute}
+		if res, err := store.Allow(ctx, "login:1", window); err != nil || !res.Allowed {
+			t.Fatalf("Expected first request allowed, got %+v (%v)", res, err)
+		}
+		res, err := store.Allow(ctx, "login:1", window)
+		if err != nil || res.Allowed || res.RetryAfter != time.Minute {
+			t.Fatalf("Expected rejection with 1m retry, got %+v (%v)", res, err)
+		}
+		if len(fake.logs[redisKeyPrefix+"login:1"]) != 1 {
+			t.Fatalf("Expected one logged request, got %v", fake.logs)
+		}
+	})
+
+	t.Run("reports auth failures", func(t *testing.T) {
+		bad, _ := NewRedisStore("redis://:wrong@" + fake.ln.Addr().String())
+		if _, err := bad.Allow(ctx, "ip:1", limit); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+			t.Fatalf("Expected WRONGPASS error, got %v", err)
+		}
+	})
+}
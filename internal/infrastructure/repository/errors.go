@@ -1,22 +1,28 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"log/slog"
 	"time"
 
 	apperrors "minigo/internal/domain/errors"
+	"minigo/internal/infrastructure/logging"
+
+	"github.com/uptrace/bun/driver/pgdriver"
 )
 
+// pgUniqueViolation PostgreSQL 唯一约束冲突错误码
+const pgUniqueViolation = "23505"
+
 // CheckUpdateResult checks the result of an update operation and returns appropriate error
-func CheckUpdateResult(result sql.Result, err error) error {
+func CheckUpdateResult(ctx context.Context, result sql.Result, err error) error {
 	if err != nil {
-		return ConvertError(err)
+		return ConvertError(ctx, err)
 	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return ConvertError(err)
+		return ConvertError(ctx, err)
 	}
 	if rowsAffected == 0 {
 		return apperrors.ErrResourceNotFound
@@ -25,13 +31,13 @@ func CheckUpdateResult(result sql.Result, err error) error {
 }
 
 // CheckDeleteResult checks the result of a delete operation and returns appropriate error
-func CheckDeleteResult(result sql.Result, err error) error {
+func CheckDeleteResult(ctx context.Context, result sql.Result, err error) error {
 	if err != nil {
-		return ConvertError(err)
+		return ConvertError(ctx, err)
 	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return ConvertError(err)
+		return ConvertError(ctx, err)
 	}
 	if rowsAffected == 0 {
 		return apperrors.ErrResourceNotFound
@@ -44,8 +50,8 @@ func Now() time.Time {
 	return time.Now()
 }
 
-// ConvertError 转换数据库错误为应用错误
-func ConvertError(err error) error {
+// ConvertError 转换数据库错误为应用错误，未识别的错误带请求上下文记录日志
+func ConvertError(ctx context.Context, err error) error {
 	if err == nil {
 		return nil
 	}
@@ -55,18 +61,38 @@ func ConvertError(err error) error {
 		return apperrors.ErrResourceNotFound
 	}
 
-	slog.Error("数据库错误", "error", err)
+	// 唯一约束冲突转换为业务错误，Details 中保留约束名
+	if constraint, ok := UniqueViolationConstraint(err); ok {
+		return &apperrors.AppError{
+			Type:    apperrors.ErrDuplicateResource.Type,
+			Code:    apperrors.ErrDuplicateResource.Code,
+			Message: apperrors.ErrDuplicateResource.Message,
+			Details: constraint,
+			Cause:   err,
+		}
+	}
+
+	logging.FromContext(ctx).WithError(err).Error("db_error")
 
 	// 其他数据库错误转换为系统错误
 	return apperrors.WrapSystemError(err, "DB_001", "数据库操作失败")
 }
 
 // ConvertQueryError 转换查询错误
-func ConvertQueryError(err error) error {
-	return ConvertError(err)
+func ConvertQueryError(ctx context.Context, err error) error {
+	return ConvertError(ctx, err)
 }
 
 // ConvertExecError 转换执行错误
-func ConvertExecError(err error) error {
-	return ConvertError(err)
+func ConvertExecError(ctx context.Context, err error) error {
+	return ConvertError(ctx, err)
+}
+
+// UniqueViolationConstraint 判断是否为唯一约束冲突，并返回冲突的约束（索引）名
+func UniqueViolationConstraint(err error) (string, bool) {
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) && pgErr.Field('C') == pgUniqueViolation {
+		return pgErr.Field('n'), true
+	}
+	return "", false
 }
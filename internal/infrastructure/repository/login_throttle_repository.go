@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"minigo/internal/domain/entity"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/dbctx"
+
+	"github.com/uptrace/bun"
+)
+
+// BunLoginThrottleRepository implements LoginThrottleRepository using Bun ORM
+type BunLoginThrottleRepository struct {
+	DB *bun.DB
+}
+
+// NewBunLoginThrottleRepository creates a new BunLoginThrottleRepository
+func NewBunLoginThrottleRepository(db *bun.DB) repository.LoginThrottleRepository {
+	return &BunLoginThrottleRepository{DB: db}
+}
+
+func (r *BunLoginThrottleRepository) ListBySubjects(ctx context.Context, subjects []string) ([]*entity.LoginThrottle, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var throttles []*entity.LoginThrottle
+	err := db.NewSelect().
+		Model(&throttles).
+		Where("lt.subject IN (?)", bun.In(subjects)).
+		Scan(ctx)
+	if err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return throttles, nil
+}
+
+func (r *BunLoginThrottleRepository) RecordFailure(ctx context.Context, subject string, at, resetBefore time.Time) (*entity.LoginThrottle, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	throttle := &entity.LoginThrottle{Subject: subject, Failures: 1, LastFailedAt: at}
+	// 单条语句完成计数，并发失败不会丢失
+	_, err := db.NewInsert().
+		Model(throttle).
+		On("CONFLICT (subject) DO UPDATE").
+		Set("failures = CASE WHEN lt.last_failed_at < ? THEN 1 ELSE lt.failures + 1 END", resetBefore).
+		Set("last_failed_at = EXCLUDED.last_failed_at").
+		Returning("*").
+		Exec(ctx)
+	if err != nil {
+		return nil, ConvertExecError(ctx, err)
+	}
+	return throttle, nil
+}
+
+func (r *BunLoginThrottleRepository) Lock(ctx context.Context, subject string, until time.Time) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	result, err := db.NewUpdate().
+		Model((*entity.LoginThrottle)(nil)).
+		Set("failures = 0").
+		Set("locked_until = ?", until).
+		Where("subject = ?", subject).
+		Exec(ctx)
+	return CheckUpdateResult(ctx, result, err)
+}
+
+func (r *BunLoginThrottleRepository) Delete(ctx context.Context, subjects ...string) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewDelete().
+		Model((*entity.LoginThrottle)(nil)).
+		Where("subject IN (?)", bun.In(subjects)).
+		Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunLoginThrottleRepository) PurgeStale(ctx context.Context, before time.Time) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewDelete().
+		Model((*entity.LoginThrottle)(nil)).
+		Where("last_failed_at < ?", before).
+		Where("(locked_until IS NULL OR locked_until < ?)", before).
+		Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunLoginThrottleRepository) CreateEvent(ctx context.Context, event *entity.LoginLockoutEvent) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewInsert().Model(event).Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunLoginThrottleRepository) ListEvents(ctx context.Context, filter repository.LockoutEventFilter) ([]*entity.LoginLockoutEvent, int, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var events []*entity.LoginLockoutEvent
+	query := db.NewSelect().Model(&events)
+	if filter.Subject != "" {
+		query = query.Where("lle.subject = ?", filter.Subject)
+	}
+	total, err := query.
+		Order("lle.created_at DESC").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, ConvertQueryError(ctx, err)
+	}
+	return events, total, nil
+}
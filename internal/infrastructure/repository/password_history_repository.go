@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"minigo/internal/domain/entity"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/dbctx"
+
+	"github.com/uptrace/bun"
+)
+
+// BunPasswordHistoryRepository implements PasswordHistoryRepository using Bun ORM
+type BunPasswordHistoryRepository struct {
+	DB *bun.DB
+}
+
+// NewBunPasswordHistoryRepository creates a new BunPasswordHistoryRepository
+func NewBunPasswordHistoryRepository(db *bun.DB) repository.PasswordHistoryRepository {
+	return &BunPasswordHistoryRepository{DB: db}
+}
+
+func (r *BunPasswordHistoryRepository) Create(ctx context.Context, history *entity.PasswordHistory) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewInsert().Model(history).Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunPasswordHistoryRepository) ListRecent(ctx context.Context, userID int64, limit int) ([]*entity.PasswordHistory, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var history []*entity.PasswordHistory
+	err := db.NewSelect().
+		Model(&history).
+		Where("ph.user_id = ?", userID).
+		Order("ph.created_at DESC", "ph.id DESC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return history, nil
+}
+
+func (r *BunPasswordHistoryRepository) Prune(ctx context.Context, userID int64, keep int) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	recent := db.NewSelect().
+		Model((*entity.PasswordHistory)(nil)).
+		Column("ph.id").
+		Where("ph.user_id = ?", userID).
+		Order("ph.created_at DESC", "ph.id DESC").
+		Limit(keep)
+	_, err := db.NewDelete().
+		Model((*entity.PasswordHistory)(nil)).
+		Where("user_id = ?", userID).
+		Where("id NOT IN (?)", recent).
+		Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"minigo/internal/domain/entity"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/dbctx"
+
+	"github.com/uptrace/bun"
+)
+
+// BunPasswordResetTokenRepository implements PasswordResetTokenRepository using Bun ORM
+type BunPasswordResetTokenRepository struct {
+	DB *bun.DB
+}
+
+// NewBunPasswordResetTokenRepository creates a new BunPasswordResetTokenRepository
+func NewBunPasswordResetTokenRepository(db *bun.DB) repository.PasswordResetTokenRepository {
+	return &BunPasswordResetTokenRepository{DB: db}
+}
+
+func (r *BunPasswordResetTokenRepository) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewInsert().Model(token).Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunPasswordResetTokenRepository) GetLatestByUser(ctx context.Context, userID int64) (*entity.PasswordResetToken, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var token entity.PasswordResetToken
+	err := db.NewSelect().
+		Model(&token).
+		Where("prt.user_id = ?", userID).
+		Order("prt.created_at DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return &token, nil
+}
+
+func (r *BunPasswordResetTokenRepository) GetByHashForUpdate(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var token entity.PasswordResetToken
+	err := db.NewSelect().
+		Model(&token).
+		Where("prt.token_hash = ?", tokenHash).
+		For("UPDATE").
+		Scan(ctx)
+	if err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return &token, nil
+}
+
+func (r *BunPasswordResetTokenRepository) InvalidateByUser(ctx context.Context, userID int64, usedAt time.Time) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewUpdate().
+		Model((*entity.PasswordResetToken)(nil)).
+		Set("used_at = ?", usedAt).
+		Where("user_id = ?", userID).
+		Where("used_at IS NULL").
+		Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunPasswordResetTokenRepository) PurgeExpired(ctx context.Context, before time.Time) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewDelete().
+		Model((*entity.PasswordResetToken)(nil)).
+		Where("expires_at < ?", before).
+		Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
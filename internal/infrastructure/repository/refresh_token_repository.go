@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"minigo/internal/domain/entity"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/dbctx"
+
+	"github.com/uptrace/bun"
+)
+
+// BunRefreshTokenRepository implements RefreshTokenRepository using Bun ORM
+type BunRefreshTokenRepository struct {
+	DB *bun.DB
+}
+
+// NewBunRefreshTokenRepository creates a new BunRefreshTokenRepository
+func NewBunRefreshTokenRepository(db *bun.DB) repository.RefreshTokenRepository {
+	return &BunRefreshTokenRepository{DB: db}
+}
+
+func (r *BunRefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewInsert().Model(token).Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunRefreshTokenRepository) GetByHashForUpdate(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var token entity.RefreshToken
+	err := db.NewSelect().
+		Model(&token).
+		Where("token_hash = ?", tokenHash).
+		For("UPDATE").
+		Scan(ctx)
+	if err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return &token, nil
+}
+
+func (r *BunRefreshTokenRepository) MarkUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	result, err := db.NewUpdate().
+		Model((*entity.RefreshToken)(nil)).
+		Set("used_at = ?", usedAt).
+		Where("id = ?", id).
+		Where("used_at IS NULL").
+		Exec(ctx)
+	return CheckUpdateResult(ctx, result, err)
+}
+
+func (r *BunRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewUpdate().
+		Model((*entity.RefreshToken)(nil)).
+		Set("revoked_at = ?", Now()).
+		Where("family_id = ?", familyID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunRefreshTokenRepository) RevokeByUserID(ctx context.Context, userID int64) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewUpdate().
+		Model((*entity.RefreshToken)(nil)).
+		Set("revoked_at = ?", Now()).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunRefreshTokenRepository) PurgeExpired(ctx context.Context, before time.Time) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	expiredFamilies := db.NewSelect().
+		Model((*entity.RefreshToken)(nil)).
+		Column("family_id").
+		Group("family_id").
+		Having("max(expires_at) < ?", before)
+	_, err := db.NewDelete().
+		Model((*entity.RefreshToken)(nil)).
+		Where("(family_id IN (?) OR revoked_at < ?)", expiredFamilies, before).
+		Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+
+	"minigo/internal/domain/entity"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/dbctx"
+
+	"github.com/uptrace/bun"
+)
+
+// BunRoleRepository implements RoleRepository using Bun ORM
+type BunRoleRepository struct {
+	DB *bun.DB
+}
+
+// NewBunRoleRepository creates a new BunRoleRepository
+func NewBunRoleRepository(db *bun.DB) repository.RoleRepository {
+	// m2m 关联需要先注册中间表模型
+	db.RegisterModel((*entity.RolePermission)(nil))
+	return &BunRoleRepository{DB: db}
+}
+
+func (r *BunRoleRepository) List(ctx context.Context) ([]*entity.Role, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var roles []*entity.Role
+	err := db.NewSelect().
+		Model(&roles).
+		Relation("Permissions", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("p.id")
+		}).
+		Order("r.id").
+		Scan(ctx)
+	if err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return roles, nil
+}
+
+func (r *BunRoleRepository) GetByCode(ctx context.Context, code string) (*entity.Role, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var role entity.Role
+	err := db.NewSelect().
+		Model(&role).
+		Where("r.code = ?", code).
+		Scan(ctx)
+	if err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return &role, nil
+}
+
+func (r *BunRoleRepository) ListByUserID(ctx context.Context, userID int64) ([]*entity.Role, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var roles []*entity.Role
+	err := db.NewSelect().
+		Model(&roles).
+		Join("JOIN user_roles AS ur ON ur.role_id = r.id").
+		Where("ur.user_id = ?", userID).
+		Order("r.id").
+		Scan(ctx)
+	if err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return roles, nil
+}
+
+func (r *BunRoleRepository) ListPermissionCodesByUserID(ctx context.Context, userID int64) ([]string, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var codes []string
+	err := db.NewSelect().
+		TableExpr("permissions AS p").
+		ColumnExpr("DISTINCT p.code").
+		Join("JOIN role_permissions AS rp ON rp.permission_id = p.id").
+		Join("JOIN user_roles AS ur ON ur.role_id = rp.role_id").
+		Where("ur.user_id = ?", userID).
+		OrderExpr("p.code").
+		Scan(ctx, &codes)
+	if err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return codes, nil
+}
+
+func (r *BunRoleRepository) AssignToUser(ctx context.Context, userID, roleID int64) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewInsert().
+		Model(&entity.UserRole{UserID: userID, RoleID: roleID, CreatedAt: Now()}).
+		On("CONFLICT (user_id, role_id) DO NOTHING").
+		Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunRoleRepository) RemoveFromUser(ctx context.Context, userID, roleID int64) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	result, err := db.NewDelete().
+		Model((*entity.UserRole)(nil)).
+		Where("user_id = ?", userID).
+		Where("role_id = ?", roleID).
+		Exec(ctx)
+	return CheckDeleteResult(ctx, result, err)
+}
+
+func (r *BunRoleRepository) SetRequireTwoFactor(ctx context.Context, roleID int64, required bool) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	result, err := db.NewUpdate().
+		Model((*entity.Role)(nil)).
+		Set("require_two_factor = ?", required).
+		Set("updated_at = ?", Now()).
+		Where("id = ?", roleID).
+		Exec(ctx)
+	return CheckUpdateResult(ctx, result, err)
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"minigo/internal/domain/entity"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/dbctx"
+	"minigo/internal/infrastructure/id"
+
+	"github.com/uptrace/bun"
+)
+
+// BunTwoFactorRepository implements TwoFactorRepository using Bun ORM
+type BunTwoFactorRepository struct {
+	DB *bun.DB
+}
+
+// NewBunTwoFactorRepository creates a new BunTwoFactorRepository
+func NewBunTwoFactorRepository(db *bun.DB) repository.TwoFactorRepository {
+	return &BunTwoFactorRepository{DB: db}
+}
+
+func (r *BunTwoFactorRepository) GetTOTP(ctx context.Context, userID int64) (*entity.UserTOTP, error) {
+	return r.getTOTP(ctx, userID, false)
+}
+
+func (r *BunTwoFactorRepository) GetTOTPForUpdate(ctx context.Context, userID int64) (*entity.UserTOTP, error) {
+	return r.getTOTP(ctx, userID, true)
+}
+
+func (r *BunTwoFactorRepository) getTOTP(ctx context.Context, userID int64, forUpdate bool) (*entity.UserTOTP, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var totp entity.UserTOTP
+	query := db.NewSelect().
+		Model(&totp).
+		Where("ut.user_id = ?", userID)
+	if forUpdate {
+		query = query.For("UPDATE")
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return &totp, nil
+}
+
+func (r *BunTwoFactorRepository) SaveTOTP(ctx context.Context, totp *entity.UserTOTP) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	totp.UpdatedAt = Now()
+	_, err := db.NewInsert().
+		Model(totp).
+		On("CONFLICT (user_id) DO UPDATE").
+		Set("secret = EXCLUDED.secret").
+		Set("enabled_at = EXCLUDED.enabled_at").
+		Set("last_used_step = EXCLUDED.last_used_step").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunTwoFactorRepository) DeleteTOTP(ctx context.Context, userID int64) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	if _, err := db.NewDelete().
+		Model((*entity.RecoveryCode)(nil)).
+		Where("user_id = ?", userID).
+		Exec(ctx); err != nil {
+		return ConvertExecError(ctx, err)
+	}
+	result, err := db.NewDelete().
+		Model((*entity.UserTOTP)(nil)).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	return CheckDeleteResult(ctx, result, err)
+}
+
+func (r *BunTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID int64, hashes []string) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	if _, err := db.NewDelete().
+		Model((*entity.RecoveryCode)(nil)).
+		Where("user_id = ?", userID).
+		Exec(ctx); err != nil {
+		return ConvertExecError(ctx, err)
+	}
+	codes := make([]*entity.RecoveryCode, 0, len(hashes))
+	for _, hash := range hashes {
+		codes = append(codes, &entity.RecoveryCode{ID: id.NextID(), UserID: userID, CodeHash: hash})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	_, err := db.NewInsert().Model(&codes).Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int64, hash string, usedAt time.Time) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	result, err := db.NewUpdate().
+		Model((*entity.RecoveryCode)(nil)).
+		Set("used_at = ?", usedAt).
+		Where("user_id = ?", userID).
+		Where("code_hash = ?", hash).
+		Where("used_at IS NULL").
+		Exec(ctx)
+	return CheckUpdateResult(ctx, result, err)
+}
+
+func (r *BunTwoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID int64) (int, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	count, err := db.NewSelect().
+		Model((*entity.RecoveryCode)(nil)).
+		Where("urc.user_id = ?", userID).
+		Where("urc.used_at IS NULL").
+		Count(ctx)
+	if err != nil {
+		return 0, ConvertQueryError(ctx, err)
+	}
+	return count, nil
+}
+
+func (r *BunTwoFactorRepository) CreateChallenge(ctx context.Context, challenge *entity.TwoFactorChallenge) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewInsert().Model(challenge).Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunTwoFactorRepository) GetChallengeByHashForUpdate(ctx context.Context, tokenHash string) (*entity.TwoFactorChallenge, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var challenge entity.TwoFactorChallenge
+	err := db.NewSelect().
+		Model(&challenge).
+		Where("token_hash = ?", tokenHash).
+		For("UPDATE").
+		Scan(ctx)
+	if err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return &challenge, nil
+}
+
+func (r *BunTwoFactorRepository) IncrementChallengeAttempts(ctx context.Context, id int64) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	result, err := db.NewUpdate().
+		Model((*entity.TwoFactorChallenge)(nil)).
+		Set("attempts = attempts + 1").
+		Where("id = ?", id).
+		Exec(ctx)
+	return CheckUpdateResult(ctx, result, err)
+}
+
+func (r *BunTwoFactorRepository) MarkChallengeUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	result, err := db.NewUpdate().
+		Model((*entity.TwoFactorChallenge)(nil)).
+		Set("used_at = ?", usedAt).
+		Where("id = ?", id).
+		Where("used_at IS NULL").
+		Exec(ctx)
+	return CheckUpdateResult(ctx, result, err)
+}
+
+func (r *BunTwoFactorRepository) PurgeExpiredChallenges(ctx context.Context, before time.Time) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewDelete().
+		Model((*entity.TwoFactorChallenge)(nil)).
+		Where("expires_at < ?", before).
+		Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
@@ -23,7 +23,7 @@ func NewBunUserRepository(db *bun.DB) repository.UserRepository {
 func (r *BunUserRepository) Create(ctx context.Context, user *entity.User) error {
 	db := dbctx.FromCtx(ctx, r.DB)
 	_, err := db.NewInsert().Model(user).Exec(ctx)
-	return ConvertExecError(err)
+	return ConvertExecError(ctx, err)
 }
 
 func (r *BunUserRepository) Update(ctx context.Context, user *entity.User) error {
@@ -32,7 +32,7 @@ func (r *BunUserRepository) Update(ctx context.Context, user *entity.User) error
 		Model(user).
 		WherePK().
 		Exec(ctx)
-	return CheckUpdateResult(result, err)
+	return CheckUpdateResult(ctx, result, err)
 }
 
 func (r *BunUserRepository) GetByID(ctx context.Context, id int64) (*entity.User, error) {
@@ -43,7 +43,7 @@ func (r *BunUserRepository) GetByID(ctx context.Context, id int64) (*entity.User
 		WherePK().
 		Scan(ctx)
 	if err != nil {
-		return nil, ConvertQueryError(err)
+		return nil, ConvertQueryError(ctx, err)
 	}
 	return &user, nil
 }
@@ -56,11 +56,48 @@ func (r *BunUserRepository) GetByPhone(ctx context.Context, phone string) (*enti
 		Where("phone = ?", phone).
 		Scan(ctx)
 	if err != nil {
-		return nil, ConvertQueryError(err)
+		return nil, ConvertQueryError(ctx, err)
 	}
 	return &user, nil
 }
 
+func (r *BunUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var user entity.User
+	err := db.NewSelect().
+		Model(&user).
+		Where("lower(email) = ?", entity.NormalizeEmail(email)).
+		Scan(ctx)
+	if err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return &user, nil
+}
+
+func (r *BunUserRepository) List(ctx context.Context, filter repository.UserListFilter) ([]*entity.User, int, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var users []*entity.User
+	query := db.NewSelect().Model(&users)
+	if filter.Keyword != "" {
+		keyword := "%" + filter.Keyword + "%"
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("u.name ILIKE ?", keyword).WhereOr("u.phone LIKE ?", keyword)
+		})
+	}
+	if filter.Status != nil {
+		query = query.Where("u.status = ?", *filter.Status)
+	}
+	total, err := query.
+		Order("u.created_at DESC").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, ConvertQueryError(ctx, err)
+	}
+	return users, total, nil
+}
+
 func (r *BunUserRepository) GetForUpdate(ctx context.Context, id int64) (*entity.User, error) {
 	db := dbctx.FromCtx(ctx, r.DB)
 	var user = entity.User{ID: id}
@@ -70,7 +107,7 @@ func (r *BunUserRepository) GetForUpdate(ctx context.Context, id int64) (*entity
 		For("UPDATE").
 		Scan(ctx)
 	if err != nil {
-		return nil, ConvertQueryError(err)
+		return nil, ConvertQueryError(ctx, err)
 	}
 	return &user, nil
 }
@@ -82,6 +119,6 @@ func (r *BunUserRepository) Delete(ctx context.Context, id int64) error {
 		Model(user).
 		WherePK().
 		Exec(ctx)
-	return CheckDeleteResult(result, err)
+	return CheckDeleteResult(ctx, result, err)
 
 }
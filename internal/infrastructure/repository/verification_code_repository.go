@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"minigo/internal/domain/entity"
+	"minigo/internal/domain/repository"
+	"minigo/internal/infrastructure/dbctx"
+
+	"github.com/uptrace/bun"
+)
+
+// BunVerificationCodeRepository implements VerificationCodeRepository using Bun ORM
+type BunVerificationCodeRepository struct {
+	DB *bun.DB
+}
+
+// NewBunVerificationCodeRepository creates a new BunVerificationCodeRepository
+func NewBunVerificationCodeRepository(db *bun.DB) repository.VerificationCodeRepository {
+	return &BunVerificationCodeRepository{DB: db}
+}
+
+func (r *BunVerificationCodeRepository) Create(ctx context.Context, code *entity.VerificationCode) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewInsert().Model(code).Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
+
+func (r *BunVerificationCodeRepository) GetLatest(ctx context.Context, phone, purpose string) (*entity.VerificationCode, error) {
+	return r.getLatest(ctx, phone, purpose, false)
+}
+
+func (r *BunVerificationCodeRepository) GetLatestForUpdate(ctx context.Context, phone, purpose string) (*entity.VerificationCode, error) {
+	return r.getLatest(ctx, phone, purpose, true)
+}
+
+func (r *BunVerificationCodeRepository) getLatest(ctx context.Context, phone, purpose string, forUpdate bool) (*entity.VerificationCode, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	var code entity.VerificationCode
+	query := db.NewSelect().
+		Model(&code).
+		Where("vc.phone = ?", phone).
+		Where("vc.purpose = ?", purpose).
+		Order("vc.created_at DESC").
+		Limit(1)
+	if forUpdate {
+		query = query.For("UPDATE")
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, ConvertQueryError(ctx, err)
+	}
+	return &code, nil
+}
+
+func (r *BunVerificationCodeRepository) CountSince(ctx context.Context, phone string, since time.Time) (int, error) {
+	db := dbctx.FromCtx(ctx, r.DB)
+	count, err := db.NewSelect().
+		Model((*entity.VerificationCode)(nil)).
+		Where("vc.phone = ?", phone).
+		Where("vc.created_at >= ?", since).
+		Count(ctx)
+	if err != nil {
+		return 0, ConvertQueryError(ctx, err)
+	}
+	return count, nil
+}
+
+func (r *BunVerificationCodeRepository) IncrementAttempts(ctx context.Context, id int64) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	result, err := db.NewUpdate().
+		Model((*entity.VerificationCode)(nil)).
+		Set("attempts = attempts + 1").
+		Where("id = ?", id).
+		Exec(ctx)
+	return CheckUpdateResult(ctx, result, err)
+}
+
+func (r *BunVerificationCodeRepository) MarkUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	result, err := db.NewUpdate().
+		Model((*entity.VerificationCode)(nil)).
+		Set("used_at = ?", usedAt).
+		Where("id = ?", id).
+		Where("used_at IS NULL").
+		Exec(ctx)
+	return CheckUpdateResult(ctx, result, err)
+}
+
+func (r *BunVerificationCodeRepository) PurgeSentBefore(ctx context.Context, before time.Time) error {
+	db := dbctx.FromCtx(ctx, r.DB)
+	_, err := db.NewDelete().
+		Model((*entity.VerificationCode)(nil)).
+		Where("created_at < ?", before).
+		Exec(ctx)
+	return ConvertExecError(ctx, err)
+}
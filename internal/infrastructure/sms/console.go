@@ -0,0 +1,24 @@
+package sms
+
+import (
+	"context"
+
+	"minigo/internal/infrastructure/logging"
+)
+
+// ConsoleSender 将短信内容写入日志，不实际发送（开发环境使用）
+type ConsoleSender struct{}
+
+// NewConsoleSender 创建日志输出的短信发送器
+func NewConsoleSender() *ConsoleSender {
+	return &ConsoleSender{}
+}
+
+func (ConsoleSender) Send(ctx context.Context, msg Message) error {
+	logging.FromContext(ctx).WithFields(map[string]interface{}{
+		"phone":    msg.Phone,
+		"template": msg.Template,
+		"params":   msg.Params,
+	}).Info("sms_console")
+	return nil
+}
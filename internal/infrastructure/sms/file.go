@@ -0,0 +1,77 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileSender 将短信以 JSON 行追加到文件，测试和本地联调时读取验证码使用
+type FileSender struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// fileRecord 文件中的一行
+type fileRecord struct {
+	Message
+	SentAt time.Time `json:"sent_at"`
+}
+
+// NewFileSender 创建文件输出的短信发送器，目录不存在时自动创建
+func NewFileSender(path string) (*FileSender, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return &FileSender{path: path}, nil
+}
+
+func (s *FileSender) Send(ctx context.Context, msg Message) error {
+	line, err := json.Marshal(fileRecord{Message: msg, SentAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return &SendError{Provider: "file", Err: err}
+	}
+	defer f.Close()
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		return &SendError{Provider: "file", Err: err}
+	}
+	return nil
+}
+
+// LastMessage 返回发给 phone 的最后一条短信，没有时返回 false
+func (s *FileSender) LastMessage(phone string) (Message, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Message{}, false, nil
+		}
+		return Message{}, false, err
+	}
+	var (
+		last  Message
+		found bool
+	)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var record fileRecord
+		if err = dec.Decode(&record); err != nil {
+			return Message{}, false, err
+		}
+		if record.Phone == phone {
+			last, found = record.Message, true
+		}
+	}
+	return last, found, nil
+}
@@ -0,0 +1,37 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+)
+
+// 短信模板标识，由服务商侧（或 webhook 接收方）映射为实际模板
+const (
+	TemplateVerifyCode = "verify_code"
+)
+
+// Message 一条待发送的短信，Params 为模板变量
+type Message struct {
+	Phone    string            `json:"phone"`
+	Template string            `json:"template"`
+	Params   map[string]string `json:"params"`
+}
+
+// Sender sends SMS messages through a provider.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SendError 服务商拒绝或无法发送
+type SendError struct {
+	Provider string
+	Err      error
+}
+
+func (e *SendError) Error() string {
+	return fmt.Sprintf("sms %s: %v", e.Provider, e.Err)
+}
+
+func (e *SendError) Unwrap() error {
+	return e.Err
+}
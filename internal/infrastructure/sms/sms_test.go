@@ -0,0 +1,69 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSender(t *testing.T) {
+	ctx := context.Background()
+	sender, err := NewFileSender(filepath.Join(t.TempDir(), "sms", "sms.log"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, found, err := sender.LastMessage("13800000000"); err != nil || found {
+		t.Fatalf("Expected no message before sending, got %v (%v)", found, err)
+	}
+	for _, code := range []string{"111111", "222222"} {
+		msg := Message{Phone: "13800000000", Template: TemplateVerifyCode, Params: map[string]string{"code": code}}
+		if err = sender.Send(ctx, msg); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	_ = sender.Send(ctx, Message{Phone: "13900000000", Template: TemplateVerifyCode, Params: map[string]string{"code": "333333"}})
+
+	msg, found, err := sender.LastMessage("13800000000")
+	if err != nil || !found || msg.Params["code"] != "222222" {
+		t.Fatalf("Expected last code 222222, got %+v %v (%v)", msg, found, err)
+	}
+}
+
+func TestWebhookSender(t *testing.T) {
+	var (
+		received Message
+		auth     string
+		status   = http.StatusOK
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("quota exceeded"))
+	}))
+	defer server.Close()
+	sender := NewWebhookSender(server.URL, "s3cret")
+	msg := Message{Phone: "13800000000", Template: TemplateVerifyCode, Params: map[string]string{"code": "123456"}}
+
+	t.Run("posts the message with the bearer token", func(t *testing.T) {
+		if err := sender.Send(context.Background(), msg); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if auth != "Bearer s3cret" || received.Phone != msg.Phone || received.Params["code"] != "123456" {
+			t.Fatalf("Expected message with token, got %q %+v", auth, received)
+		}
+	})
+
+	t.Run("reports non-2xx responses", func(t *testing.T) {
+		status = http.StatusTooManyRequests
+		err := sender.Send(context.Background(), msg)
+		var sendErr *SendError
+		if !errors.As(err, &sendErr) || sendErr.Provider != "webhook" {
+			t.Fatalf("Expected SendError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout 单次请求超时
+const webhookTimeout = 10 * time.Second
+
+// WebhookSender 将短信以 JSON POST 到 HTTP 接口，由接收方对接实际服务商（阿里云、腾讯云等）。
+// token 非空时通过 Authorization: Bearer 传递；接收方返回 2xx 视为发送成功。
+type WebhookSender struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewWebhookSender 创建 HTTP 接口短信发送器
+func NewWebhookSender(url, token string) *WebhookSender {
+	return &WebhookSender{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (s *WebhookSender) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return &SendError{Provider: "webhook", Err: err}
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return &SendError{Provider: "webhook", Err: fmt.Errorf("status %d: %s", res.StatusCode, bytes.TrimSpace(detail))}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"minigo/internal/infrastructure/logging"
+
+	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxStatementLen span 中记录的 SQL 最大长度
+const maxStatementLen = 2048
+
+// QueryHook emits a client span for every bun query.
+type QueryHook struct{}
+
+var _ bun.QueryHook = (*QueryHook)(nil)
+
+func NewQueryHook() *QueryHook {
+	return &QueryHook{}
+}
+
+func (h *QueryHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	// 没有上游 span 时不创建孤立的数据库 span（如后台任务、迁移）
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	operation := event.Operation()
+	ctx, _ = Tracer().Start(ctx, "db."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation.name", operation),
+		),
+	)
+	return ctx
+}
+
+func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	defer span.End()
+
+	statement := logging.RedactString(event.Query)
+	if len(statement) > maxStatementLen {
+		statement = statement[:maxStatementLen]
+	}
+	span.SetAttributes(attribute.String("db.query.text", statement))
+	if event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows) {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	}
+}
@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"minigo/internal/infrastructure/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName 本服务创建 span 使用的 tracer 名称
+const instrumentationName = "minigo"
+
+// memoryExporter TRACING_EXPORTER=memory 时保存的内存导出器，供测试读取 span
+var memoryExporter *tracetest.InMemoryExporter
+
+// Init 根据 TRACING_EXPORTER（none/otlp/stdout/memory）配置全局 TracerProvider，
+// 并启用 W3C traceparent 传播。返回的 shutdown 在停机时刷新未导出的 span。
+func Init(ctx context.Context, cfg config.TracingConfig) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var opt sdktrace.TracerProviderOption
+	switch kind := cfg.Exporter; kind {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		var opts []otlptracehttp.Option
+		if endpoint := cfg.OTLPEndpoint; endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp exporter: %w", err)
+		}
+		opt = sdktrace.WithBatcher(exporter)
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("create stdout exporter: %w", err)
+		}
+		opt = sdktrace.WithSyncer(exporter)
+	case "memory":
+		memoryExporter = tracetest.NewInMemoryExporter()
+		opt = sdktrace.WithSyncer(memoryExporter)
+	default:
+		return nil, fmt.Errorf("unknown TRACING_EXPORTER %q", kind)
+	}
+
+	provider := NewTracerProvider(opt, cfg.ServiceName, cfg.SampleRatio)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewTracerProvider builds a provider that samples ratio of root spans and
+// follows the parent's sampling decision for propagated traces.
+func NewTracerProvider(exporter sdktrace.TracerProviderOption, serviceName string, ratio float64) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		exporter,
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", serviceName),
+		)),
+	)
+}
+
+// MemoryExporter returns the in-memory exporter, nil unless TRACING_EXPORTER=memory.
+func MemoryExporter() *tracetest.InMemoryExporter {
+	return memoryExporter
+}
+
+// Tracer returns the tracer used for spans created by this service.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// TraceID 返回上下文中的 trace ID，没有有效 span 时返回空字符串
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// SpanID 返回上下文中的 span ID，没有有效 span 时返回空字符串
+func SpanID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasSpanID() {
+		return ""
+	}
+	return sc.SpanID().String()
+}
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 
 	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel/codes"
 
 	"minigo/internal/infrastructure/dbctx"
+	"minigo/internal/infrastructure/tracing"
 )
 
 // Manager provides transaction management using Bun ORM
@@ -20,7 +22,16 @@ func NewManager(db *bun.DB) *Manager {
 }
 
 // InTx executes a function within a database transaction
-func (m *Manager) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+func (m *Manager) InTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "db.transaction")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	return m.DB.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
 		// Inject the transaction into the context
 		ctx = dbctx.WithDB(ctx, tx)
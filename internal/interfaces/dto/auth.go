@@ -9,11 +9,98 @@ type ShopLoginRequest struct {
 // PasswordChangeRequest represents shop password change payload.
 type PasswordChangeRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=6,max=20"`
+	NewPassword string `json:"new_password" binding:"required"`
 }
 
 // UpdateProfileRequest represents shop profile update payload.
+// 更换手机号需使用 PhoneChangeRequest，phone 仅允许传当前手机号
 type UpdateProfileRequest struct {
 	Name  string `json:"name" binding:"required"`
-	Phone string `json:"phone" binding:"required,min=11,max=11"`
+	Phone string `json:"phone" binding:"omitempty,len=11"`
+	Email string `json:"email" binding:"omitempty,email,max=254"` // 为空表示清除邮箱
+}
+
+// RefreshTokenRequest represents refresh / logout payload.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenResponse 登录/刷新返回的令牌信息
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // 访问令牌有效期（秒）
+	// PasswordExpired 密码已过期，修改密码前访问其他接口返回 PWD_009
+	PasswordExpired bool `json:"password_expired,omitempty"`
+}
+
+// LoginUnlockRequest 解锁登录请求，手机号和IP至少指定一个
+type LoginUnlockRequest struct {
+	Phone string `json:"phone" binding:"omitempty,len=11"`
+	IP    string `json:"ip" binding:"omitempty,ip"`
+}
+
+// LockoutEventListRequest 登录锁定记录列表请求
+type LockoutEventListRequest struct {
+	Phone string `form:"phone"`
+	IP    string `form:"ip"`
+	Page  int    `form:"page,default=1" binding:"min=1"`
+	Size  int    `form:"size,default=20" binding:"min=1,max=100"`
+}
+
+// SendCodeRequest 发送短信验证码请求
+type SendCodeRequest struct {
+	Phone   string `json:"phone" binding:"required,len=11"`
+	Purpose string `json:"purpose" binding:"required,oneof=register login reset_password"`
+}
+
+// PhoneCodeRequest 发送更换手机号验证码请求
+type PhoneCodeRequest struct {
+	Phone string `json:"phone" binding:"required,len=11"`
+}
+
+// PasswordPolicyResponse 当前生效的密码规则，密码不符合时返回 PWD_001 及字段级错误
+type PasswordPolicyResponse struct {
+	MinLength      int   `json:"min_length"`
+	MaxLength      int   `json:"max_length"`
+	RequireUpper   bool  `json:"require_upper"`
+	RequireLower   bool  `json:"require_lower"`
+	RequireDigit   bool  `json:"require_digit"`
+	RequireSymbol  bool  `json:"require_symbol"`
+	MinCharClasses int   `json:"min_char_classes"`
+	History        int   `json:"history"`      // 不能与最近 N 次的密码相同
+	MaxAgeDays     int64 `json:"max_age_days"` // 0 表示不过期
+}
+
+// SendCodeResponse 验证码有效期与可再次发送前的等待时间（秒）
+type SendCodeResponse struct {
+	ExpiresIn   int64 `json:"expires_in"`
+	ResendAfter int64 `json:"resend_after"`
+}
+
+// CodeLoginRequest 短信验证码登录请求
+type CodeLoginRequest struct {
+	Phone string `json:"phone" binding:"required,len=11"`
+	Code  string `json:"code" binding:"required"`
+}
+
+// PhoneChangeRequest 更换手机号请求，code 为发送到新手机号的验证码
+type PhoneChangeRequest struct {
+	Phone string `json:"phone" binding:"required,len=11"`
+	Code  string `json:"code" binding:"required"`
+}
+
+// PasswordForgotRequest 申请找回密码请求，phone 与 email 二选一
+type PasswordForgotRequest struct {
+	Phone string `json:"phone" binding:"required_without=Email,omitempty,len=11"`
+	Email string `json:"email" binding:"required_without=Phone,omitempty,email,max=254"`
+}
+
+// PasswordResetByCodeRequest 忘记密码时重置密码，凭短信验证码（phone + code）或邮件中的 token
+type PasswordResetByCodeRequest struct {
+	Phone       string `json:"phone" binding:"omitempty,len=11"`
+	Code        string `json:"code" binding:"required_with=Phone"`
+	Token       string `json:"token" binding:"required_without=Phone"`
+	NewPassword string `json:"new_password" binding:"required"`
 }
@@ -0,0 +1,11 @@
+package dto
+
+// UserRoleAssignRequest 分配角色请求
+type UserRoleAssignRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// RoleTwoFactorRequest 设置角色是否要求两步验证
+type RoleTwoFactorRequest struct {
+	Required *bool `json:"required" binding:"required"`
+}
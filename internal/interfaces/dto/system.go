@@ -0,0 +1,13 @@
+package dto
+
+// LogLevelRequest 调整日志级别请求
+type LogLevelRequest struct {
+	Level string `json:"level" binding:"required,oneof=debug info warn error"`
+}
+
+// LogLevelResponse 当前日志级别
+type LogLevelResponse struct {
+	Level string `json:"level"`
+	// Source 级别来源：config 跟随 LOG_LEVEL（含热更新），runtime 为管理接口设置
+	Source string `json:"source"`
+}
@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+// TwoFactorChallengeResponse 密码验证通过但需要两步验证时的登录响应
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired  bool   `json:"two_factor_required"`
+	ChallengeToken     string `json:"challenge_token"`
+	ExpiresIn          int64  `json:"expires_in"`          // 挑战有效期（秒）
+	EnrollmentRequired bool   `json:"enrollment_required"` // 需先调用 /api/auth/2fa/enroll 绑定验证器
+}
+
+// TwoFactorEnrollRequest 凭登录挑战绑定验证器
+type TwoFactorEnrollRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+// TwoFactorVerifyRequest 登录第二步，code 为验证器应用的 6 位验证码或恢复码
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// TwoFactorVerifyResponse 登录第二步完成后的令牌，首次绑定时附带恢复码
+type TwoFactorVerifyResponse struct {
+	TokenResponse
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// TOTPSetupResponse 待验证的 TOTP 密钥，otpauth_uri 可直接渲染为二维码
+type TOTPSetupResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+// TwoFactorCodeRequest 验证码请求
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TwoFactorDisableRequest 关闭两步验证请求
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// RecoveryCodesResponse 恢复码（仅生成时返回一次）
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorStatusResponse 两步验证状态
+type TwoFactorStatusResponse struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabled_at,omitempty"`
+	Required          bool       `json:"required"`
+	RecoveryCodesLeft int        `json:"recovery_codes_left"`
+}
@@ -4,14 +4,15 @@ package dto
 type UserCreateRequest struct {
 	Name       string `json:"name" binding:"required,min=2,max=50"`
 	Phone      string `json:"phone" binding:"required,len=11"`
-	Password   string `json:"password" binding:"required,min=6,max=20"`
+	Email      string `json:"email" binding:"omitempty,email,max=254"`
+	Password   string `json:"password" binding:"required"`
 	Status     int16  `json:"status" binding:"min=0,max=2"`
 	ReferrerID *int64 `json:"referrer_id,string"`
 }
 
 // UserBatchCreateRequest 批量创建用户请求
 type UserBatchCreateRequest struct {
-	Users []UserCreateRequest `json:"users" binding:"required,dive"`
+	Users []UserCreateRequest `json:"users" binding:"required,min=1,max=100,dive"`
 }
 
 type FailedUser struct {
@@ -30,25 +31,27 @@ type UserBatchCreateResponse struct {
 type UserRegisterRequest struct {
 	Name     string `json:"name" binding:"required,min=2,max=50"`
 	Phone    string `json:"phone" binding:"required,len=11"`
-	Password string `json:"password" binding:"required,min=6,max=20"`
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code"` // 短信验证码（purpose=register）
 }
 
 // UserListRequest 用户列表请求
 type UserListRequest struct {
 	Keyword string `form:"keyword"`
 	Status  *int16 `form:"status"`
-	Page    int    `form:"page" binding:"min=1" default:"1"`
-	Size    int    `form:"size" binding:"min=1,max=100" default:"20"`
+	Page    int    `form:"page,default=1" binding:"min=1"`
+	Size    int    `form:"size,default=20" binding:"min=1,max=100"`
 }
 
 // UserUpdateRequest 用户信息更新请求
 type UserUpdateRequest struct {
 	Name   string `json:"name" binding:"required,min=2,max=50"`
 	Phone  string `json:"phone" binding:"required,len=11"`
+	Email  string `json:"email" binding:"omitempty,email,max=254"` // 为空表示清除邮箱
 	Status *int16 `json:"status"`
 }
 
 // UserPasswordResetRequest 用户密码重置请求
 type UserPasswordResetRequest struct {
-	Password string `json:"password" binding:"required,min=6,max=20"`
+	Password string `json:"password" binding:"required"`
 }
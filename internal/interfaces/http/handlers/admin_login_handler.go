@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"minigo/internal/application/service"
+	"minigo/internal/interfaces/dto"
+	"minigo/internal/interfaces/middleware"
+	resp "minigo/internal/interfaces/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminLoginHandler handles admin login protection endpoints.
+type AdminLoginHandler struct {
+	authService *service.AuthService
+}
+
+func NewAdminLoginHandler(authService *service.AuthService) *AdminLoginHandler {
+	return &AdminLoginHandler{authService: authService}
+}
+
+// ListLockouts implements GET /api/admin/login-lockouts
+// ListLockouts 分页查询登录锁定与解锁记录
+func (h *AdminLoginHandler) ListLockouts(c *gin.Context) {
+	var (
+		req dto.LockoutEventListRequest
+		ctx = c.Request.Context()
+	)
+
+	// 绑定查询参数
+	if !middleware.ValidateAndBindQuery(c, &req) {
+		return
+	}
+
+	events, total, err := h.authService.ListLockoutEvents(ctx, service.ListLockoutEventsParams{
+		Phone: req.Phone,
+		IP:    req.IP,
+		Page:  req.Page,
+		Size:  req.Size,
+	})
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.OkWithPage(c, events, total, req.Page, req.Size)
+}
+
+// Unlock implements POST /api/admin/login-lockouts/unlock
+// Unlock 解除手机号和/或IP的登录锁定
+func (h *AdminLoginHandler) Unlock(c *gin.Context) {
+	var (
+		req dto.LoginUnlockRequest
+		ctx = c.Request.Context()
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	if err := h.authService.UnlockLogin(ctx, service.UnlockLoginParams{
+		Phone:      req.Phone,
+		IP:         req.IP,
+		OperatorID: middleware.GetUserIDFromContext(c),
+	}); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"minigo/internal/application/service"
+	"minigo/internal/interfaces/dto"
+	"minigo/internal/interfaces/middleware"
+	resp "minigo/internal/interfaces/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminRoleHandler handles role assignment endpoints.
+type AdminRoleHandler struct {
+	roleService *service.RoleService
+}
+
+func NewAdminRoleHandler(roleService *service.RoleService) *AdminRoleHandler {
+	return &AdminRoleHandler{roleService: roleService}
+}
+
+// ListRoles implements GET /api/admin/roles
+// ListRoles 获取全部角色及权限
+func (h *AdminRoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles(c.Request.Context())
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, roles)
+}
+
+// GetUserRoles implements GET /api/admin/users/:id/roles
+// GetUserRoles 获取用户角色
+func (h *AdminRoleHandler) GetUserRoles(c *gin.Context) {
+	userID, ok := middleware.ValidateIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	roles, err := h.roleService.GetUserRoles(c.Request.Context(), userID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, roles)
+}
+
+// AssignRole implements POST /api/admin/users/:id/roles
+// AssignRole 为用户分配角色
+func (h *AdminRoleHandler) AssignRole(c *gin.Context) {
+	var req dto.UserRoleAssignRequest
+
+	userID, ok := middleware.ValidateIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	if err := h.roleService.AssignRole(c.Request.Context(), userID, req.Role); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
+
+// RemoveRole implements DELETE /api/admin/users/:id/roles/:role
+// RemoveRole 移除用户角色
+func (h *AdminRoleHandler) RemoveRole(c *gin.Context) {
+	userID, ok := middleware.ValidateIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.roleService.RemoveRole(c.Request.Context(), userID, c.Param("role")); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
+
+// SetTwoFactor implements PUT /api/admin/roles/:role/two-factor
+// SetTwoFactor 设置角色是否要求两步验证
+func (h *AdminRoleHandler) SetTwoFactor(c *gin.Context) {
+	var req dto.RoleTwoFactorRequest
+
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	if err := h.roleService.SetTwoFactorRequired(c.Request.Context(), c.Param("role"), *req.Required); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
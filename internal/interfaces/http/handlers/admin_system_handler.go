@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	apperrors "minigo/internal/domain/errors"
+	"minigo/internal/infrastructure/config"
+	"minigo/internal/infrastructure/logging"
+	"minigo/internal/interfaces/dto"
+	"minigo/internal/interfaces/middleware"
+	resp "minigo/internal/interfaces/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminSystemHandler handles runtime settings endpoints.
+type AdminSystemHandler struct {
+	config *config.Watcher
+}
+
+func NewAdminSystemHandler(watcher *config.Watcher) *AdminSystemHandler {
+	return &AdminSystemHandler{config: watcher}
+}
+
+// GetConfig implements GET /api/admin/config
+// GetConfig 导出当前生效的配置，密钥和数据库密码已隐藏
+func (h *AdminSystemHandler) GetConfig(c *gin.Context) {
+	resp.Ok(c, h.config.Current().Redacted())
+}
+
+// errConfigReloadRejected 配置文件无法读取或未通过校验，具体原因只记录在日志中
+var errConfigReloadRejected = apperrors.NewValidationError("CONFIG_001", "配置未通过校验，当前配置保持不变")
+
+// ReloadConfig implements POST /api/admin/config/reload
+// ReloadConfig 立即重新加载配置文件；失败时原因记录在 config_reload_rejected 日志中，当前配置保持不变
+func (h *AdminSystemHandler) ReloadConfig(c *gin.Context) {
+	if err := h.config.Reload(); err != nil {
+		logging.FromContext(c.Request.Context()).WithError(err).Error("config_reload_rejected")
+		middleware.HandleError(c, errConfigReloadRejected)
+		return
+	}
+
+	resp.Ok(c, h.config.Current().Redacted())
+}
+
+// GetLogLevel implements GET /api/admin/system/log-level
+// GetLogLevel 获取应用日志当前级别
+func (h *AdminSystemHandler) GetLogLevel(c *gin.Context) {
+	resp.Ok(c, logLevelResponse())
+}
+
+// SetLogLevel implements PUT /api/admin/system/log-level
+// SetLogLevel 运行时调整应用日志级别，之后配置热更新不再修改级别，重启后恢复为 LOG_LEVEL
+func (h *AdminSystemHandler) SetLogLevel(c *gin.Context) {
+	var req dto.LogLevelRequest
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	previous := logging.Level()
+	if err := logging.OverrideLevel(req.Level); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	// 使用 warn 记录，确保调高级别后仍能看到这条变更
+	logging.FromContext(c.Request.Context()).WithFields(map[string]interface{}{
+		"from": previous,
+		"to":   req.Level,
+	}).Warn("log_level_changed")
+
+	resp.Ok(c, logLevelResponse())
+}
+
+func logLevelResponse() dto.LogLevelResponse {
+	source := "config"
+	if logging.LevelOverridden() {
+		source = "runtime"
+	}
+	return dto.LogLevelResponse{Level: logging.Level(), Source: source}
+}
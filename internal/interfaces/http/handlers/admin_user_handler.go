@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"minigo/internal/application/service"
+	"minigo/internal/domain/entity"
+	apperrors "minigo/internal/domain/errors"
+	"minigo/internal/interfaces/dto"
+	"minigo/internal/interfaces/middleware"
+	resp "minigo/internal/interfaces/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminUserHandler handles admin user management endpoints.
+type AdminUserHandler struct {
+	userService      *service.UserService
+	twoFactorService *service.TwoFactorService
+}
+
+func NewAdminUserHandler(userService *service.UserService, twoFactorService *service.TwoFactorService) *AdminUserHandler {
+	return &AdminUserHandler{
+		userService:      userService,
+		twoFactorService: twoFactorService,
+	}
+}
+
+// Create implements POST /api/admin/users
+// Create 管理端创建用户
+func (h *AdminUserHandler) Create(c *gin.Context) {
+	var (
+		req dto.UserCreateRequest
+		ctx = c.Request.Context()
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.userService.CreateUser(ctx, toCreateUserParams(req))
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, user)
+}
+
+// BatchCreate implements POST /api/admin/users/batch
+// BatchCreate 批量创建用户，返回逐行失败原因
+func (h *AdminUserHandler) BatchCreate(c *gin.Context) {
+	var (
+		req dto.UserBatchCreateRequest
+		ctx = c.Request.Context()
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	items := make([]service.CreateUserParams, 0, len(req.Users))
+	for _, u := range req.Users {
+		items = append(items, toCreateUserParams(u))
+	}
+	result := h.userService.BatchCreateUsers(ctx, items)
+
+	// 构建响应
+	data := dto.UserBatchCreateResponse{
+		SuccessCount: result.SuccessCount,
+		FailedCount:  len(result.Failures),
+		FailedUsers:  make([]dto.FailedUser, 0, len(result.Failures)),
+	}
+	for _, f := range result.Failures {
+		message := "创建失败"
+		if appErr, ok := apperrors.AsAppError(f.Err); ok && appErr.Type != apperrors.SystemError {
+			message = appErr.Message
+			// 密码不符合策略时给出具体原因
+			if len(appErr.Fields) > 0 {
+				message = appErr.Fields[0].Message
+			}
+		}
+		data.FailedUsers = append(data.FailedUsers, dto.FailedUser{
+			Name:  f.Params.Name,
+			Phone: f.Params.Phone,
+			Error: message,
+		})
+	}
+
+	resp.Ok(c, data)
+}
+
+// List implements GET /api/admin/users
+// List 按关键字/状态分页查询用户
+func (h *AdminUserHandler) List(c *gin.Context) {
+	var (
+		req dto.UserListRequest
+		ctx = c.Request.Context()
+	)
+
+	// 绑定查询参数
+	if !middleware.ValidateAndBindQuery(c, &req) {
+		return
+	}
+
+	users, total, err := h.userService.ListUsers(ctx, service.ListUsersParams{
+		Keyword: req.Keyword,
+		Status:  req.Status,
+		Page:    req.Page,
+		Size:    req.Size,
+	})
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.OkWithPage(c, users, total, req.Page, req.Size)
+}
+
+// Get implements GET /api/admin/users/:id
+// Get 获取用户详情
+func (h *AdminUserHandler) Get(c *gin.Context) {
+	userID, ok := middleware.ValidateIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.GetUserByID(c.Request.Context(), userID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, user)
+}
+
+// Update implements PUT /api/admin/users/:id
+// Update 更新用户信息
+func (h *AdminUserHandler) Update(c *gin.Context) {
+	var req dto.UserUpdateRequest
+
+	userID, ok := middleware.ValidateIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	params := service.UpdateUserParams{
+		Name:   req.Name,
+		Phone:  req.Phone,
+		Email:  req.Email,
+		Status: req.Status,
+	}
+	if err := h.userService.UpdateUser(c.Request.Context(), userID, params); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
+
+// Enable implements POST /api/admin/users/:id/enable
+// Enable 启用用户
+func (h *AdminUserHandler) Enable(c *gin.Context) {
+	h.setStatus(c, entity.StatusNormal)
+}
+
+// Disable implements POST /api/admin/users/:id/disable
+// Disable 停用用户（同时强制下线）
+func (h *AdminUserHandler) Disable(c *gin.Context) {
+	h.setStatus(c, entity.StatusDisabled)
+}
+
+// ResetPassword implements PUT /api/admin/users/:id/password
+// ResetPassword 重置用户密码
+func (h *AdminUserHandler) ResetPassword(c *gin.Context) {
+	var req dto.UserPasswordResetRequest
+
+	userID, ok := middleware.ValidateIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	if err := h.userService.ResetPassword(c.Request.Context(), userID, req.Password); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
+
+// Delete implements DELETE /api/admin/users/:id
+// Delete 软删除用户
+func (h *AdminUserHandler) Delete(c *gin.Context) {
+	userID, ok := middleware.ValidateIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.userService.DeleteUser(c.Request.Context(), userID); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
+
+// RevokeTokens implements POST /api/admin/users/:id/revoke-tokens
+// RevokeTokens 强制用户下线
+func (h *AdminUserHandler) RevokeTokens(c *gin.Context) {
+	var ctx = c.Request.Context()
+
+	// 校验路径参数
+	userID, ok := middleware.ValidateIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.userService.RevokeUserTokens(ctx, userID); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
+
+// ResetTwoFactor implements DELETE /api/admin/users/:id/2fa
+// ResetTwoFactor 清除用户的两步验证（用户丢失设备时使用）
+func (h *AdminUserHandler) ResetTwoFactor(c *gin.Context) {
+	var ctx = c.Request.Context()
+
+	// 校验路径参数
+	userID, ok := middleware.ValidateIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.twoFactorService.Reset(ctx, userID); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
+
+// setStatus 变更用户状态
+func (h *AdminUserHandler) setStatus(c *gin.Context, status int16) {
+	userID, ok := middleware.ValidateIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.userService.SetUserStatus(c.Request.Context(), userID, status); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
+
+// toCreateUserParams 转换创建请求为服务参数
+func toCreateUserParams(req dto.UserCreateRequest) service.CreateUserParams {
+	return service.CreateUserParams{
+		Name:       req.Name,
+		Phone:      req.Phone,
+		Email:      req.Email,
+		Password:   req.Password,
+		Status:     req.Status,
+		ReferrerID: req.ReferrerID,
+	}
+}
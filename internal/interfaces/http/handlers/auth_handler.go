@@ -1,6 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
 	"minigo/internal/application/service"
 	"minigo/internal/domain/entity"
 	"minigo/internal/interfaces/dto"
@@ -12,14 +17,26 @@ import (
 
 // AuthHandler handles authentication endpoints.
 type AuthHandler struct {
-	authService *service.AuthService
-	userService *service.UserService
+	authService          *service.AuthService
+	userService          *service.UserService
+	verificationService  *service.VerificationService
+	passwordResetService *service.PasswordResetService
+	passwordPolicy       *service.PasswordPolicyService
 }
 
-func NewAuthHandler(authService *service.AuthService, userService *service.UserService) *AuthHandler {
+func NewAuthHandler(
+	authService *service.AuthService,
+	userService *service.UserService,
+	verificationService *service.VerificationService,
+	passwordResetService *service.PasswordResetService,
+	passwordPolicy *service.PasswordPolicyService,
+) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		userService: userService,
+		authService:          authService,
+		userService:          userService,
+		verificationService:  verificationService,
+		passwordResetService: passwordResetService,
+		passwordPolicy:       passwordPolicy,
 	}
 }
 
@@ -38,13 +55,299 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// 调用服务层登录逻辑
-	token, err := h.authService.Login(ctx, req.Phone, req.Password)
+	result, err := h.authService.Login(ctx, req.Phone, req.Password, c.ClientIP())
 	if err != nil {
+		handleLoginError(c, err)
+		return
+	}
+
+	respondLogin(c, result)
+}
+
+// LoginWithCode implements POST /api/auth/sms/login
+// LoginWithCode 短信验证码登录
+func (h *AuthHandler) LoginWithCode(c *gin.Context) {
+	var (
+		req dto.CodeLoginRequest
+		ctx = c.Request.Context()
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.authService.LoginWithCode(ctx, req.Phone, req.Code, c.ClientIP())
+	if err != nil {
+		handleLoginError(c, err)
+		return
+	}
+
+	respondLogin(c, result)
+}
+
+// respondLogin 返回令牌，需要两步验证时返回登录挑战
+func respondLogin(c *gin.Context, result *service.LoginResult) {
+	if challenge := result.Challenge; challenge != nil {
+		resp.Ok(c, dto.TwoFactorChallengeResponse{
+			TwoFactorRequired:  true,
+			ChallengeToken:     challenge.Token,
+			ExpiresIn:          int64(challenge.ExpiresIn.Seconds()),
+			EnrollmentRequired: challenge.EnrollmentRequired,
+		})
+		return
+	}
+
+	resp.Ok(c, toTokenResponse(result.Tokens))
+}
+
+// SendCode implements POST /api/auth/sms/code
+// SendCode 发送注册、登录或重置密码的短信验证码
+func (h *AuthHandler) SendCode(c *gin.Context) {
+	var req dto.SendCodeRequest
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	h.sendCode(c, service.SendCodeParams{Phone: req.Phone, Purpose: req.Purpose, IP: c.ClientIP()})
+}
+
+// SendPhoneChangeCode implements POST /api/auth/phone/code
+// SendPhoneChangeCode 向新手机号发送更换手机号的验证码
+func (h *AuthHandler) SendPhoneChangeCode(c *gin.Context) {
+	var req dto.PhoneCodeRequest
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	h.sendCode(c, service.SendCodeParams{Phone: req.Phone, Purpose: entity.VerifyPurposeChangePhone, IP: c.ClientIP()})
+}
+
+// sendCode 发送验证码
+func (h *AuthHandler) sendCode(c *gin.Context, params service.SendCodeParams) {
+	result, err := h.verificationService.SendCode(c.Request.Context(), params)
+	respondSendCode(c, result, err)
+}
+
+// respondSendCode 返回有效期和可再次发送的时间，发送间隔未到时设置 Retry-After
+func respondSendCode(c *gin.Context, result *service.SendCodeResult, err error) {
+	if err != nil {
+		var cooldown *service.VerificationCooldownError
+		if errors.As(err, &cooldown) {
+			setRetryAfter(c, cooldown.RetryAfter)
+		}
 		middleware.HandleError(c, err)
 		return
 	}
 
-	resp.Ok(c, token)
+	resp.Ok(c, dto.SendCodeResponse{
+		ExpiresIn:   int64(result.ExpiresIn.Seconds()),
+		ResendAfter: int64(result.ResendAfter.Seconds()),
+	})
+}
+
+// ForgotPassword implements POST /api/auth/password/forgot
+// ForgotPassword 忘记密码时按手机号发送短信验证码，或按邮箱发送重置链接
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req dto.PasswordForgotRequest
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.passwordResetService.RequestReset(c.Request.Context(), service.RequestPasswordResetParams{
+		Phone: req.Phone,
+		Email: req.Email,
+		IP:    c.ClientIP(),
+	})
+	respondSendCode(c, result, err)
+}
+
+// ResetPassword implements POST /api/auth/password/reset
+// ResetPassword 忘记密码时凭短信验证码或邮件中的重置令牌设置新密码
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var (
+		req dto.PasswordResetByCodeRequest
+		ctx = c.Request.Context()
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	params := service.ResetPasswordParams{
+		Phone:    req.Phone,
+		Code:     req.Code,
+		Token:    req.Token,
+		Password: req.NewPassword,
+	}
+	if err := h.passwordResetService.ResetPassword(ctx, params); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
+
+// PasswordPolicy implements GET /api/auth/password/policy
+// PasswordPolicy 返回当前密码规则，供注册、修改密码页面提示
+func (h *AuthHandler) PasswordPolicy(c *gin.Context) {
+	rules := h.passwordPolicy.Rules()
+	resp.Ok(c, dto.PasswordPolicyResponse{
+		MinLength:      rules.MinLength,
+		MaxLength:      rules.MaxLength,
+		RequireUpper:   rules.RequireUpper,
+		RequireLower:   rules.RequireLower,
+		RequireDigit:   rules.RequireDigit,
+		RequireSymbol:  rules.RequireSymbol,
+		MinCharClasses: rules.MinCharClasses,
+		History:        rules.History,
+		MaxAgeDays:     int64(rules.MaxAge / (24 * time.Hour)),
+	})
+}
+
+// ChangePhone implements PUT /api/auth/phone
+// ChangePhone 校验新手机号的验证码后更换手机号
+func (h *AuthHandler) ChangePhone(c *gin.Context) {
+	var (
+		req    dto.PhoneChangeRequest
+		ctx    = c.Request.Context()
+		userID = middleware.GetUserIDFromContext(c)
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	if err := h.userService.ChangePhone(ctx, userID, req.Phone, req.Code); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
+
+// EnrollTwoFactor implements POST /api/auth/2fa/enroll
+// EnrollTwoFactor 角色要求两步验证时，凭登录挑战绑定验证器
+func (h *AuthHandler) EnrollTwoFactor(c *gin.Context) {
+	var (
+		req dto.TwoFactorEnrollRequest
+		ctx = c.Request.Context()
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	setup, err := h.authService.EnrollTwoFactor(ctx, req.ChallengeToken)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, toTOTPSetupResponse(setup))
+}
+
+// VerifyTwoFactor implements POST /api/auth/2fa/verify
+// VerifyTwoFactor 登录第二步，校验验证码后签发令牌
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var (
+		req dto.TwoFactorVerifyRequest
+		ctx = c.Request.Context()
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.authService.VerifyTwoFactor(ctx, req.ChallengeToken, req.Code, c.ClientIP())
+	if err != nil {
+		handleLoginError(c, err)
+		return
+	}
+
+	resp.Ok(c, dto.TwoFactorVerifyResponse{
+		TokenResponse: toTokenResponse(result.Tokens),
+		RecoveryCodes: result.RecoveryCodes,
+	})
+}
+
+// handleLoginError 延迟或锁定期间告知客户端可再次尝试的时间
+func handleLoginError(c *gin.Context, err error) {
+	var throttled *service.LoginThrottledError
+	if errors.As(err, &throttled) {
+		setRetryAfter(c, throttled.RetryAfter)
+	}
+	middleware.HandleError(c, err)
+}
+
+// setRetryAfter 设置 Retry-After（秒，向上取整）
+func setRetryAfter(c *gin.Context, d time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+}
+
+// Refresh implements POST /api/auth/refresh
+// Refresh 使用刷新令牌换取新的令牌对
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var (
+		req dto.RefreshTokenRequest
+		ctx = c.Request.Context()
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	// 调用服务层轮换刷新令牌
+	pair, err := h.authService.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, toTokenResponse(pair))
+}
+
+// Logout implements POST /api/auth/logout
+// Logout 吊销当前刷新令牌所属的令牌族，并吊销请求携带的访问令牌
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var (
+		req dto.RefreshTokenRequest
+		ctx = c.Request.Context()
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	if err := h.authService.Logout(ctx, req.RefreshToken, middleware.BearerToken(c)); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
+
+// toTokenResponse 转换令牌对为响应DTO
+func toTokenResponse(pair *service.TokenPair) dto.TokenResponse {
+	return dto.TokenResponse{
+		AccessToken:     pair.AccessToken,
+		RefreshToken:    pair.RefreshToken,
+		TokenType:       "Bearer",
+		ExpiresIn:       int64(pair.ExpiresIn.Seconds()),
+		PasswordExpired: pair.PasswordExpired,
+	}
 }
 
 // Register implements POST /api/auth/register
@@ -62,14 +365,15 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 
 	// 构建参数
-	params := service.CreateUserParams{
+	params := service.RegisterParams{
 		Name:     req.Name,
 		Phone:    req.Phone,
 		Password: req.Password,
+		Code:     req.Code,
 	}
 
 	// 创建用户
-	if _, err = h.userService.CreateUser(ctx, params); err != nil {
+	if _, err = h.userService.Register(ctx, params); err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
@@ -135,14 +439,9 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	// 构建更新参数
-	params := service.UpdateUserParams{
-		Name:  req.Name,
-		Phone: req.Phone,
-	}
-
 	// 调用服务层更新用户信息逻辑
-	if err := h.userService.UpdateUser(ctx, userID, params); err != nil {
+	params := service.UpdateProfileParams{Name: req.Name, Phone: req.Phone, Email: req.Email}
+	if err := h.userService.UpdateProfile(ctx, userID, params); err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
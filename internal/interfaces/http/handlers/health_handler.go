@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"minigo/internal/infrastructure/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler serves liveness, readiness and status probes.
+// 探针输出原始 JSON（不使用统一响应包装），便于负载均衡和编排系统判断
+type HealthHandler struct {
+	checks *health.Registry
+}
+
+func NewHealthHandler(checks *health.Registry) *HealthHandler {
+	return &HealthHandler{checks: checks}
+}
+
+// Livez implements GET /livez
+// 进程能处理请求即为存活，不检查依赖
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusHealthy})
+}
+
+// Readyz implements GET /readyz
+// 仅执行关键检查；停机过程中返回 503，让流量尽快摘除
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.checks.Ready(c.Request.Context())
+	c.JSON(statusCode(report), report.Redacted())
+}
+
+// Status implements GET /status
+// 执行全部检查，降级时仍返回 200；不鉴权，不输出组件错误信息
+func (h *HealthHandler) Status(c *gin.Context) {
+	report := h.checks.Run(c.Request.Context())
+	c.JSON(statusCode(report), report.Redacted())
+}
+
+// StatusDetail implements GET /api/admin/system/status
+// 与 /status 相同的检查，附带各组件的错误信息
+func (h *HealthHandler) StatusDetail(c *gin.Context) {
+	report := h.checks.Run(c.Request.Context())
+	c.JSON(statusCode(report), report)
+}
+
+func statusCode(report health.Report) int {
+	if report.Status == health.StatusUnhealthy {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"minigo/internal/infrastructure/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler publishes token verification keys.
+type JWKSHandler struct {
+	tokens *auth.TokenIssuer
+}
+
+func NewJWKSHandler(tokens *auth.TokenIssuer) *JWKSHandler {
+	return &JWKSHandler{tokens: tokens}
+}
+
+// JWKS implements GET /.well-known/jwks.json
+// JWKS 输出标准 JWK Set（不使用统一响应包装），HS256 模式下为空集合
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.tokens.JWKS())
+}
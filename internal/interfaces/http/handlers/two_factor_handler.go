@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"minigo/internal/application/service"
+	"minigo/internal/interfaces/dto"
+	"minigo/internal/interfaces/middleware"
+	resp "minigo/internal/interfaces/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler handles two-factor settings of the current user.
+type TwoFactorHandler struct {
+	twoFactorService *service.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService *service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorService: twoFactorService}
+}
+
+// Status implements GET /api/auth/2fa
+// Status 获取两步验证状态
+func (h *TwoFactorHandler) Status(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		userID = middleware.GetUserIDFromContext(c)
+	)
+
+	status, err := h.twoFactorService.Status(ctx, userID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, dto.TwoFactorStatusResponse{
+		Enabled:           status.Enabled,
+		EnabledAt:         status.EnabledAt,
+		Required:          status.Required,
+		RecoveryCodesLeft: status.RecoveryCodesLeft,
+	})
+}
+
+// Setup implements POST /api/auth/2fa/setup
+// Setup 生成待验证的 TOTP 密钥
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	var (
+		ctx    = c.Request.Context()
+		userID = middleware.GetUserIDFromContext(c)
+	)
+
+	setup, err := h.twoFactorService.Setup(ctx, userID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, toTOTPSetupResponse(setup))
+}
+
+// Enable implements POST /api/auth/2fa/enable
+// Enable 校验验证码并启用两步验证
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	var (
+		req    dto.TwoFactorCodeRequest
+		ctx    = c.Request.Context()
+		userID = middleware.GetUserIDFromContext(c)
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	codes, err := h.twoFactorService.Enable(ctx, userID, req.Code)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable implements POST /api/auth/2fa/disable
+// Disable 关闭两步验证
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var (
+		req    dto.TwoFactorDisableRequest
+		ctx    = c.Request.Context()
+		userID = middleware.GetUserIDFromContext(c)
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	if err := h.twoFactorService.Disable(ctx, userID, req.Password, req.Code); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, nil)
+}
+
+// RegenerateRecoveryCodes implements POST /api/auth/2fa/recovery-codes
+// RegenerateRecoveryCodes 重新生成恢复码
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var (
+		req    dto.TwoFactorCodeRequest
+		ctx    = c.Request.Context()
+		userID = middleware.GetUserIDFromContext(c)
+	)
+
+	// 绑定并验证请求参数
+	if !middleware.ValidateAndBindJSON(c, &req) {
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(ctx, userID, req.Code)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	resp.Ok(c, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// toTOTPSetupResponse 转换 TOTP 密钥为响应DTO
+func toTOTPSetupResponse(setup *service.TOTPSetup) dto.TOTPSetupResponse {
+	return dto.TOTPSetupResponse{
+		Secret:     setup.Secret,
+		OtpauthURI: setup.URI,
+	}
+}
@@ -1,32 +1,87 @@
 package http
 
 import (
+	"fmt"
+	"net/http"
+	"time"
+
 	"minigo/internal/interfaces/http/handlers"
 
 	"github.com/gin-gonic/gin"
 	"github.com/uptrace/bun"
 
 	appsvc "minigo/internal/application/service"
+	"minigo/internal/domain/entity"
+	"minigo/internal/infrastructure/auth"
 	configx "minigo/internal/infrastructure/config"
+	"minigo/internal/infrastructure/health"
+	"minigo/internal/infrastructure/logging"
+	"minigo/internal/infrastructure/mail"
+	"minigo/internal/infrastructure/ratelimit"
 	infrarepo "minigo/internal/infrastructure/repository"
+	"minigo/internal/infrastructure/sms"
 	"minigo/internal/infrastructure/tx"
 	"minigo/internal/interfaces/middleware"
 )
 
 // BuildRouter builds the gin engine with routes and middleware.
-func BuildRouter(db *bun.DB) *gin.Engine {
+// 可热更新的配置（CORS 策略、限流容量、功能开关）通过 watcher 订阅生效。
+func BuildRouter(
+	watcher *configx.Watcher,
+	db *bun.DB,
+	tokens *auth.TokenIssuer,
+	checks *health.Registry,
+	rateLimitStore ratelimit.RateLimiter,
+	smsSender sms.Sender,
+	mailSender mail.Sender,
+	deliveries *appsvc.DeliveryQueue,
+) (*gin.Engine, error) {
+	cfg := watcher.Current()
 	// 设置Gin模式
-	if configx.IsDevEnv() {
+	if cfg.IsDev() {
 		gin.SetMode(gin.DebugMode)
 	} else {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	engine := gin.New()
 
+	// hot-reloadable settings
+	corsPolicies, err := middleware.NewCORSPolicies(corsPolicy(cfg.CORS))
+	if err != nil {
+		return nil, fmt.Errorf("cors: %w", err)
+	}
+	// 公钥可被任意来源读取，不携带凭证
+	err = corsPolicies.Override("/.well-known/", middleware.CORSPolicy{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet, http.MethodHead},
+		MaxAge:         24 * time.Hour,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cors: %w", err)
+	}
+	rateLimits, err := middleware.NewRateLimits(rateLimitStore, tokens, rateLimitOptions(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("rate limits: %w", err)
+	}
+	features := middleware.NewFeatureFlags(cfg.Features.Enabled)
+	watcher.Subscribe(func(old, cfg *configx.Config) {
+		if err := corsPolicies.Set(corsPolicy(cfg.CORS)); err != nil {
+			logging.L().WithError(err).Error("cors_policy_rejected")
+		}
+		if err := rateLimits.Set(rateLimitOptions(cfg)); err != nil {
+			logging.L().WithError(err).Error("rate_limit_policy_rejected")
+		}
+		features.Set(cfg.Features.Enabled)
+	})
+
 	// CORS middleware first to handle preflight requests
-	engine.Use(middleware.CORSMiddleware())
+	engine.Use(middleware.CORSMiddleware(corsPolicies))
 	// Request ID tracking
 	engine.Use(middleware.RequestIDMiddleware())
+	// W3C trace context and server spans
+	engine.Use(middleware.TracingMiddleware())
+	// per-route request metrics
+	engine.Use(middleware.MetricsMiddleware())
 	// global error handler
 	engine.Use(middleware.ErrorHandlerMiddleware())
 	engine.Use(gin.Recovery())
@@ -35,27 +90,198 @@ func BuildRouter(db *bun.DB) *gin.Engine {
 
 	// repositories
 	userRepo := infrarepo.NewBunUserRepository(db)
+	refreshTokenRepo := infrarepo.NewBunRefreshTokenRepository(db)
+	roleRepo := infrarepo.NewBunRoleRepository(db)
+	loginThrottleRepo := infrarepo.NewBunLoginThrottleRepository(db)
+	twoFactorRepo := infrarepo.NewBunTwoFactorRepository(db)
+	verificationCodeRepo := infrarepo.NewBunVerificationCodeRepository(db)
+	passwordResetTokenRepo := infrarepo.NewBunPasswordResetTokenRepository(db)
+	passwordHistoryRepo := infrarepo.NewBunPasswordHistoryRepository(db)
 
 	// transaction manager
 	txManager := tx.NewManager(db)
 
+	// token revocation store
+	var revocations auth.RevocationStore
+	switch cfg.JWT.RevocationStore {
+	case "memory":
+		revocations = auth.NewMemoryRevocationStore()
+	default:
+		revocations = auth.NewPGRevocationStore(db)
+	}
+
+	// TOTP secrets are sealed at rest when TOTP_ENCRYPTION_KEY is set (required in prod)
+	var totpSecrets *auth.SecretBox
+	if cfg.TwoFactor.EncryptionKey != "" {
+		key, err := configx.ParseMasterKey(cfg.TwoFactor.EncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("totp encryption key: %w", err)
+		}
+		if totpSecrets, err = auth.NewSecretBox(key); err != nil {
+			return nil, fmt.Errorf("totp encryption key: %w", err)
+		}
+	}
+
+	// verification codes are stored as HMAC-SHA256 with VERIFY_CODE_HASH_KEY (required in prod)
+	var codeHashKey []byte
+	if cfg.Verify.HashKey != "" {
+		if codeHashKey, err = configx.ParseMasterKey(cfg.Verify.HashKey); err != nil {
+			return nil, fmt.Errorf("verify code hash key: %w", err)
+		}
+	}
+
+	// common password blocklist, built-in list plus PASSWORD_BLOCKLIST_FILE
+	var passwordBlocklist *auth.PasswordBlocklist
+	if cfg.Password.Blocklist {
+		var err error
+		if passwordBlocklist, err = auth.NewPasswordBlocklist(cfg.Password.BlocklistFile); err != nil {
+			return nil, fmt.Errorf("password blocklist: %w", err)
+		}
+	}
+
 	// services
-	authSvc := appsvc.NewAuthService(userRepo)
-	userSvc := appsvc.NewUserService(userRepo, txManager)
+	passwordPolicySvc := appsvc.NewPasswordPolicyService(passwordHistoryRepo, passwordBlocklist, cfg.Password)
+	verificationSvc := appsvc.NewVerificationService(verificationCodeRepo, userRepo, smsSender, deliveries, txManager, codeHashKey, cfg.Verify)
+	authSvc := appsvc.NewAuthService(userRepo, roleRepo, refreshTokenRepo, tokens, revocations, loginThrottleRepo, twoFactorRepo, verificationSvc, passwordPolicySvc, txManager, totpSecrets, cfg.JWT, cfg.Login, cfg.TwoFactor)
+	userSvc := appsvc.NewUserService(userRepo, refreshTokenRepo, revocations, verificationSvc, passwordPolicySvc, txManager)
+	passwordResetSvc := appsvc.NewPasswordResetService(passwordResetTokenRepo, userRepo, userSvc, verificationSvc, mailSender, deliveries, txManager, cfg.Reset)
+	roleSvc := appsvc.NewRoleService(roleRepo, userRepo, revocations, txManager)
+	twoFactorSvc := appsvc.NewTwoFactorService(userRepo, roleRepo, twoFactorRepo, txManager, totpSecrets, cfg.TwoFactor)
 
 	// infrastructure services
 	//ossService := oss.NewOSSService()
 
 	// handlers
-	authHandler := handlers.NewAuthHandler(authSvc, userSvc)
+	authHandler := handlers.NewAuthHandler(authSvc, userSvc, verificationSvc, passwordResetSvc, passwordPolicySvc)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorSvc)
+	adminUserHandler := handlers.NewAdminUserHandler(userSvc, twoFactorSvc)
+	adminRoleHandler := handlers.NewAdminRoleHandler(roleSvc)
+	adminLoginHandler := handlers.NewAdminLoginHandler(authSvc)
+	adminSystemHandler := handlers.NewAdminSystemHandler(watcher)
+	jwksHandler := handlers.NewJWKSHandler(tokens)
+	healthHandler := handlers.NewHealthHandler(checks)
 	// health
 	engine.GET("/api/health", func(c *gin.Context) { c.JSON(200, gin.H{"status": "ok"}) })
+	engine.GET("/livez", healthHandler.Livez)
+	engine.GET("/readyz", healthHandler.Readyz)
+	engine.GET("/status", healthHandler.Status)
+	// public verification keys
+	engine.GET("/.well-known/jwks.json", jwksHandler.JWKS)
 
 	// admin routes (no shop context)
-	apiGroup := engine.Group("/api")
+	// 限流中间件只对匹配 RATE_LIMIT_POLICIES 的路由访问存储；按 IP 的全局限流需设置 RATE_LIMIT_CAPACITY 开启，
+	// 开启后每个 /api 请求都会访问限流存储（postgres 存储为一次事务）
+	apiGroup := engine.Group("/api", middleware.RateLimitMiddleware(rateLimits))
 	{
 		apiGroup.POST("/auth/login", authHandler.Login)
+		apiGroup.POST("/auth/refresh", authHandler.Refresh)
+		apiGroup.POST("/auth/logout", authHandler.Logout)
+		apiGroup.POST("/auth/register", authHandler.Register)
+		apiGroup.POST("/auth/sms/code", authHandler.SendCode)
+		apiGroup.POST("/auth/sms/login", middleware.RequireFeature(features, middleware.FeatureSMSLogin), authHandler.LoginWithCode)
+		apiGroup.POST("/auth/password/forgot", authHandler.ForgotPassword)
+		apiGroup.POST("/auth/password/reset", authHandler.ResetPassword)
+		apiGroup.GET("/auth/password/policy", authHandler.PasswordPolicy)
+		apiGroup.POST("/auth/2fa/enroll", authHandler.EnrollTwoFactor)
+		apiGroup.POST("/auth/2fa/verify", authHandler.VerifyTwoFactor)
+	}
+
+	// password expired: only viewing the profile and changing the password are allowed
+	passwordExpiry := middleware.PasswordExpiry("GET /api/auth/me", "PUT /api/auth/password")
+
+	// authenticated user routes
+	authGroup := apiGroup.Group("/auth", middleware.AuthMiddleware(tokens, revocations), passwordExpiry)
+	{
+		authGroup.GET("/me", authHandler.GetMe)
+		authGroup.PUT("/password", authHandler.ChangePassword)
+		authGroup.PUT("/profile", authHandler.UpdateProfile)
+		authGroup.POST("/phone/code", authHandler.SendPhoneChangeCode)
+		authGroup.PUT("/phone", authHandler.ChangePhone)
+		authGroup.GET("/2fa", twoFactorHandler.Status)
+		authGroup.POST("/2fa/setup", twoFactorHandler.Setup)
+		authGroup.POST("/2fa/enable", twoFactorHandler.Enable)
+		authGroup.POST("/2fa/disable", twoFactorHandler.Disable)
+		authGroup.POST("/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+	}
+
+	// admin routes
+	adminGroup := apiGroup.Group("/admin",
+		middleware.AuthMiddleware(tokens, revocations),
+		passwordExpiry,
+		middleware.RequireRoleMiddleware(entity.RoleAdmin),
+	)
+	{
+		// user management
+		userRead := middleware.RequirePermission(roleSvc, entity.PermUserRead)
+		userWrite := middleware.RequirePermission(roleSvc, entity.PermUserWrite)
+		adminGroup.GET("/users", userRead, adminUserHandler.List)
+		adminGroup.GET("/users/:id", userRead, adminUserHandler.Get)
+		adminGroup.POST("/users", userWrite, adminUserHandler.Create)
+		adminGroup.POST("/users/batch", userWrite, adminUserHandler.BatchCreate)
+		adminGroup.PUT("/users/:id", userWrite, adminUserHandler.Update)
+		adminGroup.POST("/users/:id/enable", userWrite, adminUserHandler.Enable)
+		adminGroup.POST("/users/:id/disable", userWrite, adminUserHandler.Disable)
+		adminGroup.PUT("/users/:id/password", userWrite, adminUserHandler.ResetPassword)
+		adminGroup.DELETE("/users/:id", userWrite, adminUserHandler.Delete)
+		adminGroup.POST("/users/:id/revoke-tokens", userWrite, adminUserHandler.RevokeTokens)
+		adminGroup.DELETE("/users/:id/2fa", userWrite, adminUserHandler.ResetTwoFactor)
+
+		// login lockouts
+		adminGroup.GET("/login-lockouts", userRead, adminLoginHandler.ListLockouts)
+		adminGroup.POST("/login-lockouts/unlock", userWrite, adminLoginHandler.Unlock)
+
+		// role assignments
+		adminGroup.GET("/roles", middleware.RequirePermission(roleSvc, entity.PermRoleRead), adminRoleHandler.ListRoles)
+		adminGroup.GET("/users/:id/roles", middleware.RequirePermission(roleSvc, entity.PermRoleRead), adminRoleHandler.GetUserRoles)
+		adminGroup.POST("/users/:id/roles", middleware.RequirePermission(roleSvc, entity.PermRoleWrite), adminRoleHandler.AssignRole)
+		adminGroup.DELETE("/users/:id/roles/:role", middleware.RequirePermission(roleSvc, entity.PermRoleWrite), adminRoleHandler.RemoveRole)
+		adminGroup.PUT("/roles/:role/two-factor", middleware.RequirePermission(roleSvc, entity.PermRoleWrite), adminRoleHandler.SetTwoFactor)
+
+		// runtime settings
+		adminGroup.GET("/config", middleware.RequirePermission(roleSvc, entity.PermSystemRead), adminSystemHandler.GetConfig)
+		adminGroup.POST("/config/reload", middleware.RequirePermission(roleSvc, entity.PermSystemWrite), adminSystemHandler.ReloadConfig)
+		adminGroup.GET("/system/log-level", middleware.RequirePermission(roleSvc, entity.PermSystemRead), adminSystemHandler.GetLogLevel)
+		adminGroup.PUT("/system/log-level", middleware.RequirePermission(roleSvc, entity.PermSystemWrite), adminSystemHandler.SetLogLevel)
+		adminGroup.GET("/system/status", middleware.RequirePermission(roleSvc, entity.PermSystemRead), healthHandler.StatusDetail)
 	}
 
-	return engine
+	return engine, nil
+}
+
+// rateLimitOptions 将 RATE_LIMIT_* 配置转换为限流中间件参数，策略已在配置校验时解析过
+func rateLimitOptions(cfg *configx.Config) middleware.RateLimitOptions {
+	policies, _ := cfg.RateLimit.RateLimitPolicies()
+	opts := middleware.RateLimitOptions{
+		Global:        ratelimit.Limit{Capacity: cfg.RateLimit.Capacity, Rate: cfg.RateLimit.Rate},
+		ExemptIPs:     cfg.RateLimit.ExemptIPs,
+		ExemptAPIKeys: cfg.RateLimit.ExemptAPIKeys,
+	}
+	for _, p := range policies {
+		policy := middleware.RateLimitPolicy{Name: p.Name, Method: p.Method, Path: p.Path, Key: p.Key}
+		switch p.Algorithm {
+		case "concurrency":
+			policy.Concurrency = p.Limit
+		case "sliding_window":
+			policy.Limit = ratelimit.Limit{Algorithm: ratelimit.SlidingWindow, Capacity: p.Limit, Window: p.Period}
+		default:
+			policy.Limit = ratelimit.Limit{
+				Algorithm: ratelimit.Algorithm(p.Algorithm),
+				Capacity:  p.Burst,
+				Rate:      p.Period / time.Duration(p.Limit),
+			}
+		}
+		opts.Policies = append(opts.Policies, policy)
+	}
+	return opts
+}
+
+func corsPolicy(cfg configx.CORSConfig) middleware.CORSPolicy {
+	return middleware.CORSPolicy{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		ExposedHeaders:   cfg.ExposedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"minigo/internal/infrastructure/auth"
+	"minigo/internal/infrastructure/logging"
 	resp "minigo/internal/interfaces/response"
 )
 
@@ -14,32 +15,57 @@ import (
 const (
 	ContextUserIDKey   = "user_id"
 	ContextUserRoleKey = "user_type"
+	ContextClaimsKey   = "auth_claims"
 )
 
-// AuthMiddleware parses JWT and injects user info.
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware parses JWT, rejects revoked tokens and injects user info.
+// revocations 为 nil 时不做吊销检查。
+func AuthMiddleware(tokens *auth.TokenIssuer, revocations auth.RevocationStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		token := BearerToken(c)
+		if token == "" {
 			resp.Error(c, http.StatusUnauthorized, "未提供认证令牌")
 			c.Abort()
 			return
 		}
-		if after, ok := strings.CutPrefix(authHeader, "Bearer "); ok {
-			authHeader = after
-		}
-		claims, err := auth.ParseToken(authHeader)
+		claims, err := tokens.ParseToken(token)
 		if err != nil {
 			resp.Error(c, http.StatusUnauthorized, "无效的认证令牌")
 			c.Abort()
 			return
 		}
+		// 后续日志自动携带 user_id
+		c.Request = c.Request.WithContext(logging.WithField(c.Request.Context(), logging.FieldUserID, claims.UserID))
+		if revocations != nil {
+			revoked, err := revocations.IsRevoked(c.Request.Context(), claims)
+			if err != nil {
+				logging.FromContext(c.Request.Context()).WithError(err).Error("token_revocation_check_failed")
+				resp.Error(c, http.StatusInternalServerError, "系统内部错误")
+				c.Abort()
+				return
+			}
+			if revoked {
+				resp.Error(c, http.StatusUnauthorized, "认证令牌已失效")
+				c.Abort()
+				return
+			}
+		}
+		c.Set(ContextClaimsKey, claims)
 		c.Set(ContextUserIDKey, claims.UserID)
 		c.Set(ContextUserRoleKey, claims.UserRole)
 		c.Next()
 	}
 }
 
+// BearerToken 读取 Authorization 头中的令牌，兼容不带 Bearer 前缀的写法
+func BearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if after, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return after
+	}
+	return header
+}
+
 func GetUserIDFromContext(c *gin.Context) int64 {
 	userIDVal, ok := c.Get(ContextUserIDKey)
 	if !ok {
@@ -48,3 +74,13 @@ func GetUserIDFromContext(c *gin.Context) int64 {
 	userID, _ := userIDVal.(int64)
 	return userID
 }
+
+// GetClaimsFromContext 获取当前请求的令牌声明
+func GetClaimsFromContext(c *gin.Context) *auth.Claims {
+	val, ok := c.Get(ContextClaimsKey)
+	if !ok {
+		return nil
+	}
+	claims, _ := val.(*auth.Claims)
+	return claims
+}
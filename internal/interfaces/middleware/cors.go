@@ -2,93 +2,210 @@ package middleware
 
 import (
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"minigo/pkg/origin"
 )
 
-// CORSMiddleware 跨域资源共享中间件
-func CORSMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
+// CORSPolicy 跨域策略。AllowedOrigins 的格式见 origin.Matcher：精确来源、
+// 子域名通配（https://*.example.com）、以 ^ 开头的正则或 *
+type CORSPolicy struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string // 含 * 时回显预检请求的 Access-Control-Request-Headers
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration // 预检结果缓存时间，0 表示不设置
+}
 
-		// 允许的域名列表，可以根据环境配置
-		allowedOrigins := []string{
-			"http://localhost:3000", // React开发服务器
-			"http://localhost:3001", // 备用前端端口
-			"http://localhost:8080", // 本地测试
-			"http://127.0.0.1:3000", // 本地IP
-			"http://127.0.0.1:3001", // 本地IP备用
-			"http://127.0.0.1:8080", // 本地IP测试
-		}
+// corsRule 编译后的策略，响应头预先拼接好
+type corsRule struct {
+	origins     *origin.Matcher
+	anyOrigin   bool
+	credentials bool
+	anyHeaders  bool
+	methods     string
+	headers     string
+	exposed     string
+	maxAge      string
+}
 
-		// 检查请求来源是否在允许列表中
-		isAllowed := false
-		for _, allowedOrigin := range allowedOrigins {
-			if origin == allowedOrigin {
-				isAllowed = true
-				break
-			}
-		}
+type corsOverride struct {
+	prefix string
+	rule   *corsRule
+}
 
-		// 如果是开发环境，可以允许所有来源（生产环境应该严格控制）
-		if gin.Mode() == gin.DebugMode && origin != "" {
-			isAllowed = true
-		}
+type corsTable struct {
+	base      *corsRule
+	overrides []corsOverride // 按前缀长度降序
+}
 
-		if isAllowed {
-			c.Header("Access-Control-Allow-Origin", origin)
-		}
+// CORSPolicies holds the global CORS policy plus per-path-prefix overrides.
+// 覆盖策略按路径前缀匹配而不是挂在路由组上：预检请求是 OPTIONS，通常没有对应路由，
+// 不会经过路由组中间件。全局策略可在运行时整体替换（配置热更新）。
+type CORSPolicies struct {
+	table atomic.Pointer[corsTable]
+	mu    sync.Mutex // 串行化写操作
+}
 
-		// 设置其他CORS头
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Shop-Domain")
-		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Max-Age", "86400") // 24小时
+func NewCORSPolicies(policy CORSPolicy) (*CORSPolicies, error) {
+	p := &CORSPolicies{}
+	p.table.Store(&corsTable{})
+	if err := p.Set(policy); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
 
-		// 处理预检请求
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
+// Set 原子替换全局策略，覆盖策略保持不变
+func (p *CORSPolicies) Set(policy CORSPolicy) error {
+	rule, err := compileCORSPolicy(policy)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	next := *p.table.Load()
+	next.base = rule
+	p.table.Store(&next)
+	return nil
+}
+
+// Override 为以 prefix 开头的路径设置独立策略，最长前缀优先
+func (p *CORSPolicies) Override(prefix string, policy CORSPolicy) error {
+	rule, err := compileCORSPolicy(policy)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	next := *p.table.Load()
+	overrides := make([]corsOverride, 0, len(next.overrides)+1)
+	for _, o := range next.overrides {
+		if o.prefix != prefix {
+			overrides = append(overrides, o)
 		}
+	}
+	overrides = append(overrides, corsOverride{prefix: prefix, rule: rule})
+	sort.SliceStable(overrides, func(i, j int) bool { return len(overrides[i].prefix) > len(overrides[j].prefix) })
+	next.overrides = overrides
+	p.table.Store(&next)
+	return nil
+}
 
-		c.Next()
+func (p *CORSPolicies) ruleFor(path string) *corsRule {
+	table := p.table.Load()
+	for _, o := range table.overrides {
+		if strings.HasPrefix(path, o.prefix) {
+			return o.rule
+		}
 	}
+	return table.base
 }
 
-// CORSMiddlewareWithConfig 带配置的CORS中间件
-func CORSMiddlewareWithConfig(allowedOrigins []string, allowCredentials bool) gin.HandlerFunc {
+// CORSMiddleware 跨域资源共享中间件，应在其他中间件之前注册以便直接响应预检请求。
+// 来源不被允许时不设置任何 CORS 响应头，由浏览器拒绝。
+func CORSMiddleware(policies *CORSPolicies) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		rule := policies.ruleFor(c.Request.URL.Path)
 		origin := c.Request.Header.Get("Origin")
+		preflight := c.Request.Method == http.MethodOptions &&
+			origin != "" && c.Request.Header.Get("Access-Control-Request-Method") != ""
+
+		header := c.Writer.Header()
+		// 响应随 Origin 变化时必须声明 Vary，否则缓存可能把一个来源的响应返回给另一个来源
+		if !rule.anyOrigin || rule.credentials {
+			header.Add("Vary", "Origin")
+		}
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
 
-		// 检查请求来源是否在允许列表中
-		isAllowed := false
-		for _, allowedOrigin := range allowedOrigins {
-			if origin == allowedOrigin {
-				isAllowed = true
-				break
+		if origin == "" || !rule.allowed(origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusNoContent)
+				return
 			}
+			c.Next()
+			return
 		}
 
-		if isAllowed {
-			c.Header("Access-Control-Allow-Origin", origin)
+		if rule.anyOrigin && !rule.credentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if rule.credentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
 		}
 
-		// 设置其他CORS头
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Shop-Domain")
+		if !preflight {
+			if rule.exposed != "" {
+				header.Set("Access-Control-Expose-Headers", rule.exposed)
+			}
+			c.Next()
+			return
+		}
 
-		if allowCredentials {
-			c.Header("Access-Control-Allow-Credentials", "true")
+		if rule.methods != "" {
+			header.Set("Access-Control-Allow-Methods", rule.methods)
+		}
+		if rule.anyHeaders {
+			if requested := c.Request.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				header.Set("Access-Control-Allow-Headers", requested)
+			}
+		} else if rule.headers != "" {
+			header.Set("Access-Control-Allow-Headers", rule.headers)
 		}
+		if rule.maxAge != "" {
+			header.Set("Access-Control-Max-Age", rule.maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
 
-		c.Header("Access-Control-Max-Age", "86400") // 24小时
+func (r *corsRule) allowed(origin string) bool {
+	return r.origins.Allowed(origin)
+}
 
-		// 处理预检请求
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
+func compileCORSPolicy(policy CORSPolicy) (*corsRule, error) {
+	origins, err := origin.NewMatcher(policy.AllowedOrigins)
+	if err != nil {
+		return nil, err
+	}
+	rule := &corsRule{
+		origins:     origins,
+		anyOrigin:   origins.Any(),
+		credentials: policy.AllowCredentials,
+		exposed:     strings.Join(policy.ExposedHeaders, ", "),
+	}
+
+	methods := make([]string, 0, len(policy.AllowedMethods))
+	for _, m := range policy.AllowedMethods {
+		methods = append(methods, strings.ToUpper(m))
+	}
+	rule.methods = strings.Join(methods, ", ")
+
+	headers := make([]string, 0, len(policy.AllowedHeaders))
+	for _, h := range policy.AllowedHeaders {
+		if h == "*" {
+			rule.anyHeaders = true
+			continue
 		}
+		headers = append(headers, h)
+	}
+	rule.headers = strings.Join(headers, ", ")
 
-		c.Next()
+	if policy.MaxAge > 0 {
+		rule.maxAge = strconv.Itoa(int(policy.MaxAge / time.Second))
 	}
+	return rule, nil
 }
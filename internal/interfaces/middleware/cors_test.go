@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	policies, err := NewCORSPolicies(CORSPolicy{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org", `^https://pr-\d+\.preview\.example\.net$`, `^https://Staging-\d+\.Example\.io$`},
+		AllowedMethods:   []string{"get", "post"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err = policies.Override("/public/", CORSPolicy{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	engine := gin.New()
+	engine.Use(CORSMiddleware(policies))
+	engine.GET("/api/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.GET("/public/keys", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(method, path, origin string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+	preflight := map[string]string{"Access-Control-Request-Method": "POST"}
+
+	t.Run("matches exact, wildcard and pattern origins", func(t *testing.T) {
+		cases := map[string]bool{
+			"https://app.example.com":               true,
+			"https://a.example.org":                 true,
+			"https://a.b.example.org":               true,
+			"https://pr-12.preview.example.net":     true,
+			"https://example.org":                   false,
+			"https://evilexample.org":               false,
+			"http://a.example.org":                  false,
+			"https://pr-x.preview.example.net":      false,
+			"https://app.example.com.evil.io":       false,
+			"https://pr-1.preview.example.net.evil": false,
+			"https://staging-3.example.io":          true,
+			"HTTPS://STAGING-3.EXAMPLE.IO":          true,
+			"https://App.Example.com":               true,
+		}
+		for origin, want := range cases {
+			w := do(http.MethodGet, "/api/items", origin, nil)
+			got := w.Header().Get("Access-Control-Allow-Origin") == origin
+			if got != want {
+				t.Fatalf("Expected %s allowed=%v, got %v", origin, want, got)
+			}
+		}
+	})
+
+	t.Run("answers preflight with configured headers", func(t *testing.T) {
+		w := do(http.MethodOptions, "/api/items", "https://app.example.com", preflight)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("Expected 204, got %d", w.Code)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+			t.Fatalf("Expected methods GET, POST, got %q", got)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type, Authorization" {
+			t.Fatalf("Expected configured headers, got %q", got)
+		}
+		if w.Header().Get("Access-Control-Allow-Credentials") != "true" || w.Header().Get("Access-Control-Max-Age") != "600" {
+			t.Fatalf("Expected credentials and max-age 600, got %v", w.Header())
+		}
+		vary := strings.Join(w.Header().Values("Vary"), ",")
+		for _, h := range []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"} {
+			if !strings.Contains(vary, h) {
+				t.Fatalf("Expected Vary to contain %s, got %q", h, vary)
+			}
+		}
+	})
+
+	t.Run("rejected preflight carries no CORS headers", func(t *testing.T) {
+		w := do(http.MethodOptions, "/api/items", "https://evil.io", preflight)
+		if w.Header().Get("Access-Control-Allow-Origin") != "" || w.Header().Get("Access-Control-Allow-Methods") != "" {
+			t.Fatalf("Expected no CORS headers, got %v", w.Header())
+		}
+		if !strings.Contains(strings.Join(w.Header().Values("Vary"), ","), "Origin") {
+			t.Fatalf("Expected Vary: Origin on rejected preflight")
+		}
+	})
+
+	t.Run("actual request exposes headers and varies on origin", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/items", "https://app.example.com", nil)
+		if w.Header().Get("Access-Control-Expose-Headers") != "X-Request-ID" {
+			t.Fatalf("Expected exposed X-Request-ID, got %q", w.Header().Get("Access-Control-Expose-Headers"))
+		}
+		if w.Header().Get("Vary") != "Origin" {
+			t.Fatalf("Expected Vary: Origin, got %q", w.Header().Get("Vary"))
+		}
+	})
+
+	t.Run("path override allows any origin without credentials", func(t *testing.T) {
+		w := do(http.MethodGet, "/public/keys", "https://anywhere.io", nil)
+		if w.Header().Get("Access-Control-Allow-Origin") != "*" || w.Header().Get("Access-Control-Allow-Credentials") != "" {
+			t.Fatalf("Expected wildcard origin without credentials, got %v", w.Header())
+		}
+		if w.Header().Get("Vary") != "" {
+			t.Fatalf("Expected no Vary for origin-independent response, got %q", w.Header().Get("Vary"))
+		}
+	})
+
+	t.Run("set replaces the global policy", func(t *testing.T) {
+		if err := policies.Set(CORSPolicy{AllowedOrigins: []string{"https://new.example.com"}}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if w := do(http.MethodGet, "/api/items", "https://app.example.com", nil); w.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Fatalf("Expected old origin rejected after Set")
+		}
+		if w := do(http.MethodGet, "/public/keys", "https://app.example.com", nil); w.Header().Get("Access-Control-Allow-Origin") != "*" {
+			t.Fatalf("Expected override kept after Set")
+		}
+	})
+
+	t.Run("rejects malformed patterns", func(t *testing.T) {
+		for _, origin := range []string{"https://a.*.example.com", "^https://(", "https://*.example.com/path"} {
+			if _, err := NewCORSPolicies(CORSPolicy{AllowedOrigins: []string{origin}}); err == nil {
+				t.Fatalf("Expected error for %q", origin)
+			}
+		}
+	})
+}
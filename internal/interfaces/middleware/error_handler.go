@@ -15,16 +15,12 @@ import (
 // ErrorHandlerMiddleware catches panics and returns unified error response.
 func ErrorHandlerMiddleware() gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
-		// 获取请求ID用于日志追踪
-		requestID := c.GetString("request_id")
-
-		// 记录panic详情和堆栈
-		logging.L().WithFields(map[string]interface{}{
-			"request_id": requestID,
-			"panic":      recovered,
-			"stack":      string(debug.Stack()),
-			"path":       c.Request.URL.Path,
-			"method":     c.Request.Method,
+		// 记录panic详情和堆栈，请求ID等字段来自请求上下文
+		logging.FromContext(c.Request.Context()).WithFields(map[string]interface{}{
+			"panic":  recovered,
+			"stack":  string(debug.Stack()),
+			"path":   c.Request.URL.Path,
+			"method": c.Request.Method,
 		}).Error("panic_recovered")
 
 		// 返回统一错误响应
@@ -39,27 +35,24 @@ func HandleError(c *gin.Context, err error) {
 		return
 	}
 
-	requestID := c.GetString("request_id")
+	log := logging.FromContext(c.Request.Context())
 
 	// Check if it's an AppError
 	if appErr, ok := apperrors.AsAppError(err); ok {
 		// 根据错误类型决定日志级别
 		switch appErr.Type {
 		case apperrors.SystemError:
-			logging.L().WithFields(map[string]interface{}{
-				"request_id": requestID,
+			log.WithFields(map[string]interface{}{
 				"error_code": appErr.Code,
 				"error_type": appErr.Type,
 			}).WithError(err).Error("system_error")
 		case apperrors.BusinessError, apperrors.ValidationError:
-			logging.L().WithFields(map[string]interface{}{
-				"request_id": requestID,
+			log.WithFields(map[string]interface{}{
 				"error_code": appErr.Code,
 				"error_type": appErr.Type,
 			}).Warn("business_error")
 		default:
-			logging.L().WithFields(map[string]interface{}{
-				"request_id": requestID,
+			log.WithFields(map[string]interface{}{
 				"error_code": appErr.Code,
 				"error_type": appErr.Type,
 			}).Info("app_error")
@@ -71,10 +64,7 @@ func HandleError(c *gin.Context, err error) {
 	}
 
 	// 处理未知错误
-	logging.L().WithFields(map[string]interface{}{
-		"request_id": requestID,
-		"error":      err.Error(),
-	}).Error("unexpected_error")
+	log.WithError(err).Error("unexpected_error")
 
 	// 返回通用内部错误
 	resp.Error(c, http.StatusInternalServerError, "系统内部错误")
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	resp "minigo/internal/interfaces/response"
+)
+
+// 内置的功能开关
+const (
+	// FeatureSMSLogin 短信验证码登录，默认开启；短信被滥用时可通过热更新关闭
+	FeatureSMSLogin = "sms_login"
+)
+
+// FeatureFlags 当前开启的功能开关（FEATURE_FLAGS），配置热更新时通过 Set 整体替换
+type FeatureFlags struct {
+	enabled atomic.Pointer[map[string]bool]
+}
+
+// NewFeatureFlags 创建功能开关，enabled 为开启的功能名
+func NewFeatureFlags(enabled []string) *FeatureFlags {
+	f := &FeatureFlags{}
+	f.Set(enabled)
+	return f
+}
+
+// Set 替换开启的功能开关
+func (f *FeatureFlags) Set(enabled []string) {
+	flags := make(map[string]bool, len(enabled))
+	for _, name := range enabled {
+		flags[name] = true
+	}
+	f.enabled.Store(&flags)
+}
+
+// Enabled reports whether the feature flag is currently on.
+func (f *FeatureFlags) Enabled(name string) bool {
+	return (*f.enabled.Load())[name]
+}
+
+// RequireFeature 功能未开启时返回 404，路由对客户端表现为不存在
+func RequireFeature(flags *FeatureFlags, name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !flags.Enabled(name) {
+			resp.Error(c, http.StatusNotFound, "接口不存在")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
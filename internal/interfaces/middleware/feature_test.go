@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireFeature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	flags := NewFeatureFlags([]string{FeatureSMSLogin})
+	engine := gin.New()
+	engine.POST("/api/auth/sms/login", RequireFeature(flags, FeatureSMSLogin), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func() int {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/auth/sms/login", nil))
+		return w.Code
+	}
+
+	t.Run("serves the route while the flag is on", func(t *testing.T) {
+		if code := do(); code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", code)
+		}
+	})
+
+	t.Run("hides the route once a reload turns the flag off", func(t *testing.T) {
+		flags.Set([]string{"other"})
+		if code := do(); code != http.StatusNotFound {
+			t.Fatalf("Expected 404, got %d", code)
+		}
+		if flags.Enabled(FeatureSMSLogin) {
+			t.Fatal("Expected sms_login disabled")
+		}
+		flags.Set([]string{"other", FeatureSMSLogin})
+		if code := do(); code != http.StatusOK {
+			t.Fatalf("Expected 200 after turning the flag back on, got %d", code)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"minigo/internal/infrastructure/metrics"
+)
+
+// unmatchedRoute 未匹配路由统一归为一个标签值，避免路径作为标签导致基数爆炸
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware records per-route request count, latency and in-flight requests.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := routeLabel(c)
+		method := c.Request.Method
+
+		inFlight := metrics.HTTPRequestsInFlight.WithLabelValues(method, route)
+		inFlight.Inc()
+		start := time.Now()
+
+		c.Next()
+
+		inFlight.Dec()
+		metrics.HTTPRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		metrics.HTTPRequestsTotal.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+	}
+}
+
+// routeLabel 返回路由模板（如 /api/admin/users/:id）
+func routeLabel(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return unmatchedRoute
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	resp "minigo/internal/interfaces/response"
+)
+
+// CodePasswordExpired 密码过期时访问受限接口返回的业务码
+const CodePasswordExpired = "PWD_009"
+
+// PasswordExpiry 令牌带 pwdExpired 声明（密码超过 PASSWORD_MAX_AGE）时，只放行 allowed 中的路由，
+// 其他请求返回 403，客户端需先修改密码并重新登录。需放在 AuthMiddleware 之后。
+func PasswordExpiry(allowed ...string) gin.HandlerFunc {
+	allow := make(map[string]bool, len(allowed))
+	for _, route := range allowed {
+		allow[route] = true
+	}
+	return func(c *gin.Context) {
+		claims := GetClaimsFromContext(c)
+		if claims == nil || !claims.PasswordExpired || allow[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, resp.Response{
+			Success: false,
+			Code:    CodePasswordExpired,
+			Message: "密码已过期，请先修改密码",
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"minigo/internal/infrastructure/auth"
+)
+
+func TestPasswordExpiry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	claims := &auth.Claims{UserID: 1}
+
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) { c.Set(ContextClaimsKey, claims) })
+	engine.Use(PasswordExpiry("PUT /api/auth/password"))
+	engine.PUT("/api/auth/password", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.GET("/api/auth/password", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.GET("/api/orders/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	t.Run("passes tokens without the claim", func(t *testing.T) {
+		if w := do(http.MethodGet, "/api/orders/1"); w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("only allows listed routes once expired", func(t *testing.T) {
+		claims.PasswordExpired = true
+		if w := do(http.MethodPut, "/api/auth/password"); w.Code != http.StatusOK {
+			t.Fatalf("Expected 200 for password change, got %d", w.Code)
+		}
+		for _, path := range []string{"/api/orders/1", "/api/auth/password"} {
+			w := do(http.MethodGet, path)
+			if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), CodePasswordExpired) {
+				t.Fatalf("Expected 403 %s for GET %s, got %d %s", CodePasswordExpired, path, w.Code, w.Body.String())
+			}
+		}
+	})
+}
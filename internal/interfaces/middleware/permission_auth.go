@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"minigo/internal/infrastructure/logging"
+	resp "minigo/internal/interfaces/response"
+)
+
+// PermissionResolver 按用户解析权限编码（通常带缓存）
+type PermissionResolver interface {
+	GetUserPermissions(ctx context.Context, userID int64) ([]string, error)
+}
+
+// RequirePermission 要求当前用户拥有全部指定权限
+// 优先使用令牌中的 perms 声明，未命中时再通过 resolver 查询；resolver 为 nil 时只看令牌。
+func RequirePermission(resolver PermissionResolver, permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := GetClaimsFromContext(c)
+		if claims == nil {
+			resp.Error(c, http.StatusUnauthorized, "未认证的请求")
+			c.Abort()
+			return
+		}
+
+		var resolved map[string]bool
+		for _, perm := range permissions {
+			if claims.HasPermission(perm) {
+				continue
+			}
+			if resolved == nil {
+				resolved = make(map[string]bool)
+				if resolver != nil {
+					codes, err := resolver.GetUserPermissions(c.Request.Context(), claims.UserID)
+					if err != nil {
+						logging.FromContext(c.Request.Context()).WithError(err).Error("permission_resolve_failed")
+						resp.Error(c, http.StatusInternalServerError, "系统内部错误")
+						c.Abort()
+						return
+					}
+					for _, code := range codes {
+						resolved[code] = true
+					}
+				}
+			}
+			if !resolved[perm] {
+				resp.Error(c, http.StatusForbidden, "权限不足")
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"minigo/internal/infrastructure/auth"
+)
+
+// staticPermissions 固定返回给定权限的解析器
+type staticPermissions []string
+
+func (p staticPermissions) GetUserPermissions(ctx context.Context, userID int64) ([]string, error) {
+	return p, nil
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newEngine := func(claims *auth.Claims, resolver PermissionResolver) *gin.Engine {
+		engine := gin.New()
+		engine.Use(func(c *gin.Context) { c.Set(ContextClaimsKey, claims) })
+		engine.GET("/users", RequirePermission(resolver, "user:read"), func(c *gin.Context) { c.Status(http.StatusOK) })
+		return engine
+	}
+	do := func(engine *gin.Engine) int {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+		return w.Code
+	}
+
+	t.Run("uses the permissions in the token", func(t *testing.T) {
+		if code := do(newEngine(&auth.Claims{UserID: 1, Permissions: []string{"user:read"}}, nil)); code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", code)
+		}
+	})
+
+	t.Run("each router uses its own resolver", func(t *testing.T) {
+		claims := &auth.Claims{UserID: 1}
+		allowed := newEngine(claims, staticPermissions{"user:read"})
+		denied := newEngine(claims, staticPermissions{"role:read"})
+		if code := do(allowed); code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", code)
+		}
+		if code := do(denied); code != http.StatusForbidden {
+			t.Fatalf("Expected 403, got %d", code)
+		}
+		if code := do(newEngine(claims, nil)); code != http.StatusForbidden {
+			t.Fatalf("Expected 403 without a resolver, got %d", code)
+		}
+	})
+}
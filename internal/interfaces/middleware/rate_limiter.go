@@ -1,224 +1,310 @@
 package middleware
 
 import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
-	"sync"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"minigo/internal/infrastructure/auth"
+	"minigo/internal/infrastructure/logging"
+	"minigo/internal/infrastructure/metrics"
+	"minigo/internal/infrastructure/ratelimit"
 	resp "minigo/internal/interfaces/response"
 )
 
-// RateLimiter 限流器接口
-type RateLimiter interface {
-	Allow(key string) bool
-	Reset(key string)
+// maxKeyBodyBytes 从请求体提取限流键时最多解析的字节数
+const maxKeyBodyBytes = 64 << 10
+
+// globalPolicyName 按客户端 IP 的全局限流在指标和日志中的名称
+const globalPolicyName = "global"
+
+// RateLimitPolicy 按路由的限流策略
+type RateLimitPolicy struct {
+	Name        string
+	Method      string          // 为空匹配任意方法
+	Path        string          // 路由模板（c.FullPath()），以 * 结尾时按前缀匹配
+	Limit       ratelimit.Limit // Concurrency 为 0 时使用
+	Concurrency int             // 大于 0 时限制同时处理中的请求数
+	Key         []string        // ip、user、header.<Name>、query.<name>、body.<field>
 }
 
-// TokenBucket 令牌桶限流器
-type TokenBucket struct {
-	capacity int           // 桶容量
-	tokens   int           // 当前令牌数
-	rate     time.Duration // 令牌生成速率
-	lastTime time.Time     // 上次更新时间
-	mutex    sync.Mutex
+// RateLimitOptions 限流配置，可通过 RateLimits.Set 整体替换
+type RateLimitOptions struct {
+	Global        ratelimit.Limit // 按客户端 IP 的全局限流，Capacity 为 0 时不启用
+	Policies      []RateLimitPolicy
+	ExemptIPs     []string // IP 或 CIDR
+	ExemptAPIKeys []string // 通过 X-API-Key 请求头匹配
 }
 
-// NewTokenBucket 创建令牌桶
-func NewTokenBucket(capacity int, rate time.Duration) *TokenBucket {
-	return &TokenBucket{
-		capacity: capacity,
-		tokens:   capacity,
-		rate:     rate,
-		lastTime: time.Now(),
-	}
+type rateLimitState struct {
+	global        ratelimit.Limit
+	policies      []RateLimitPolicy
+	exemptNets    []*net.IPNet
+	exemptAPIKeys []string
 }
 
-// Allow 检查是否允许请求
-func (tb *TokenBucket) Allow(key string) bool {
-	tb.mutex.Lock()
-	defer tb.mutex.Unlock()
+// RateLimits applies the global per-IP limit and the per-route policies.
+// 每个策略使用独立的键前缀，互不影响；限流参数可在运行时整体替换（配置热更新），
+// 并发限制只统计本进程内的请求。
+type RateLimits struct {
+	store       ratelimit.RateLimiter
+	tokens      *auth.TokenIssuer // 解析 user 键的访问令牌，nil 时只使用已认证的用户
+	concurrency *ratelimit.ConcurrencyLimiter
+	state       atomic.Pointer[rateLimitState]
+}
 
-	now := time.Now()
-	// 计算应该添加的令牌数
-	elapsed := now.Sub(tb.lastTime)
-	tokensToAdd := int(elapsed / tb.rate)
+func NewRateLimits(store ratelimit.RateLimiter, tokens *auth.TokenIssuer, opts RateLimitOptions) (*RateLimits, error) {
+	r := &RateLimits{store: store, tokens: tokens, concurrency: ratelimit.NewConcurrencyLimiter()}
+	if err := r.Set(opts); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
 
-	if tokensToAdd > 0 {
-		tb.tokens += tokensToAdd
-		if tb.tokens > tb.capacity {
-			tb.tokens = tb.capacity
+// Set 原子替换限流配置，已有的限流状态保留
+func (r *RateLimits) Set(opts RateLimitOptions) error {
+	state := &rateLimitState{
+		global:        opts.Global,
+		policies:      append([]RateLimitPolicy{}, opts.Policies...),
+		exemptAPIKeys: append([]string{}, opts.ExemptAPIKeys...),
+	}
+	if opts.Global.Capacity > 0 {
+		if err := opts.Global.Validate(); err != nil {
+			return fmt.Errorf("global rate limit: %w", err)
 		}
-		tb.lastTime = now
 	}
-
-	if tb.tokens > 0 {
-		tb.tokens--
-		return true
+	for _, p := range opts.Policies {
+		if p.Concurrency > 0 {
+			continue
+		}
+		if err := p.Limit.Validate(); err != nil {
+			return fmt.Errorf("rate limit policy %s: %w", p.Name, err)
+		}
 	}
-	return false
+	for _, value := range opts.ExemptIPs {
+		if !strings.Contains(value, "/") {
+			if ip := net.ParseIP(value); ip != nil && ip.To4() != nil {
+				value += "/32"
+			} else {
+				value += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return fmt.Errorf("invalid exempt IP %q", value)
+		}
+		state.exemptNets = append(state.exemptNets, ipNet)
+	}
+	r.state.Store(state)
+	return nil
 }
 
-// Reset 重置令牌桶
-func (tb *TokenBucket) Reset(key string) {
-	tb.mutex.Lock()
-	defer tb.mutex.Unlock()
-	tb.tokens = tb.capacity
-	tb.lastTime = time.Now()
-}
+// RateLimitMiddleware 依次执行全局限流和匹配当前路由的全部策略，任一拒绝即返回 429。
+// 响应头反映剩余次数最少的限流；存储不可用时放行请求，避免限流组件故障导致整个服务不可用。
+func RateLimitMiddleware(limits *RateLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state := limits.state.Load()
+		if state.exempt(c) {
+			c.Next()
+			return
+		}
 
-// RateLimiterManager 限流器管理器
-type RateLimiterManager struct {
-	limiters map[string]*TokenBucket
-	mutex    sync.RWMutex
-	capacity int
-	rate     time.Duration
-}
+		var tightest *ratelimit.Result
+		record := func(res ratelimit.Result) {
+			if tightest == nil || res.Remaining < tightest.Remaining {
+				tightest = &res
+			}
+		}
 
-// NewRateLimiterManager 创建限流器管理器
-func NewRateLimiterManager(capacity int, rate time.Duration) *RateLimiterManager {
-	return &RateLimiterManager{
-		limiters: make(map[string]*TokenBucket),
-		capacity: capacity,
-		rate:     rate,
-	}
-}
+		if state.global.Capacity > 0 {
+			res, ok := limits.allow(c, globalPolicyName, "ip:"+c.ClientIP(), state.global)
+			if ok && !res.Allowed {
+				rejectRateLimited(c, globalPolicyName, res)
+				return
+			}
+			if ok {
+				record(res)
+			}
+		}
 
-// GetLimiter 获取或创建限流器
-func (rlm *RateLimiterManager) GetLimiter(key string) *TokenBucket {
-	rlm.mutex.RLock()
-	limiter, exists := rlm.limiters[key]
-	rlm.mutex.RUnlock()
+		keys := &keySource{c: c, tokens: limits.tokens}
+		for _, p := range state.policies {
+			if !p.matches(c) {
+				continue
+			}
+			key := p.Name + ":" + keys.build(p.Key)
+			if p.Concurrency > 0 {
+				release, res := limits.concurrency.Acquire(key, p.Concurrency)
+				if !res.Allowed {
+					res.RetryAfter = time.Second
+					rejectRateLimited(c, p.Name, res)
+					return
+				}
+				defer release()
+				continue
+			}
+			res, ok := limits.allow(c, p.Name, key, p.Limit)
+			if ok && !res.Allowed {
+				rejectRateLimited(c, p.Name, res)
+				return
+			}
+			if ok {
+				record(res)
+			}
+		}
 
-	if exists {
-		return limiter
+		if tightest != nil {
+			setRateLimitHeaders(c, *tightest)
+		}
+		c.Next()
 	}
+}
 
-	rlm.mutex.Lock()
-	defer rlm.mutex.Unlock()
-
-	// 双重检查
-	if limiter, exists = rlm.limiters[key]; exists {
-		return limiter
+func (r *RateLimits) allow(c *gin.Context, policy, key string, limit ratelimit.Limit) (ratelimit.Result, bool) {
+	res, err := r.store.Allow(c.Request.Context(), key, limit)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).WithError(err).WithField("policy", policy).Warn("rate_limit_store_error")
+		return res, false
 	}
-
-	limiter = NewTokenBucket(rlm.capacity, rlm.rate)
-	rlm.limiters[key] = limiter
-	return limiter
+	return res, true
 }
 
-// Allow 检查是否允许请求
-func (rlm *RateLimiterManager) Allow(key string) bool {
-	limiter := rlm.GetLimiter(key)
-	return limiter.Allow(key)
+func rejectRateLimited(c *gin.Context, policy string, res ratelimit.Result) {
+	setRateLimitHeaders(c, res)
+	c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+	metrics.RateLimitRejections.WithLabelValues(routeLabel(c), policy).Inc()
+	resp.Error(c, http.StatusTooManyRequests, "请求过于频繁，请稍后再试")
+	c.Abort()
 }
 
-// Reset 重置限流器
-func (rlm *RateLimiterManager) Reset(key string) {
-	rlm.mutex.RLock()
-	limiter, exists := rlm.limiters[key]
-	rlm.mutex.RUnlock()
-
-	if exists {
-		limiter.Reset(key)
-	}
+func setRateLimitHeaders(c *gin.Context, res ratelimit.Result) {
+	c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.ResetAfter)))
 }
 
-// CleanupExpired 清理过期的限流器
-func (rlm *RateLimiterManager) CleanupExpired() {
-	rlm.mutex.Lock()
-	defer rlm.mutex.Unlock()
+// ceilSeconds 响应头以整秒表示，向上取整避免客户端过早重试
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
 
-	now := time.Now()
-	for key, limiter := range rlm.limiters {
-		// 如果限流器超过1小时没有使用，则删除
-		if now.Sub(limiter.lastTime) > time.Hour {
-			delete(rlm.limiters, key)
+func (s *rateLimitState) exempt(c *gin.Context) bool {
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		for _, k := range s.exemptAPIKeys {
+			if subtle.ConstantTimeCompare([]byte(apiKey), []byte(k)) == 1 {
+				return true
+			}
+		}
+	}
+	if len(s.exemptNets) == 0 {
+		return false
+	}
+	ip := net.ParseIP(c.ClientIP())
+	for _, n := range s.exemptNets {
+		if ip != nil && n.Contains(ip) {
+			return true
 		}
 	}
+	return false
 }
 
-// 全局限流器管理器
-var (
-	globalRateLimiter *RateLimiterManager
-	once              sync.Once
-)
-
-// GetGlobalRateLimiter 获取全局限流器
-func GetGlobalRateLimiter() *RateLimiterManager {
-	once.Do(func() {
-		// 默认配置：每秒100个请求，桶容量200
-		globalRateLimiter = NewRateLimiterManager(200, 10*time.Millisecond)
-
-		// 启动清理协程
-		go func() {
-			ticker := time.NewTicker(10 * time.Minute)
-			defer ticker.Stop()
-			for range ticker.C {
-				globalRateLimiter.CleanupExpired()
-			}
-		}()
-	})
-	return globalRateLimiter
+func (p RateLimitPolicy) matches(c *gin.Context) bool {
+	if p.Method != "" && p.Method != c.Request.Method {
+		return false
+	}
+	route := c.FullPath()
+	if route == "" {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(p.Path, "*"); ok {
+		return strings.HasPrefix(route, prefix)
+	}
+	return route == p.Path
 }
 
-// RateLimitMiddleware 限流中间件
-func RateLimitMiddleware(keyFunc func(*gin.Context) string) gin.HandlerFunc {
-	limiter := GetGlobalRateLimiter()
+// keySource 按请求提取限流键，请求体只解析一次并原样放回供后续处理器读取
+type keySource struct {
+	c      *gin.Context
+	tokens *auth.TokenIssuer
+	body   map[string]interface{}
+	parsed bool
+}
 
-	return func(c *gin.Context) {
-		key := keyFunc(c)
-		if !limiter.Allow(key) {
-			resp.Error(c, http.StatusTooManyRequests, "请求过于频繁，请稍后再试")
-			c.Abort()
-			return
+// build 拼接各部分的值。header、query、body 取不到值（请求体不是 JSON、超过 maxKeyBodyBytes 或缺少字段）时
+// 改为按客户端 IP 限流，避免这类请求共用同一个桶
+func (k *keySource) build(parts []string) string {
+	values := make([]string, len(parts))
+	for i, part := range parts {
+		values[i] = k.value(part)
+		if values[i] == "" {
+			return "ip:" + k.c.ClientIP()
 		}
-		c.Next()
 	}
+	return strings.Join(values, "|")
 }
 
-// IPBasedRateLimitMiddleware 基于IP的限流中间件
-func IPBasedRateLimitMiddleware() gin.HandlerFunc {
-	return RateLimitMiddleware(func(c *gin.Context) string {
-		return c.ClientIP()
-	})
-}
-
-// UserBasedRateLimitMiddleware 基于用户的限流中间件
-func UserBasedRateLimitMiddleware() gin.HandlerFunc {
-	return RateLimitMiddleware(func(c *gin.Context) string {
-		userID, exists := c.Get(ContextUserIDKey)
-		if !exists {
-			return c.ClientIP() // 如果没有用户信息，使用IP
+func (k *keySource) value(part string) string {
+	source, name, _ := strings.Cut(part, ".")
+	switch source {
+	case "ip":
+		return k.c.ClientIP()
+	case "user":
+		return k.user()
+	case "header":
+		return k.c.GetHeader(name)
+	case "query":
+		return k.c.Query(name)
+	case "body":
+		if value, ok := k.json()[name]; ok && value != nil {
+			return fmt.Sprint(value)
 		}
-		return fmt.Sprintf("user_%d", userID.(int64))
-	})
+	}
+	return ""
 }
 
-// APIKeyBasedRateLimitMiddleware 基于API Key的限流中间件
-func APIKeyBasedRateLimitMiddleware() gin.HandlerFunc {
-	return RateLimitMiddleware(func(c *gin.Context) string {
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey == "" {
-			return c.ClientIP()
+// user 限流在认证中间件之前执行，未注入用户时从令牌中解析，匿名请求按 IP 区分
+func (k *keySource) user() string {
+	if userID := GetUserIDFromContext(k.c); userID != 0 {
+		return strconv.FormatInt(userID, 10)
+	}
+	token, ok := strings.CutPrefix(k.c.GetHeader("Authorization"), "Bearer ")
+	if ok && k.tokens != nil {
+		if claims, err := k.tokens.ParseToken(token); err == nil {
+			return strconv.FormatInt(claims.UserID, 10)
 		}
-		return fmt.Sprintf("api_%s", apiKey)
-	})
+	}
+	return "anon:" + k.c.ClientIP()
 }
 
-// CustomRateLimitMiddleware 自定义限流中间件
-func CustomRateLimitMiddleware(capacity int, rate time.Duration, keyFunc func(*gin.Context) string) gin.HandlerFunc {
-	limiter := NewRateLimiterManager(capacity, rate)
-
-	return func(c *gin.Context) {
-		key := keyFunc(c)
-		if !limiter.Allow(key) {
-			resp.Error(c, http.StatusTooManyRequests, "请求过于频繁，请稍后再试")
-			c.Abort()
-			return
-		}
-		c.Next()
+func (k *keySource) json() map[string]interface{} {
+	if k.parsed {
+		return k.body
 	}
+	k.parsed = true
+	req := k.c.Request
+	if req.Body == nil || !strings.HasPrefix(k.c.ContentType(), "application/json") {
+		return nil
+	}
+	head, err := io.ReadAll(io.LimitReader(req.Body, maxKeyBodyBytes))
+	req.Body = readCloser{io.MultiReader(bytes.NewReader(head), req.Body), req.Body}
+	if err == nil {
+		_ = json.Unmarshal(head, &k.body)
+	}
+	return k.body
+}
+
+// readCloser 读取已缓冲的请求体前缀和剩余部分，关闭时关闭原请求体
+type readCloser struct {
+	io.Reader
+	io.Closer
 }
@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"minigo/internal/infrastructure/ratelimit"
+)
+
+type failingStore struct{}
+
+func (failingStore) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+func (failingStore) Reset(ctx context.Context, key string) error { return nil }
+
+func newRateLimitEngine(t *testing.T, store ratelimit.RateLimiter, opts RateLimitOptions, handler gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	limits, err := NewRateLimits(store, nil, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	engine := gin.New()
+	engine.Use(RateLimitMiddleware(limits))
+	engine.GET("/", handler)
+	engine.POST("/login", handler)
+	return engine
+}
+
+func serveRequest(engine *gin.Engine, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	global := ratelimit.Limit{Capacity: 1, Rate: time.Minute}
+
+	t.Run("sets standard headers and rejects with retry-after", func(t *testing.T) {
+		engine := newRateLimitEngine(t, ratelimit.NewMemoryStore(), RateLimitOptions{Global: global}, ok)
+		w := serveRequest(engine, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "1" || w.Header().Get("RateLimit-Remaining") != "0" {
+			t.Fatalf("Expected 200 with limit 1 and 0 remaining, got %d %v", w.Code, w.Header())
+		}
+		w = serveRequest(engine, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected 429, got %d", w.Code)
+		}
+		if w.Header().Get("Retry-After") != "60" || w.Header().Get("RateLimit-Reset") != "60" {
+			t.Fatalf("Expected Retry-After and RateLimit-Reset of 60, got %v", w.Header())
+		}
+	})
+
+	t.Run("skips the store for routes without a policy when the global limit is off", func(t *testing.T) {
+		engine := newRateLimitEngine(t, failingStore{}, RateLimitOptions{Policies: []RateLimitPolicy{{
+			Name:   "login",
+			Method: http.MethodPost,
+			Path:   "/login",
+			Limit:  ratelimit.Limit{Capacity: 1, Rate: time.Minute},
+			Key:    []string{"ip"},
+		}}}, ok)
+		w := serveRequest(engine, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "" {
+			t.Fatalf("Expected 200 without rate limit headers, got %d %v", w.Code, w.Header())
+		}
+	})
+
+	t.Run("allows requests when the store fails", func(t *testing.T) {
+		engine := newRateLimitEngine(t, failingStore{}, RateLimitOptions{Global: global}, ok)
+		if w := serveRequest(engine, httptest.NewRequest(http.MethodGet, "/", nil)); w.Code != http.StatusOK {
+			t.Fatalf("Expected 200 when store is unavailable, got %d", w.Code)
+		}
+	})
+
+	t.Run("keys policies by body field and restores the body", func(t *testing.T) {
+		var bodies []string
+		engine := newRateLimitEngine(t, ratelimit.NewMemoryStore(), RateLimitOptions{
+			Policies: []RateLimitPolicy{{
+				Name:   "login",
+				Method: http.MethodPost,
+				Path:   "/login",
+				Limit:  ratelimit.Limit{Algorithm: ratelimit.SlidingWindow, Capacity: 1, Window: time.Minute},
+				Key:    []string{"ip", "body.phone"},
+			}},
+		}, func(c *gin.Context) {
+			body, _ := io.ReadAll(c.Request.Body)
+			bodies = append(bodies, string(body))
+			c.Status(http.StatusOK)
+		})
+		login := func(phone string) int {
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"phone":"`+phone+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+			return serveRequest(engine, req).Code
+		}
+
+		if code := login("13800000000"); code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", code)
+		}
+		if code := login("13800000000"); code != http.StatusTooManyRequests {
+			t.Fatalf("Expected 429 for the same phone, got %d", code)
+		}
+		if code := login("13900000000"); code != http.StatusOK {
+			t.Fatalf("Expected 200 for another phone, got %d", code)
+		}
+		if len(bodies) != 2 || bodies[0] != `{"phone":"13800000000"}` {
+			t.Fatalf("Expected handler to read the original body, got %v", bodies)
+		}
+		if w := serveRequest(engine, httptest.NewRequest(http.MethodGet, "/", nil)); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "" {
+			t.Fatalf("Expected unmatched route without rate limit headers, got %d %v", w.Code, w.Header())
+		}
+	})
+
+	t.Run("falls back to the client IP when a key part is missing", func(t *testing.T) {
+		engine := newRateLimitEngine(t, ratelimit.NewMemoryStore(), RateLimitOptions{
+			Policies: []RateLimitPolicy{{
+				Name:  "login",
+				Path:  "/login",
+				Limit: ratelimit.Limit{Algorithm: ratelimit.SlidingWindow, Capacity: 1, Window: time.Minute},
+				Key:   []string{"body.phone"},
+			}},
+		}, ok)
+		login := func(ip, contentType, body string) int {
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+			req.Header.Set("Content-Type", contentType)
+			req.RemoteAddr = ip + ":1234"
+			return serveRequest(engine, req).Code
+		}
+
+		if code := login("10.0.0.1", "text/plain", "phone=13800000000"); code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", code)
+		}
+		if code := login("10.0.0.2", "application/json", "{not json"); code != http.StatusOK {
+			t.Fatalf("Expected 200 for another IP without a phone, got %d", code)
+		}
+		if code := login("10.0.0.3", "application/json", `{"name":"a"}`); code != http.StatusOK {
+			t.Fatalf("Expected 200 for another IP without a phone, got %d", code)
+		}
+		if code := login("10.0.0.1", "application/json", `{}`); code != http.StatusTooManyRequests {
+			t.Fatalf("Expected 429 for the same IP without a phone, got %d", code)
+		}
+	})
+
+	t.Run("limits concurrent requests", func(t *testing.T) {
+		var engine *gin.Engine
+		var nested int
+		engine = newRateLimitEngine(t, ratelimit.NewMemoryStore(), RateLimitOptions{
+			Policies: []RateLimitPolicy{{Name: "export", Path: "/*", Concurrency: 1, Key: []string{"ip"}}},
+		}, func(c *gin.Context) {
+			// 处理中再发起一个请求，模拟并发
+			if c.Request.Method == http.MethodGet {
+				nested = serveRequest(engine, httptest.NewRequest(http.MethodPost, "/login", nil)).Code
+			}
+			c.Status(http.StatusOK)
+		})
+		if w := serveRequest(engine, httptest.NewRequest(http.MethodGet, "/", nil)); w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", w.Code)
+		}
+		if nested != http.StatusTooManyRequests {
+			t.Fatalf("Expected concurrent request rejected with 429, got %d", nested)
+		}
+		if w := serveRequest(engine, httptest.NewRequest(http.MethodPost, "/login", nil)); w.Code != http.StatusOK {
+			t.Fatalf("Expected slot released after the request, got %d", w.Code)
+		}
+	})
+
+	t.Run("exempts allowlisted IPs and API keys", func(t *testing.T) {
+		engine := newRateLimitEngine(t, ratelimit.NewMemoryStore(), RateLimitOptions{
+			Global:        global,
+			ExemptIPs:     []string{"10.0.0.0/8"},
+			ExemptAPIKeys: []string{"partner-key"},
+		}, ok)
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "10.1.2.3:1234"
+			if w := serveRequest(engine, req); w.Code != http.StatusOK {
+				t.Fatalf("Expected exempt IP allowed, got %d", w.Code)
+			}
+			req = httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-API-Key", "partner-key")
+			if w := serveRequest(engine, req); w.Code != http.StatusOK {
+				t.Fatalf("Expected exempt API key allowed, got %d", w.Code)
+			}
+		}
+	})
+
+	t.Run("rejects invalid options", func(t *testing.T) {
+		_, err := NewRateLimits(ratelimit.NewMemoryStore(), nil, RateLimitOptions{ExemptIPs: []string{"not-an-ip"}})
+		if err == nil {
+			t.Fatal("Expected error for invalid exempt IP")
+		}
+		_, err = NewRateLimits(ratelimit.NewMemoryStore(), nil, RateLimitOptions{
+			Policies: []RateLimitPolicy{{Name: "bad", Path: "/", Limit: ratelimit.Limit{Capacity: 1}}},
+		})
+		if err == nil {
+			t.Fatal("Expected error for policy without rate")
+		}
+	})
+}
@@ -3,6 +3,9 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"minigo/internal/infrastructure/logging"
 )
 
 const (
@@ -25,6 +28,11 @@ func RequestIDMiddleware() gin.HandlerFunc {
 
 		// 将请求ID存入上下文
 		c.Set(RequestIDKey, requestID)
+		// 请求上下文中的日志字段，服务和仓储通过 logging.FromContext 读取
+		c.Request = c.Request.WithContext(logging.WithFields(c.Request.Context(), logrus.Fields{
+			logging.FieldRequestID: requestID,
+			logging.FieldRoute:     routeLabel(c),
+		}))
 
 		// 将请求ID写入响应头
 		c.Header(RequestIDHeader, requestID)
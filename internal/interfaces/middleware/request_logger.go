@@ -8,14 +8,15 @@ import (
 	"minigo/internal/infrastructure/logging"
 )
 
-// RequestLoggerMiddleware logs basic request info using logrus.
+// RequestLoggerMiddleware logs basic request info to the access log.
 func RequestLoggerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		c.Next()
 		lat := time.Since(start)
 		status := c.Writer.Status()
-		logging.L().WithFields(map[string]interface{}{
+		// request_id、route、user_id、trace_id 来自请求上下文
+		logging.AccessFromContext(c.Request.Context()).WithFields(map[string]interface{}{
 			"method": c.Request.Method,
 			"path":   c.Request.URL.Path,
 			"status": status,
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"minigo/internal/infrastructure/tracing"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewTracerProvider(sdktrace.WithSyncer(exporter), "test", 1)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	engine := gin.New()
+	engine.Use(RequestIDMiddleware(), TracingMiddleware())
+	engine.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	t.Run("continues incoming trace and names span by route", func(t *testing.T) {
+		exporter.Reset()
+		const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+		req.Header.Set(RequestIDHeader, "req-1")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		spans := exporter.GetSpans()
+		if len(spans) != 1 {
+			t.Fatalf("Expected 1 span, got %d", len(spans))
+		}
+		if spans[0].Name != "GET /users/:id" {
+			t.Fatalf("Expected span named by route template, got %q", spans[0].Name)
+		}
+		if got := spans[0].SpanContext.TraceID().String(); got != traceID {
+			t.Fatalf("Expected trace ID %s, got %s", traceID, got)
+		}
+		if w.Header().Get(TraceIDHeader) != traceID {
+			t.Fatalf("Expected %s header %s, got %q", TraceIDHeader, traceID, w.Header().Get(TraceIDHeader))
+		}
+		if w.Header().Get("traceparent") == "" {
+			t.Fatalf("Expected traceparent response header")
+		}
+		var requestID string
+		for _, attr := range spans[0].Attributes {
+			if attr.Key == "request_id" {
+				requestID = attr.Value.AsString()
+			}
+		}
+		if requestID != "req-1" {
+			t.Fatalf("Expected request_id attribute req-1, got %q", requestID)
+		}
+	})
+
+	t.Run("starts a new trace without traceparent", func(t *testing.T) {
+		exporter.Reset()
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+		spans := exporter.GetSpans()
+		if len(spans) != 1 || spans[0].Parent.IsValid() {
+			t.Fatalf("Expected a single root span, got %+v", spans)
+		}
+	})
+}
@@ -10,10 +10,11 @@ import (
 
 // Response 统一响应结构
 type Response struct {
-	Success bool        `json:"success"`
-	Code    string      `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Success bool                   `json:"success"`
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Data    interface{}            `json:"data,omitempty"`
+	Errors  []apperrors.FieldError `json:"errors,omitempty"` // 字段级错误
 }
 
 // PageData 分页数据结构
@@ -84,6 +85,7 @@ func ErrorFromAppError(c *gin.Context, err *apperrors.AppError) {
 		Success: false,
 		Code:    err.Code,
 		Message: err.Message,
+		Errors:  err.Fields,
 	})
 }
 
@@ -116,6 +118,8 @@ func statusToCode(status int) string {
 		return "NOT_FOUND"
 	case http.StatusConflict:
 		return "CONFLICT"
+	case http.StatusTooManyRequests:
+		return "TOO_MANY_REQUESTS"
 	case http.StatusInternalServerError:
 		return "INTERNAL_ERROR"
 	default:
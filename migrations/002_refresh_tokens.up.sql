@@ -0,0 +1,24 @@
+-- 刷新令牌表（支持轮换与重放检测）
+CREATE TABLE "refresh_tokens" (
+    id                  BIGINT PRIMARY KEY,
+    user_id             BIGINT NOT NULL,
+    family_id           VARCHAR(64) NOT NULL,
+    token_hash          VARCHAR(64) NOT NULL,
+    expires_at          TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at             TIMESTAMP WITH TIME ZONE,
+    revoked_at          TIMESTAMP WITH TIME ZONE,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX uk_refresh_tokens_token_hash ON "refresh_tokens"(token_hash);
+CREATE INDEX idx_refresh_tokens_family_id ON "refresh_tokens"(family_id);
+CREATE INDEX idx_refresh_tokens_user_id ON "refresh_tokens"(user_id);
+
+COMMENT ON TABLE "refresh_tokens" IS '刷新令牌表（每次刷新轮换，同一登录会话共享family_id）';
+COMMENT ON COLUMN "refresh_tokens".id IS '令牌ID（Snowflake生成）';
+COMMENT ON COLUMN "refresh_tokens".user_id IS '所属用户ID';
+COMMENT ON COLUMN "refresh_tokens".family_id IS '令牌族ID（一次登录产生的所有刷新令牌共享）';
+COMMENT ON COLUMN "refresh_tokens".token_hash IS '令牌SHA-256哈希（不保存明文）';
+COMMENT ON COLUMN "refresh_tokens".expires_at IS '过期时间';
+COMMENT ON COLUMN "refresh_tokens".used_at IS '使用时间（已轮换的令牌再次出现即视为重放）';
+COMMENT ON COLUMN "refresh_tokens".revoked_at IS '吊销时间';
@@ -0,0 +1,24 @@
+-- 访问令牌吊销表
+CREATE TABLE "revoked_tokens" (
+    jti                 VARCHAR(64) PRIMARY KEY,
+    user_id             BIGINT NOT NULL,
+    expires_at          TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON "revoked_tokens"(expires_at);
+
+COMMENT ON TABLE "revoked_tokens" IS '已吊销的访问令牌（过期后可清理）';
+COMMENT ON COLUMN "revoked_tokens".jti IS '令牌唯一标识（JWT jti）';
+COMMENT ON COLUMN "revoked_tokens".user_id IS '所属用户ID';
+COMMENT ON COLUMN "revoked_tokens".expires_at IS '令牌原过期时间';
+
+CREATE TABLE "user_token_revocations" (
+    user_id             BIGINT PRIMARY KEY,
+    revoked_before      TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE "user_token_revocations" IS '用户级令牌吊销（修改密码、停用、强制下线）';
+COMMENT ON COLUMN "user_token_revocations".user_id IS '用户ID';
+COMMENT ON COLUMN "user_token_revocations".revoked_before IS '在此时间之前签发的令牌全部失效';
@@ -0,0 +1,60 @@
+-- 角色权限（RBAC）
+CREATE TABLE "roles" (
+    id                  BIGINT PRIMARY KEY,
+    code                VARCHAR(50) NOT NULL,
+    name                VARCHAR(50) NOT NULL,
+    description         VARCHAR(255) NOT NULL DEFAULT '',
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX uk_roles_code ON "roles"(code);
+
+COMMENT ON TABLE "roles" IS '角色表';
+COMMENT ON COLUMN "roles".code IS '角色编码（写入令牌的 userRole）';
+COMMENT ON COLUMN "roles".name IS '角色名称';
+
+CREATE TABLE "permissions" (
+    id                  BIGINT PRIMARY KEY,
+    code                VARCHAR(100) NOT NULL,
+    name                VARCHAR(50) NOT NULL,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX uk_permissions_code ON "permissions"(code);
+
+COMMENT ON TABLE "permissions" IS '权限表';
+COMMENT ON COLUMN "permissions".code IS '权限编码，格式为 资源:操作，如 user:write';
+
+CREATE TABLE "role_permissions" (
+    role_id             BIGINT NOT NULL,
+    permission_id       BIGINT NOT NULL,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+COMMENT ON TABLE "role_permissions" IS '角色-权限关联表';
+
+CREATE TABLE "user_roles" (
+    user_id             BIGINT NOT NULL,
+    role_id             BIGINT NOT NULL,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE INDEX idx_user_roles_role_id ON "user_roles"(role_id);
+
+COMMENT ON TABLE "user_roles" IS '用户-角色关联表（无记录的用户视为普通用户）';
+
+-- 内置角色与权限
+INSERT INTO "roles" (id, code, name, description) VALUES
+    (1, 'admin', '管理员', '拥有全部管理权限'),
+    (2, 'user', '普通用户', '默认角色');
+
+INSERT INTO "permissions" (id, code, name) VALUES
+    (1, 'user:read', '查看用户'),
+    (2, 'user:write', '管理用户'),
+    (3, 'role:read', '查看角色'),
+    (4, 'role:write', '分配角色');
+
+INSERT INTO "role_permissions" (role_id, permission_id) VALUES
+    (1, 1), (1, 2), (1, 3), (1, 4);
@@ -0,0 +1,2 @@
+DELETE FROM "role_permissions" WHERE permission_id IN (5, 6);
+DELETE FROM "permissions" WHERE id IN (5, 6);
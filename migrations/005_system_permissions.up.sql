@@ -0,0 +1,7 @@
+-- 系统运维权限（日志级别等运行时设置）
+INSERT INTO "permissions" (id, code, name) VALUES
+    (5, 'system:read', '查看系统设置'),
+    (6, 'system:write', '修改系统设置');
+
+INSERT INTO "role_permissions" (role_id, permission_id) VALUES
+    (1, 5), (1, 6);
@@ -0,0 +1,15 @@
+-- 限流令牌桶（多实例共享限流状态）
+CREATE TABLE "rate_limit_buckets" (
+    key                 VARCHAR(255) PRIMARY KEY,
+    tokens              DOUBLE PRECISION NOT NULL,
+    updated_at          TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at          TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_buckets_expires_at ON "rate_limit_buckets"(expires_at);
+
+COMMENT ON TABLE "rate_limit_buckets" IS '限流令牌桶（补满后可清理）';
+COMMENT ON COLUMN "rate_limit_buckets".key IS '限流键，如 IP 或用户';
+COMMENT ON COLUMN "rate_limit_buckets".tokens IS '上次更新时的剩余令牌数（可含小数）';
+COMMENT ON COLUMN "rate_limit_buckets".updated_at IS '上次更新时间（数据库时钟）';
+COMMENT ON COLUMN "rate_limit_buckets".expires_at IS '令牌桶补满的时间';
@@ -0,0 +1,38 @@
+-- 登录失败计数与临时锁定（按手机号和 IP）
+CREATE TABLE "login_throttles" (
+    subject             VARCHAR(128) PRIMARY KEY,
+    failures            INT NOT NULL DEFAULT 0,
+    last_failed_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until        TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_login_throttles_last_failed_at ON "login_throttles"(last_failed_at);
+
+COMMENT ON TABLE "login_throttles" IS '登录失败计数（登录成功或管理员解锁后删除）';
+COMMENT ON COLUMN "login_throttles".subject IS '计数对象，如 phone:13800000000、ip:10.0.0.1';
+COMMENT ON COLUMN "login_throttles".failures IS '连续失败次数（锁定后清零）';
+COMMENT ON COLUMN "login_throttles".last_failed_at IS '最近一次失败时间';
+COMMENT ON COLUMN "login_throttles".locked_until IS '锁定截止时间';
+
+CREATE TABLE "login_lockout_events" (
+    id                  BIGINT PRIMARY KEY,
+    subject             VARCHAR(128) NOT NULL,
+    action              VARCHAR(16) NOT NULL,
+    failures            INT NOT NULL DEFAULT 0,
+    locked_until        TIMESTAMP WITH TIME ZONE,
+    ip                  VARCHAR(64) NOT NULL DEFAULT '',
+    operator_id         BIGINT,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_login_lockout_events_subject ON "login_lockout_events"(subject, created_at);
+CREATE INDEX idx_login_lockout_events_created_at ON "login_lockout_events"(created_at);
+
+COMMENT ON TABLE "login_lockout_events" IS '登录锁定与解锁记录';
+COMMENT ON COLUMN "login_lockout_events".id IS '记录ID（Snowflake生成）';
+COMMENT ON COLUMN "login_lockout_events".subject IS '锁定对象，如 phone:13800000000、ip:10.0.0.1';
+COMMENT ON COLUMN "login_lockout_events".action IS 'locked/unlocked';
+COMMENT ON COLUMN "login_lockout_events".failures IS '锁定前的连续失败次数';
+COMMENT ON COLUMN "login_lockout_events".locked_until IS '锁定截止时间';
+COMMENT ON COLUMN "login_lockout_events".ip IS '触发锁定的请求 IP';
+COMMENT ON COLUMN "login_lockout_events".operator_id IS '执行解锁的管理员ID';
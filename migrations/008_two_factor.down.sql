@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "two_factor_challenges";
+DROP TABLE IF EXISTS "user_recovery_codes";
+DROP TABLE IF EXISTS "user_totp";
+ALTER TABLE "roles" DROP COLUMN IF EXISTS require_two_factor;
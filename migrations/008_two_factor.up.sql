@@ -0,0 +1,50 @@
+-- TOTP 两步验证
+ALTER TABLE "roles" ADD COLUMN require_two_factor BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN "roles".require_two_factor IS '该角色的用户登录时必须完成两步验证';
+
+CREATE TABLE "user_totp" (
+    user_id             BIGINT PRIMARY KEY,
+    secret              VARCHAR(255) NOT NULL,
+    enabled_at          TIMESTAMP WITH TIME ZONE,
+    last_used_step      BIGINT NOT NULL DEFAULT 0,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE "user_totp" IS '用户 TOTP 密钥';
+COMMENT ON COLUMN "user_totp".secret IS 'TOTP 密钥（配置 TOTP_ENCRYPTION_KEY 时加密保存）';
+COMMENT ON COLUMN "user_totp".enabled_at IS '启用时间（为空表示已生成、尚未验证）';
+COMMENT ON COLUMN "user_totp".last_used_step IS '最近一次验证通过的时间步（拒绝重复使用同一验证码）';
+
+CREATE TABLE "user_recovery_codes" (
+    id                  BIGINT PRIMARY KEY,
+    user_id             BIGINT NOT NULL,
+    code_hash           VARCHAR(64) NOT NULL,
+    used_at             TIMESTAMP WITH TIME ZONE,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX uk_user_recovery_codes_user_code ON "user_recovery_codes"(user_id, code_hash);
+
+COMMENT ON TABLE "user_recovery_codes" IS '两步验证恢复码（一次性）';
+COMMENT ON COLUMN "user_recovery_codes".code_hash IS '恢复码SHA-256哈希（不保存明文）';
+COMMENT ON COLUMN "user_recovery_codes".used_at IS '使用时间';
+
+CREATE TABLE "two_factor_challenges" (
+    id                  BIGINT PRIMARY KEY,
+    user_id             BIGINT NOT NULL,
+    token_hash          VARCHAR(64) NOT NULL,
+    attempts            INT NOT NULL DEFAULT 0,
+    expires_at          TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at             TIMESTAMP WITH TIME ZONE,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX uk_two_factor_challenges_token_hash ON "two_factor_challenges"(token_hash);
+CREATE INDEX idx_two_factor_challenges_expires_at ON "two_factor_challenges"(expires_at);
+
+COMMENT ON TABLE "two_factor_challenges" IS '登录第二步挑战（密码验证通过后签发，过期后可清理）';
+COMMENT ON COLUMN "two_factor_challenges".token_hash IS '挑战令牌SHA-256哈希（不保存明文）';
+COMMENT ON COLUMN "two_factor_challenges".attempts IS '已失败的验证次数';
+COMMENT ON COLUMN "two_factor_challenges".used_at IS '使用时间（一次性）';
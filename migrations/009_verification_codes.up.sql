@@ -0,0 +1,22 @@
+-- 短信验证码
+CREATE TABLE "verification_codes" (
+    id                  BIGINT PRIMARY KEY,
+    phone               VARCHAR(20) NOT NULL,
+    purpose             VARCHAR(32) NOT NULL,
+    code_hash           VARCHAR(64) NOT NULL,
+    attempts            INT NOT NULL DEFAULT 0,
+    ip                  VARCHAR(45) NOT NULL DEFAULT '',
+    expires_at          TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at             TIMESTAMP WITH TIME ZONE,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_verification_codes_phone_purpose ON "verification_codes"(phone, purpose, created_at DESC);
+CREATE INDEX idx_verification_codes_phone_created_at ON "verification_codes"(phone, created_at);
+
+COMMENT ON TABLE "verification_codes" IS '短信验证码（仅保存哈希值，过期后可清理）';
+COMMENT ON COLUMN "verification_codes".purpose IS '用途：register/login/reset_password/change_phone';
+COMMENT ON COLUMN "verification_codes".code_hash IS '验证码SHA-256哈希（绑定手机号和用途）';
+COMMENT ON COLUMN "verification_codes".attempts IS '已失败的校验次数';
+COMMENT ON COLUMN "verification_codes".ip IS '请求发送的客户端IP';
+COMMENT ON COLUMN "verification_codes".used_at IS '使用时间（一次性）';
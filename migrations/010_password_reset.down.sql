@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "password_reset_tokens";
+DROP INDEX IF EXISTS uk_users_email;
+ALTER TABLE "users" DROP COLUMN IF EXISTS email;
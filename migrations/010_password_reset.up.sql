@@ -0,0 +1,25 @@
+-- 用户邮箱（可选，用于找回密码）
+ALTER TABLE "users" ADD COLUMN email VARCHAR(254);
+
+CREATE UNIQUE INDEX uk_users_email ON "users"(lower(email)) WHERE email IS NOT NULL AND deleted_at IS null;
+
+COMMENT ON COLUMN "users".email IS '邮箱（可选，非删除状态下全局唯一，忽略大小写）';
+
+-- 邮件找回密码的重置令牌
+CREATE TABLE "password_reset_tokens" (
+    id                  BIGINT PRIMARY KEY,
+    user_id             BIGINT NOT NULL,
+    token_hash          VARCHAR(64) NOT NULL,
+    ip                  VARCHAR(45) NOT NULL DEFAULT '',
+    expires_at          TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at             TIMESTAMP WITH TIME ZONE,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX uk_password_reset_tokens_token_hash ON "password_reset_tokens"(token_hash);
+CREATE INDEX idx_password_reset_tokens_user_id ON "password_reset_tokens"(user_id, created_at DESC);
+
+COMMENT ON TABLE "password_reset_tokens" IS '密码重置令牌（仅保存哈希值，过期后可清理）';
+COMMENT ON COLUMN "password_reset_tokens".token_hash IS '令牌SHA-256哈希';
+COMMENT ON COLUMN "password_reset_tokens".ip IS '请求重置的客户端IP';
+COMMENT ON COLUMN "password_reset_tokens".used_at IS '使用或作废时间（一次性）';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "password_history";
+ALTER TABLE "users" DROP COLUMN IF EXISTS password_changed_at;
@@ -0,0 +1,16 @@
+-- 密码最长使用期限：记录最近一次设置密码的时间（已有用户从迁移时开始计算）
+ALTER TABLE "users" ADD COLUMN password_changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+COMMENT ON COLUMN "users".password_changed_at IS '最近一次设置密码的时间';
+
+-- 历史密码，禁止重复使用最近 PASSWORD_HISTORY 次的密码
+CREATE TABLE "password_history" (
+    id                  BIGINT PRIMARY KEY,
+    user_id             BIGINT NOT NULL,
+    password_hash       VARCHAR(255) NOT NULL,
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_password_history_user_id ON "password_history"(user_id, created_at DESC);
+
+COMMENT ON TABLE "password_history" IS '历史密码（bcrypt哈希，每个用户只保留最近 PASSWORD_HISTORY 条）';
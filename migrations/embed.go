@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned SQL migrations into the binary.
+//
+// 文件命名：NNN_name.up.sql / NNN_name.down.sql，NNN 为递增版本号。
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
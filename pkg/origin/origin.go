@@ -0,0 +1,133 @@
+package origin
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Matcher 匹配浏览器请求的 Origin，配置校验和 CORS 中间件共用同一套解析规则。每项可以是：
+//   - 精确来源，如 https://app.example.com
+//   - 子域名通配，如 https://*.example.com（匹配任意层级子域名，不含 example.com 本身）
+//   - 以 ^ 开头的正则，整体匹配来源，如 ^https://pr-\d+\.preview\.example\.com$
+//   - *，允许任意来源
+//
+// 来源的 scheme 和主机名不区分大小写，正则同样按不区分大小写匹配。
+type Matcher struct {
+	any       bool
+	exact     map[string]bool
+	wildcards []wildcard
+	patterns  []*regexp.Regexp
+}
+
+// wildcard https://*.example.com 形式的来源，suffix 为 .example.com（含端口）
+type wildcard struct {
+	scheme string
+	suffix string
+}
+
+// NewMatcher 解析允许的来源列表，任一项无效时返回错误
+func NewMatcher(origins []string) (*Matcher, error) {
+	m := &Matcher{exact: make(map[string]bool)}
+	for _, origin := range origins {
+		switch {
+		case origin == "*":
+			m.any = true
+		case strings.HasPrefix(origin, "^"):
+			re, err := compilePattern(origin)
+			if err != nil {
+				return nil, err
+			}
+			m.patterns = append(m.patterns, re)
+		case strings.Contains(origin, "*"):
+			w, err := parseWildcard(origin)
+			if err != nil {
+				return nil, err
+			}
+			m.wildcards = append(m.wildcards, w)
+		default:
+			if err := checkOrigin(origin); err != nil {
+				return nil, err
+			}
+			m.exact[strings.ToLower(origin)] = true
+		}
+	}
+	return m, nil
+}
+
+// Validate 校验单个来源的格式
+func Validate(origin string) error {
+	_, err := NewMatcher([]string{origin})
+	return err
+}
+
+// Any reports whether every origin is allowed.
+func (m *Matcher) Any() bool {
+	return m.any
+}
+
+// Allowed reports whether the request origin matches any entry.
+func (m *Matcher) Allowed(origin string) bool {
+	if m.any {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if m.exact[origin] {
+		return true
+	}
+	for _, w := range m.wildcards {
+		if w.matches(origin) {
+			return true
+		}
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches 通配部分至少包含一级子域名，且不能包含路径、端口或用户信息
+func (w wildcard) matches(origin string) bool {
+	prefix := w.scheme + "://"
+	if !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, w.suffix) ||
+		len(origin) <= len(prefix)+len(w.suffix) {
+		return false
+	}
+	sub := origin[len(prefix) : len(origin)-len(w.suffix)]
+	return !strings.ContainsAny(sub, "/:@")
+}
+
+// compilePattern 正则总是整体匹配，避免 ^https://app\.example\.com 匹配到 app.example.com.evil.io
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	body := strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$")
+	re, err := regexp.Compile("(?i)^(?:" + body + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid origin pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+func parseWildcard(origin string) (wildcard, error) {
+	if err := checkOrigin(strings.Replace(origin, "*.", "wildcard.", 1)); err != nil ||
+		!strings.Contains(origin, "://*.") {
+		return wildcard{}, fmt.Errorf("invalid wildcard origin %q: want scheme://*.domain", origin)
+	}
+	u, _ := url.Parse(strings.Replace(origin, "*.", "wildcard.", 1))
+	return wildcard{
+		scheme: strings.ToLower(u.Scheme),
+		suffix: strings.ToLower(strings.TrimPrefix(u.Host, "wildcard")),
+	}, nil
+}
+
+// checkOrigin 精确来源只能包含 http(s) scheme、主机名和端口
+func checkOrigin(origin string) error {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		strings.Contains(u.Host, "*") || u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("invalid origin %q: want scheme://host[:port]", origin)
+	}
+	return nil
+}
@@ -19,6 +19,12 @@ func BcryptCheck(password, hash string) bool {
 	return err == nil
 }
 
+// IsBcryptHash 判断字符串是否已经是 bcrypt 哈希值
+func IsBcryptHash(s string) bool {
+	_, err := bcrypt.Cost([]byte(s))
+	return err == nil
+}
+
 func MD5V(str []byte, b ...byte) string {
 	h := md5.New()
 	h.Write(str)
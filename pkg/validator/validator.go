@@ -135,67 +135,3 @@ func ContainsAll(s string, substrs []string) bool {
 	}
 	return true
 }
-
-// IsStrongPassword 检查是否为强密码（至少8位，包含大小写字母、数字）
-func IsStrongPassword(password string) bool {
-	if len(password) < 8 {
-		return false
-	}
-
-	hasUpper := false
-	hasLower := false
-	hasDigit := false
-
-	for _, r := range password {
-		switch {
-		case r >= 'A' && r <= 'Z':
-			hasUpper = true
-		case r >= 'a' && r <= 'z':
-			hasLower = true
-		case r >= '0' && r <= '9':
-			hasDigit = true
-		}
-	}
-
-	return hasUpper && hasLower && hasDigit
-}
-
-// ValidatePassword 验证密码强度（可自定义规则）
-func ValidatePassword(password string, minLength int, requireUpper, requireLower, requireDigit, requireSpecial bool) bool {
-	if len(password) < minLength {
-		return false
-	}
-
-	hasUpper := false
-	hasLower := false
-	hasDigit := false
-	hasSpecial := false
-
-	for _, r := range password {
-		switch {
-		case r >= 'A' && r <= 'Z':
-			hasUpper = true
-		case r >= 'a' && r <= 'z':
-			hasLower = true
-		case r >= '0' && r <= '9':
-			hasDigit = true
-		default:
-			hasSpecial = true
-		}
-	}
-
-	if requireUpper && !hasUpper {
-		return false
-	}
-	if requireLower && !hasLower {
-		return false
-	}
-	if requireDigit && !hasDigit {
-		return false
-	}
-	if requireSpecial && !hasSpecial {
-		return false
-	}
-
-	return true
-}